@@ -3,9 +3,11 @@ package ipmi
 import (
 	"context"
 	"fmt"
+	"log/slog"
 	"sync"
 	"time"
 
+	"github.com/bougou/go-ipmi/open"
 	"golang.org/x/net/proxy"
 )
 
@@ -38,6 +40,12 @@ type Client struct {
 	requesterAddr uint8
 	requesterLUN  uint8
 
+	// the controller behind the BMC which the requests are bridged to, see WithTarget and WithTransit
+	targetChannel  uint8
+	targetAddr     uint8
+	transitChannel uint8
+	transitAddr    uint8
+
 	openipmi *openipmi
 	session  *session
 
@@ -54,8 +62,34 @@ type Client struct {
 	retryCount    int
 	retryInterval time.Duration
 
+	// retryPolicy decides which failed exchanges are retried, see WithRetryPolicy
+	retryPolicy *RetryPolicy
+
+	// interceptors are called around each exchange, see WithInterceptor
+	interceptors []Interceptor
+
+	// sdrCache caches the SDR records, see WithSDRCache
+	sdrCache *sdrCache
+
+	// logger receives the debug messages and the structured logs, see WithLogger
+	logger *slog.Logger
+
+	// maxInFlight limits the requests in flight on the lanplus session, see WithMaxInFlight
+	maxInFlight int
+	inFlight    *inFlight
+
 	l sync.Mutex
 
+	// autoReconnect re-establishes the lan/lanplus session once it is lost, see WithAutoReconnect
+	autoReconnect bool
+	onReconnect   ReconnectFunc
+	// established is set after the session is activated by Connect
+	established bool
+	// sessionL is held for writing while the session is re-established, and for reading by the other exchanges.
+	sessionL sync.RWMutex
+	// sessionGen is increased each time the session is re-established
+	sessionGen uint64
+
 	// closedCh is closed when Client.Close() is called.
 	// used to notify other goroutines that Client is closed.
 	closedCh chan bool
@@ -70,8 +104,9 @@ func NewOpenClient() (*Client, error) {
 		bufferSize: DefaultBufferSize,
 
 		openipmi: &openipmi{
-			myAddr:     myAddr,
-			targetAddr: myAddr,
+			myAddr:         myAddr,
+			sendRequest:    open.SendRequest,
+			receiveMessage: open.ReceiveMessage,
 		},
 	}, nil
 }
@@ -189,6 +224,20 @@ func (c *Client) WithCipherSuiteID(cipherSuiteID CipherSuiteID) *Client {
 	return c
 }
 
+// WithAutoReconnect makes the lan/lanplus client re-establish the session when it is lost,
+// like expired for inactivity, closed by another administrator, or discarded by a BMC reset.
+//
+// A session is considered lost when a request times out or is answered outside of the session
+// (ErrSessionInvalid), and the session does not answer Get Session Info either.
+// The client then activates a new session with the same cipher suite and privilege level,
+// and retries the request once. If that fails, the next request tries again.
+// onReconnect, if not nil, is called after each attempt to re-establish the session.
+func (c *Client) WithAutoReconnect(onReconnect ReconnectFunc) *Client {
+	c.autoReconnect = true
+	c.onReconnect = onReconnect
+	return c
+}
+
 // WithMaxPrivilegeLevel sets a specified session privilege level to use.
 func (c *Client) WithMaxPrivilegeLevel(privilegeLevel PrivilegeLevel) *Client {
 	c.maxPrivilegeLevel = privilegeLevel
@@ -204,6 +253,29 @@ func (c *Client) WithRequesterAddr(requesterAddr, requesterLUN uint8) {
 	c.requesterLUN = requesterLUN
 }
 
+// WithTarget bridges the requests to the management controller at targetAddr on targetChannel
+// behind the BMC (like the -b and -t options of ipmitool). The requests are wrapped in Send Message
+// commands and the nested responses are unwrapped. It takes effect for the lan, lanplus and open interfaces,
+// for the open interface the requests are sent to the IPMB address of the target by the driver.
+//
+// The targetAddr 0 or BMC_SA disables bridging.
+func (c *Client) WithTarget(targetChannel uint8, targetAddr uint8) *Client {
+	c.targetChannel = targetChannel
+	c.targetAddr = targetAddr
+	return c
+}
+
+// WithTransit sets the transit controller at transitAddr on transitChannel for double bridging
+// (like the -B and -T options of ipmitool), the requests are bridged by the BMC to the transit controller,
+// and then by the transit controller to the target set by WithTarget.
+//
+// The transitAddr 0 or BMC_SA means single bridging.
+func (c *Client) WithTransit(transitChannel uint8, transitAddr uint8) *Client {
+	c.transitChannel = transitChannel
+	c.transitAddr = transitAddr
+	return c
+}
+
 func (c *Client) SessionPrivilegeLevel() PrivilegeLevel {
 	return c.maxPrivilegeLevel
 }
@@ -256,7 +328,31 @@ func (c *Client) Close(ctx context.Context) error {
 	return nil
 }
 
+// Exchange sends the request and unpacks the response, the failed exchange is retried
+// according to the RetryPolicy set by WithRetryPolicy.
 func (c *Client) Exchange(ctx context.Context, request Request, response Response) error {
+	if policy := c.retryPolicy; policy != nil && isRetryable(request) {
+		return c.exchangeRetry(ctx, policy, request, response)
+	}
+	return c.exchange(ctx, 1, request, response)
+}
+
+// exchange makes one attempt of the exchange, through the interceptors if any.
+func (c *Client) exchange(ctx context.Context, attempt int, request Request, response Response) (err error) {
+	if c.logger != nil {
+		start := time.Now()
+		defer func() {
+			c.logExchange(ctx, attempt, request, start, err)
+		}()
+	}
+
+	if len(c.interceptors) > 0 {
+		return c.intercept(ctx, attempt, request, response, c.exchangeInterface)
+	}
+	return c.exchangeInterface(ctx, request, response)
+}
+
+func (c *Client) exchangeInterface(ctx context.Context, request Request, response Response) error {
 	switch c.Interface {
 	case "", InterfaceOpen:
 		return c.exchangeOpen(ctx, request, response)
@@ -265,7 +361,7 @@ func (c *Client) Exchange(ctx context.Context, request Request, response Respons
 		return c.exchangeTool(ctx, request, response)
 
 	case InterfaceLan, InterfaceLanplus:
-		return c.exchangeLAN(ctx, request, response)
+		return c.exchangeSession(ctx, request, response)
 
 	}
 
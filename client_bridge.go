@@ -0,0 +1,227 @@
+package ipmi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// errBridgedResponsePending means the BMC answered the Send Message request without the
+// bridged response, which is sent later in a separate message.
+var errBridgedResponsePending = errors.New("bridged response pending")
+
+// ipmbBridge describes the path of a request bridged to a management controller behind the BMC.
+//
+// For single bridging, the request is wrapped in a Send Message request to the BMC,
+// which forwards it to the target on the target channel.
+//
+// For double bridging, the Send Message request for the target is wrapped in another
+// Send Message request to the BMC, which forwards it to the transit controller on the transit channel.
+//
+// see 6.13 BMC Message Bridging, 22.7 Send Message Command
+type ipmbBridge struct {
+	targetChannel  uint8
+	targetAddr     uint8
+	transitChannel uint8
+	transitAddr    uint8
+}
+
+func (b *ipmbBridge) double() bool {
+	return b.transitAddr != 0 && b.transitAddr != BMC_SA
+}
+
+// levels returns the count of the Send Message requests wrapping the bridged request.
+func (b *ipmbBridge) levels() int {
+	if b.double() {
+		return 2
+	}
+	return 1
+}
+
+// ipmbBridgeKeyType is a custom type for the context key to avoid collisions
+type ipmbBridgeKeyType string
+
+// ipmbBridgeKey is the key used to store the ipmbBridge of the request being exchanged
+const ipmbBridgeKey ipmbBridgeKeyType = "ipmbBridge"
+
+func withIPMBBridge(ctx context.Context, bridge *ipmbBridge) context.Context {
+	return context.WithValue(ctx, ipmbBridgeKey, bridge)
+}
+
+func getIPMBBridge(ctx context.Context) *ipmbBridge {
+	bridge, _ := ctx.Value(ipmbBridgeKey).(*ipmbBridge)
+	return bridge
+}
+
+// bridgeFor returns how the request is bridged, nil means the request is sent to the BMC itself.
+// The target and transit set by WithTarget and WithTransit are overridden by the CommandContext.
+func (c *Client) bridgeFor(ctx context.Context, request Request) *ipmbBridge {
+	bridge := &ipmbBridge{
+		targetChannel:  c.targetChannel,
+		targetAddr:     c.targetAddr,
+		transitChannel: c.transitChannel,
+		transitAddr:    c.transitAddr,
+	}
+
+	if commandContext := GetCommandContext(ctx); commandContext != nil {
+		if commandContext.targetChannel != nil {
+			bridge.targetChannel = *commandContext.targetChannel
+		}
+		if commandContext.targetAddr != nil {
+			bridge.targetAddr = *commandContext.targetAddr
+		}
+		if commandContext.transitChannel != nil {
+			bridge.transitChannel = *commandContext.transitChannel
+		}
+		if commandContext.transitAddr != nil {
+			bridge.transitAddr = *commandContext.transitAddr
+		}
+	}
+
+	if bridge.targetAddr == 0 || bridge.targetAddr == BMC_SA {
+		return nil
+	}
+	if !isBridgeable(request.Command()) {
+		return nil
+	}
+	return bridge
+}
+
+// isBridgeable reports whether the command can be bridged to another controller.
+// The session and payload commands are always handled by the BMC the client is connected to.
+func isBridgeable(cmd Command) bool {
+	switch cmd {
+	case
+		CommandGetChannelAuthCapabilities,
+		CommandGetSessionChallenge,
+		CommandActivateSession,
+		CommandSetSessionPrivilegeLevel,
+		CommandCloseSession,
+		CommandGetSessionInfo,
+		CommandGetChannelCipherSuites,
+		CommandSendMessage,
+		CommandActivatePayload,
+		CommandDeactivatePayload,
+		CommandGetPayloadActivationStatus:
+		return false
+	}
+	return true
+}
+
+// wrapSendMessage wraps the IPMI request message into a Send Message request with response tracking,
+// which asks the responder to forward the message to the channel.
+// The caller must hold the lock.
+func (c *Client) wrapSendMessage(ipmiReq *IPMIRequest, channel uint8, responderAddr uint8, requesterAddr uint8, requesterLUN uint8) *IPMIRequest {
+	sendMessage := &SendMessageRequest{
+		TrackMask:     0x01, // Track Request
+		ChannelNumber: channel,
+		MessageData:   ipmiReq.Pack(),
+	}
+
+	out := &IPMIRequest{
+		ResponderAddr:     responderAddr,
+		NetFn:             CommandSendMessage.NetFn,
+		ResponderLUN:      uint8(IPMB_LUN_BMC),
+		RequesterAddr:     requesterAddr,
+		RequesterSequence: c.nextIPMISeq(),
+		RequesterLUN:      requesterLUN,
+		Command:           CommandSendMessage.ID,
+		CommandData:       sendMessage.Pack(),
+	}
+	out.ComputeChecksum()
+	return out
+}
+
+// unwrapBridgedResponse returns the response of the bridged request embedded in the Send Message responses.
+// Both the checksums and the completion code of each Send Message response are checked.
+//
+// Some BMCs return the bridged response directly instead of embedding it,
+// then the response is returned as is.
+func (c *Client) unwrapBridgedResponse(ipmiRes *IPMIResponse, bridge *ipmbBridge) (*IPMIResponse, error) {
+	for i := 0; i < bridge.levels(); i++ {
+		if ipmiRes.NetFn != NetFnAppResponse || ipmiRes.Command != CommandSendMessage.ID {
+			break
+		}
+
+		if !ipmiRes.ValidChecksum() {
+			return nil, fmt.Errorf("invalid checksum of Send Message response (level %d)", i)
+		}
+
+		ccode := ipmiRes.CompletionCode
+		if ccode != 0x00 {
+			return nil, &ResponseError{
+				completionCode: CompletionCode(ccode),
+				description:    fmt.Sprintf("Send Message CompletionCode (%#02x) is not normal: %s", ccode, StrCC(&SendMessageResponse{}, ccode)),
+			}
+		}
+
+		if len(ipmiRes.Data) == 0 {
+			return nil, errBridgedResponsePending
+		}
+
+		embedded := &IPMIResponse{}
+		if err := embedded.Unpack(ipmiRes.Data); err != nil {
+			return nil, fmt.Errorf("unpack bridged ipmiRes failed, err: %w", err)
+		}
+		c.Debug("<<<< Bridged IPMI Response", embedded)
+		ipmiRes = embedded
+	}
+
+	if !ipmiRes.ValidChecksum() {
+		return nil, fmt.Errorf("invalid checksum of bridged response")
+	}
+	return ipmiRes, nil
+}
+
+// ipmbMaxRequestDataSize is the max request data size of an IPMB message (32 bytes),
+// less the 7 bytes of the header, the command and the checksums.
+const ipmbMaxRequestDataSize = 25
+
+// lanMaxRequestDataSize is the max request data size of the lan and lanplus interfaces,
+// same as IPMI_LAN_MAX_REQUEST_SIZE of ipmitool (45 bytes less the 7 bytes of the IPMI message header and trailer).
+const lanMaxRequestDataSize = 38
+
+// isRequestLengthError reports whether the request is refused for the length of its data,
+// by the BMCs taking less request data than the max request size of the interface.
+func isRequestLengthError(err error) bool {
+	respErr, ok := isResponseError(err)
+	if !ok {
+		return false
+	}
+
+	switch respErr.CompletionCode() {
+	case CompletionCodeRequestDataLengthInvalid,
+		CompletionCodeRequestDataLengthLimitExceeded,
+		CompletionCodeCannotReturnRequestedDataBytes:
+		return true
+	}
+	return false
+}
+
+// maxRequestDataSize returns the max size of the request data which can be sent by the Client.
+//
+// It is the max request size of the lan and lanplus interfaces, and the size of an IPMB message
+// on the other interfaces (the default of ipmitool, the driver and the BMC may accept larger requests),
+// or if the request is bridged to a controller on IPMB, less the 8 bytes of the
+// Send Message request for each level of bridging, like ipmitool.
+func (c *Client) maxRequestDataSize(ctx context.Context, request Request) int {
+	var size int
+	var bridge *ipmbBridge
+
+	switch c.Interface {
+	case InterfaceLan, InterfaceLanplus:
+		size = lanMaxRequestDataSize
+		bridge = c.bridgeFor(ctx, request)
+	case InterfaceOpen:
+		size = ipmbMaxRequestDataSize
+		bridge = c.openBridge(ctx, request)
+	default:
+		size = ipmbMaxRequestDataSize
+		bridge = c.bridgeFor(ctx, request)
+	}
+
+	if bridge != nil {
+		size = ipmbMaxRequestDataSize - 8*bridge.levels()
+	}
+	return size
+}
@@ -0,0 +1,82 @@
+package ipmi
+
+import (
+	"context"
+	"testing"
+)
+
+func TestClient_MaxRequestDataSize(t *testing.T) {
+	newClient := func(intf Interface) *Client {
+		c, _ := NewClient("127.0.0.1", 623, "admin", "admin")
+		if intf == InterfaceOpen {
+			c, _ = NewOpenClient()
+		}
+		c.Interface = intf
+		return c
+	}
+
+	tests := []struct {
+		name     string
+		client   *Client
+		ctx      context.Context
+		wantSize int
+	}{
+		{
+			name:     "lanplus",
+			client:   newClient(InterfaceLanplus),
+			wantSize: 38,
+		},
+		{
+			name:     "lan",
+			client:   newClient(InterfaceLan),
+			wantSize: 38,
+		},
+		{
+			name:     "lanplus single bridge",
+			client:   newClient(InterfaceLanplus).WithTarget(0x00, 0x2c),
+			wantSize: 17,
+		},
+		{
+			name:     "lanplus double bridge",
+			client:   newClient(InterfaceLanplus).WithTarget(0x00, 0x2c).WithTransit(0x07, 0x82),
+			wantSize: 9,
+		},
+		{
+			name:     "open",
+			client:   newClient(InterfaceOpen),
+			wantSize: 25,
+		},
+		{
+			name:     "open target is the BMC",
+			client:   newClient(InterfaceOpen).WithTarget(0x00, BMC_SA),
+			wantSize: 25,
+		},
+		{
+			name:     "open single bridge",
+			client:   newClient(InterfaceOpen).WithTarget(0x00, 0x2c),
+			wantSize: 17,
+		},
+		{
+			name:     "open responder on ipmb",
+			client:   newClient(InterfaceOpen),
+			ctx:      WithCommandContext(context.Background(), (&CommandContext{}).WithResponderAddr(0x2c)),
+			wantSize: 17,
+		},
+		{
+			name:     "open double bridge",
+			client:   newClient(InterfaceOpen).WithTarget(0x00, 0x2c).WithTransit(0x07, 0x82),
+			wantSize: 9,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := tt.ctx
+			if ctx == nil {
+				ctx = context.Background()
+			}
+			if got := tt.client.maxRequestDataSize(ctx, &AddSDRRequest{}); got != tt.wantSize {
+				t.Errorf("maxRequestDataSize() = %d, want %d", got, tt.wantSize)
+			}
+		})
+	}
+}
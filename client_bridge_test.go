@@ -0,0 +1,141 @@
+package ipmi_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/bougou/go-ipmi"
+	"github.com/bougou/go-ipmi/ipmitest"
+)
+
+func TestClient_Bridge(t *testing.T) {
+	const (
+		transitChannel uint8 = 0x07
+		transitAddr    uint8 = 0x82
+		targetChannel  uint8 = 0x00
+		targetAddr     uint8 = 0x2c
+	)
+
+	// addTarget configures the controller reached by bridging, with fixtures different from the BMC
+	addTarget := func(parent *ipmitest.Server) {
+		target := parent.AddController(targetChannel, targetAddr)
+		target.Device.ProductID = 0x1234
+
+		sdr := fullSensorSDR(0x05, "Sat Temp")
+		sdr[5] = targetAddr // sensor owner
+		target.AddSDR(sdr)
+		target.SetSensor(0x05, ipmitest.Sensor{Reading: 55})
+		target.SetFRU(0, []byte{0x01, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0xff})
+	}
+
+	tests := []struct {
+		name     string
+		intf     ipmi.Interface
+		double   bool
+		separate bool
+	}{
+		{name: "single", intf: ipmi.InterfaceLanplus},
+		{name: "double", intf: ipmi.InterfaceLanplus, double: true},
+		{name: "separate response", intf: ipmi.InterfaceLanplus, separate: true},
+		{name: "separate response lan", intf: ipmi.InterfaceLan, separate: true},
+		{name: "double separate response", intf: ipmi.InterfaceLanplus, double: true, separate: true},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestServer(t, func(s *ipmitest.Server) {
+				s.BridgeSeparateResponse = tt.separate
+				if tt.double {
+					addTarget(s.AddController(transitChannel, transitAddr))
+				} else {
+					addTarget(s)
+				}
+			})
+
+			ctx := context.Background()
+			c := newTestClient(t, s, tt.intf, testPassword).WithTarget(targetChannel, targetAddr)
+			if tt.double {
+				c.WithTransit(transitChannel, transitAddr)
+			}
+			if err := c.Connect(ctx); err != nil {
+				t.Fatalf("Connect failed, err: %s", err)
+			}
+			defer c.Close(ctx)
+
+			res, err := c.GetDeviceID(ctx)
+			if err != nil {
+				t.Fatalf("GetDeviceID failed, err: %s", err)
+			}
+			if res.ProductID != 0x1234 {
+				t.Errorf("GetDeviceID returned product ID %#04x, want %#04x", res.ProductID, 0x1234)
+			}
+
+			sensors, err := c.GetSensors(ctx)
+			if err != nil {
+				t.Fatalf("GetSensors failed, err: %s", err)
+			}
+			if len(sensors) != 1 || sensors[0].Name != "Sat Temp" || sensors[0].Value != 55 {
+				t.Errorf("GetSensors returned unexpected sensors %v", sensors)
+			}
+
+			sdrs, err := c.GetDeviceSDRs(ctx)
+			if err != nil {
+				t.Fatalf("GetDeviceSDRs failed, err: %s", err)
+			}
+			if len(sdrs) != 1 || sdrs[0].SensorName() != "Sat Temp" {
+				t.Errorf("GetDeviceSDRs returned unexpected records %v", sdrs)
+			}
+
+			if _, err := c.GetFRU(ctx, 0, "Builtin FRU"); err != nil {
+				t.Errorf("GetFRU failed, err: %s", err)
+			}
+		})
+	}
+}
+
+func TestClient_BridgeCommandContext(t *testing.T) {
+	s := newTestServer(t, func(s *ipmitest.Server) {
+		s.AddController(0x00, 0x2c).Device.ProductID = 0x1234
+	})
+
+	ctx := context.Background()
+	c := newTestClient(t, s, ipmi.InterfaceLanplus, testPassword)
+	if err := c.Connect(ctx); err != nil {
+		t.Fatalf("Connect failed, err: %s", err)
+	}
+	defer c.Close(ctx)
+
+	tests := []struct {
+		name          string
+		targetAddr    uint8
+		wantProductID uint16
+		wantErr       bool
+	}{
+		{name: "bmc", targetAddr: ipmi.BMC_SA, wantProductID: 0},
+		{name: "controller", targetAddr: 0x2c, wantProductID: 0x1234},
+		{name: "no controller", targetAddr: 0x2e, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			commandContext := &ipmi.CommandContext{}
+			commandContext.WithTargetChannel(0x00).WithTargetAddr(tt.targetAddr)
+
+			res, err := c.GetDeviceID(ipmi.WithCommandContext(ctx, commandContext))
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("GetDeviceID succeeded, want error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("GetDeviceID failed, err: %s", err)
+			}
+			if res.ProductID != tt.wantProductID {
+				t.Errorf("GetDeviceID returned product ID %#04x, want %#04x", res.ProductID, tt.wantProductID)
+			}
+		})
+	}
+}
@@ -0,0 +1,138 @@
+package ipmi
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// SELTimeUTCOffsetUnspecified is the SEL Time UTC Offset reported when the offset is not specified.
+const SELTimeUTCOffsetUnspecified int16 = 0x07ff
+
+// BMCClocks holds the clocks of the BMC read by GetBMCClocks, together with the host clock they are compared against.
+type BMCClocks struct {
+	// HostTime is the time of the host taken right before the clocks of the BMC are read.
+	HostTime time.Time
+
+	SELTime time.Time
+
+	// SDRRepoTimeSupported is false if the BMC rejects the Get SDR Repository Time command.
+	SDRRepoTimeSupported bool
+	SDRRepoTime          time.Time
+
+	// UTCOffsetSupported is false if the BMC rejects the Get SEL Time UTC Offset command.
+	UTCOffsetSupported bool
+
+	// UTCOffsetMinutes is the offset in minutes from UTC to the clocks of the BMC,
+	// SELTimeUTCOffsetUnspecified if not specified.
+	UTCOffsetMinutes int16
+}
+
+// utcOffset returns the offset of the clocks of the BMC from UTC, 0 if it is not supported or not specified.
+func (clocks *BMCClocks) utcOffset() time.Duration {
+	if !clocks.UTCOffsetSupported || clocks.UTCOffsetMinutes == SELTimeUTCOffsetUnspecified {
+		return 0
+	}
+	return time.Duration(clocks.UTCOffsetMinutes) * time.Minute
+}
+
+// SELDrift returns how far the SEL clock is ahead of the host clock (negative if behind),
+// the SEL Time UTC Offset is taken into account. The clocks of the BMC have a resolution of one second.
+func (clocks *BMCClocks) SELDrift() time.Duration {
+	return clocks.SELTime.Add(-clocks.utcOffset()).Sub(clocks.HostTime.Truncate(time.Second))
+}
+
+// SDRRepoDrift returns how far the SDR Repository clock is ahead of the host clock (negative if behind),
+// 0 if Get SDR Repository Time is not supported.
+// The SEL Time UTC Offset is taken into account, as both clocks are the timestamp clock of the BMC.
+func (clocks *BMCClocks) SDRRepoDrift() time.Duration {
+	if !clocks.SDRRepoTimeSupported {
+		return 0
+	}
+	return clocks.SDRRepoTime.Add(-clocks.utcOffset()).Sub(clocks.HostTime.Truncate(time.Second))
+}
+
+func (clocks *BMCClocks) Format() string {
+	sdrRepoTime := "not supported"
+	if clocks.SDRRepoTimeSupported {
+		sdrRepoTime = fmt.Sprintf("%s (drift %s)", clocks.SDRRepoTime.UTC().Format(timeFormat), clocks.SDRRepoDrift())
+	}
+
+	utcOffset := "not supported"
+	if clocks.UTCOffsetSupported {
+		if clocks.UTCOffsetMinutes == SELTimeUTCOffsetUnspecified {
+			utcOffset = "unspecified"
+		} else {
+			utcOffset = fmt.Sprintf("%d minutes", clocks.UTCOffsetMinutes)
+		}
+	}
+
+	return "" +
+		fmt.Sprintf("Host Time          : %s\n", clocks.HostTime.UTC().Format(timeFormat)) +
+		fmt.Sprintf("SEL Time           : %s (drift %s)\n", clocks.SELTime.UTC().Format(timeFormat), clocks.SELDrift()) +
+		fmt.Sprintf("SDR Repo Time      : %s\n", sdrRepoTime) +
+		fmt.Sprintf("SEL Time UTC Offset: %s\n", utcOffset)
+}
+
+// GetBMCClocks reads the SEL clock, the SDR Repository clock and the SEL Time UTC Offset of the BMC,
+// so their drift from the host clock can be checked.
+// The SDR Repository clock and the UTC Offset are optional, they are reported as not supported
+// if the BMC answers the commands with an error completion code.
+func (c *Client) GetBMCClocks(ctx context.Context) (*BMCClocks, error) {
+	clocks := &BMCClocks{
+		HostTime: time.Now(),
+	}
+
+	selTime, err := c.GetSELTime(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("GetSELTime failed, err: %w", err)
+	}
+	clocks.SELTime = selTime.Time
+
+	sdrRepoTime, err := c.GetSDRRepoTime(ctx)
+	if err != nil {
+		if _, ok := isResponseError(err); !ok {
+			return nil, fmt.Errorf("GetSDRRepoTime failed, err: %w", err)
+		}
+		c.Debugf("GetSDRRepoTime not supported, err: %s\n", err)
+	} else {
+		clocks.SDRRepoTimeSupported = true
+		clocks.SDRRepoTime = sdrRepoTime.Time
+	}
+
+	utcOffset, err := c.GetSELTimeUTCOffset(ctx)
+	if err != nil {
+		if _, ok := isResponseError(err); !ok {
+			return nil, fmt.Errorf("GetSELTimeUTCOffset failed, err: %w", err)
+		}
+		c.Debugf("GetSELTimeUTCOffset not supported, err: %s\n", err)
+	} else {
+		clocks.UTCOffsetSupported = true
+		clocks.UTCOffsetMinutes = utcOffset.MinutesOffset
+	}
+
+	return clocks, nil
+}
+
+// SyncBMCClocks sets both the SEL clock and the SDR Repository clock of the BMC to t,
+// shifted by the SEL Time UTC Offset if the BMC specifies one.
+// The SDR Repository clock is skipped if the BMC does not support Get SDR Repository Time.
+func (c *Client) SyncBMCClocks(ctx context.Context, t time.Time) error {
+	clocks, err := c.GetBMCClocks(ctx)
+	if err != nil {
+		return err
+	}
+
+	t = t.Add(clocks.utcOffset())
+
+	if _, err := c.SetSELTime(ctx, t); err != nil {
+		return fmt.Errorf("SetSELTime failed, err: %w", err)
+	}
+
+	if clocks.SDRRepoTimeSupported {
+		if _, err := c.SetSDRRepoTime(ctx, t); err != nil {
+			return fmt.Errorf("SetSDRRepoTime failed, err: %w", err)
+		}
+	}
+	return nil
+}
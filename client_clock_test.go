@@ -0,0 +1,70 @@
+package ipmi_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/bougou/go-ipmi"
+	"github.com/bougou/go-ipmi/ipmitest"
+)
+
+func TestClient_BMCClocks(t *testing.T) {
+	ctx := context.Background()
+
+	s := newTestServer(t, func(s *ipmitest.Server) {
+		s.SetClockDrift(2*time.Hour, -30*time.Minute)
+	})
+	c := newTestClient(t, s, ipmi.InterfaceLanplus, testPassword)
+	if err := c.Connect(ctx); err != nil {
+		t.Fatalf("Connect failed, err: %s", err)
+	}
+	defer c.Close(ctx)
+
+	checkDrift := func(name string, got time.Duration, want time.Duration) {
+		t.Helper()
+		if got < want-time.Second || got > want+time.Second {
+			t.Errorf("%s = %s, want %s", name, got, want)
+		}
+	}
+
+	clocks, err := c.GetBMCClocks(ctx)
+	if err != nil {
+		t.Fatalf("GetBMCClocks failed, err: %s", err)
+	}
+	if !clocks.SDRRepoTimeSupported || !clocks.UTCOffsetSupported || clocks.UTCOffsetMinutes != ipmi.SELTimeUTCOffsetUnspecified {
+		t.Fatalf("unexpected clocks %+v", clocks)
+	}
+	checkDrift("SELDrift", clocks.SELDrift(), 2*time.Hour)
+	checkDrift("SDRRepoDrift", clocks.SDRRepoDrift(), -30*time.Minute)
+
+	// the clocks are synced to the host clock shifted by the UTC offset
+	if _, err := c.SetSELTimeUTCOffset(ctx, 60); err != nil {
+		t.Fatalf("SetSELTimeUTCOffset failed, err: %s", err)
+	}
+	if err := c.SyncBMCClocks(ctx, time.Now()); err != nil {
+		t.Fatalf("SyncBMCClocks failed, err: %s", err)
+	}
+	clocks, err = c.GetBMCClocks(ctx)
+	if err != nil {
+		t.Fatalf("GetBMCClocks failed, err: %s", err)
+	}
+	checkDrift("SELDrift", clocks.SELDrift(), 0)
+	checkDrift("SDRRepoDrift", clocks.SDRRepoDrift(), 0)
+	checkDrift("SEL Time", clocks.SELTime.Sub(clocks.HostTime.Truncate(time.Second)), time.Hour)
+
+	// the SDR Repository clock is optional
+	s.Handle(ipmi.CommandGetSDRRepoTime, func(req *ipmitest.Request) (ipmi.CompletionCode, []byte) {
+		return ipmi.CompletionCodeInvalidCommand, nil
+	})
+	if err := c.SyncBMCClocks(ctx, time.Now()); err != nil {
+		t.Fatalf("SyncBMCClocks failed, err: %s", err)
+	}
+	clocks, err = c.GetBMCClocks(ctx)
+	if err != nil {
+		t.Fatalf("GetBMCClocks failed, err: %s", err)
+	}
+	if clocks.SDRRepoTimeSupported {
+		t.Errorf("SDRRepoTimeSupported = true, want false")
+	}
+}
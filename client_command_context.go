@@ -9,16 +9,29 @@ import "context"
 // The context contains:
 // - responderAddr: The address of the responding device (e.g., BMC)
 // - responderLUN: The Logical Unit Number of the responding device
+// - responderChannel: The channel of the responding device when it is not the BMC,
+// used by the open interface to address the device on IPMB
 // - requesterAddr: The address of the requesting device
 // - requesterLUN: The Logical Unit Number of the requesting device
+// - targetChannel, targetAddr: The controller behind the BMC the request is bridged to
+// - transitChannel, transitAddr: The transit controller for double bridging
 //
 // This context is essential for commands that require specific addressing information,
 // such as GetSensorReading and other sensor-related operations.
+//
+// The target and transit fields override the ones set by Client.WithTarget and Client.WithTransit.
 type CommandContext struct {
 	responderAddr *uint8
 	responderLUN  *uint8
 	requesterAddr *uint8
 	requesterLUN  *uint8
+
+	responderChannel *uint8
+
+	targetChannel  *uint8
+	targetAddr     *uint8
+	transitChannel *uint8
+	transitAddr    *uint8
 }
 
 func (cmdCtx *CommandContext) WithResponderAddr(responderAddr uint8) *CommandContext {
@@ -31,6 +44,11 @@ func (cmdCtx *CommandContext) WithResponderLUN(responderLUN uint8) *CommandConte
 	return cmdCtx
 }
 
+func (cmdCtx *CommandContext) WithResponderChannel(responderChannel uint8) *CommandContext {
+	cmdCtx.responderChannel = &responderChannel
+	return cmdCtx
+}
+
 func (cmdCtx *CommandContext) WithRequesterAddr(requesterAddr uint8) *CommandContext {
 	cmdCtx.requesterAddr = &requesterAddr
 	return cmdCtx
@@ -41,6 +59,26 @@ func (cmdCtx *CommandContext) WithRequesterLUN(requesterLUN uint8) *CommandConte
 	return cmdCtx
 }
 
+func (cmdCtx *CommandContext) WithTargetChannel(targetChannel uint8) *CommandContext {
+	cmdCtx.targetChannel = &targetChannel
+	return cmdCtx
+}
+
+func (cmdCtx *CommandContext) WithTargetAddr(targetAddr uint8) *CommandContext {
+	cmdCtx.targetAddr = &targetAddr
+	return cmdCtx
+}
+
+func (cmdCtx *CommandContext) WithTransitChannel(transitChannel uint8) *CommandContext {
+	cmdCtx.transitChannel = &transitChannel
+	return cmdCtx
+}
+
+func (cmdCtx *CommandContext) WithTransitAddr(transitAddr uint8) *CommandContext {
+	cmdCtx.transitAddr = &transitAddr
+	return cmdCtx
+}
+
 // commandContextKeyType is a custom type for the context key to avoid collisions
 type commandContextKeyType string
 
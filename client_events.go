@@ -0,0 +1,353 @@
+package ipmi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/bougou/go-ipmi/open"
+)
+
+// eventBufferSize is the count of events buffered for each subscriber of Events,
+// the events received when the buffer is full are dropped.
+const eventBufferSize = 64
+
+// The receiver waits before reading the ipmi dev file again after a failed read,
+// the wait starts from openReceiveBackoffMin and doubles on each consecutive failure up to openReceiveBackoffMax.
+const (
+	openReceiveBackoffMin = 10 * time.Millisecond
+	openReceiveBackoffMax = time.Second
+)
+
+// ReceivedCommand is a command request sent by other entities (like a remote console on the LAN channel,
+// or a controller on IPMB) to the BMC, and delivered to the open interface by the driver.
+type ReceivedCommand struct {
+	// Channel the command was received on.
+	Channel uint8
+
+	// RequesterAddr is the slave address (IPMB) or the software ID (LAN) of the requester.
+	RequesterAddr uint8
+
+	NetFn   NetFn
+	Command uint8
+	Data    []byte
+}
+
+// CommandHandler answers a ReceivedCommand with the completion code and the response data.
+type CommandHandler func(ctx context.Context, cmd *ReceivedCommand) (CompletionCode, []byte)
+
+type commandKey struct {
+	netFn NetFn
+	cmd   uint8
+}
+
+// openReceiver reads all messages from the ipmi dev file once Events or RegisterCommandHandler is called.
+// The responses are passed to the waiting requests matched by the msg id,
+// the events to the subscribers, and the commands to the registered handlers.
+type openReceiver struct {
+	file *os.File
+
+	send    func(file *os.File, req *open.IPMI_REQ) error
+	receive func(file *os.File) (*open.Message, error)
+
+	mu       sync.Mutex
+	pending  map[int64]chan *open.Message
+	events   map[chan *SEL]struct{}
+	handlers map[commandKey]CommandHandler
+
+	stopped bool
+	// stop is closed when the receiver is stopped, done is closed when receiveOpen returns
+	stop chan struct{}
+	done chan struct{}
+}
+
+func (c *Client) startOpenReceiver() (*openReceiver, error) {
+	if c.Interface != InterfaceOpen {
+		return nil, fmt.Errorf("only supported for open interface")
+	}
+
+	c.openipmi.mu.Lock()
+	defer c.openipmi.mu.Unlock()
+
+	if c.openipmi.file == nil {
+		return nil, fmt.Errorf("ipmi dev file not opened")
+	}
+
+	if c.openipmi.receiver == nil {
+		r := &openReceiver{
+			file:     c.openipmi.file,
+			send:     c.openipmi.sendRequest,
+			receive:  c.openipmi.receiveMessage,
+			pending:  make(map[int64]chan *open.Message),
+			events:   make(map[chan *SEL]struct{}),
+			handlers: make(map[commandKey]CommandHandler),
+			stop:     make(chan struct{}),
+			done:     make(chan struct{}),
+		}
+		// the commands sent before by openSendCommand leave their read deadline on the file,
+		// which would fail all the reads of the receiver once passed.
+		if err := r.file.SetReadDeadline(time.Time{}); err != nil {
+			return nil, fmt.Errorf("clear read deadline on ipmi dev file failed, err: %w", err)
+		}
+		go c.receiveOpen(r)
+		c.openipmi.receiver = r
+	}
+	return c.openipmi.receiver, nil
+}
+
+// stopOpenReceiver stops reading the ipmi dev file, all the channels returned by Events are closed.
+func (c *Client) stopOpenReceiver() {
+	c.openipmi.mu.Lock()
+	r := c.openipmi.receiver
+	c.openipmi.receiver = nil
+	c.openipmi.mu.Unlock()
+
+	if r == nil {
+		return
+	}
+
+	r.mu.Lock()
+	r.stopped = true
+	r.mu.Unlock()
+	close(r.stop)
+
+	// wake up the blocked read
+	if err := r.file.SetReadDeadline(time.Now()); err != nil {
+		c.Debugf("set read deadline on ipmi dev file failed, err: %s\n", err)
+	}
+	<-r.done
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for ch := range r.events {
+		delete(r.events, ch)
+		close(ch)
+	}
+	_ = r.file.SetReadDeadline(time.Time{})
+}
+
+func (c *Client) receiveOpen(r *openReceiver) {
+	defer close(r.done)
+
+	backoff := time.Duration(0)
+	for {
+		msg, err := r.receive(r.file)
+		if err != nil {
+			r.mu.Lock()
+			stopped := r.stopped
+			r.mu.Unlock()
+			if stopped || errors.Is(err, os.ErrClosed) {
+				return
+			}
+			c.Debugf("receive message from ipmi dev file failed, err: %s\n", err)
+
+			if errors.Is(err, os.ErrDeadlineExceeded) {
+				// a deadline not set by stopOpenReceiver, no more reads would succeed without clearing it
+				if err := r.file.SetReadDeadline(time.Time{}); err != nil {
+					c.Debugf("clear read deadline on ipmi dev file failed, err: %s\n", err)
+					return
+				}
+			}
+
+			backoff = min(max(backoff*2, openReceiveBackoffMin), openReceiveBackoffMax)
+			select {
+			case <-time.After(backoff):
+			case <-r.stop:
+				return
+			}
+			continue
+		}
+		backoff = 0
+
+		switch msg.RecvType {
+		case open.IPMI_RESPONSE_RECV_TYPE:
+			r.mu.Lock()
+			ch, ok := r.pending[msg.MsgID]
+			delete(r.pending, msg.MsgID)
+			r.mu.Unlock()
+			if ok {
+				ch <- msg
+			}
+
+		case open.IPMI_ASYNC_EVENT_RECV_TYPE:
+			sel, err := ParseSEL(msg.Data)
+			if err != nil {
+				c.Debugf("parse event message failed, err: %s\n", err)
+				continue
+			}
+			c.Debug("<< Event Message", sel)
+
+			r.mu.Lock()
+			for ch := range r.events {
+				select {
+				case ch <- sel:
+				default:
+					c.Debugf("event dropped as the subscriber is not reading\n")
+				}
+			}
+			r.mu.Unlock()
+
+		case open.IPMI_CMD_RECV_TYPE:
+			go c.answerOpenCommand(r, msg)
+		}
+	}
+}
+
+// answerOpenCommand calls the handler registered for the received command and sends back the response.
+// Commands without handler are answered with CompletionCodeInvalidCommand.
+func (c *Client) answerOpenCommand(r *openReceiver, msg *open.Message) {
+	cmd := &ReceivedCommand{
+		Channel: uint8(msg.Addr.Channel),
+		NetFn:   NetFn(msg.NetFn),
+		Command: msg.Cmd,
+		Data:    msg.Data,
+	}
+	switch msg.Addr.AddrType {
+	case open.IPMI_IPMB_ADDR_TYPE, open.IPMI_IPMB_DIRECT_ADDR_TYPE:
+		cmd.RequesterAddr = msg.Addr.Data[0]
+	case open.IPMI_LAN_ADDR_TYPE:
+		// Privilege, SessionHandle, RemoteSWID
+		cmd.RequesterAddr = msg.Addr.Data[2]
+	}
+	c.Debug("<< Received Command", cmd)
+
+	r.mu.Lock()
+	handler := r.handlers[commandKey{netFn: cmd.NetFn, cmd: cmd.Command}]
+	r.mu.Unlock()
+
+	ccode, data := CompletionCodeInvalidCommand, []byte(nil)
+	if handler != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), c.timeout)
+		ccode, data = handler(ctx, cmd)
+		cancel()
+	}
+
+	if err := open.SendResponse(r.file, msg, append([]byte{uint8(ccode)}, data...)); err != nil {
+		c.Debugf("send response of received command failed, err: %s\n", err)
+	}
+}
+
+// exchange sends the request and waits for the response passed by receiveOpen.
+func (r *openReceiver) exchange(ctx context.Context, req *open.IPMI_REQ, timeout time.Duration) ([]byte, error) {
+	if timeout == 0 {
+		timeout = open.IPMI_FILE_READ_TIMEOUT
+	}
+
+	ch := make(chan *open.Message, 1)
+	r.mu.Lock()
+	r.pending[req.MsgID] = ch
+	r.mu.Unlock()
+
+	defer func() {
+		r.mu.Lock()
+		delete(r.pending, req.MsgID)
+		r.mu.Unlock()
+	}()
+
+	if err := r.send(r.file, req); err != nil {
+		return nil, err
+	}
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	select {
+	case msg := <-ch:
+		return msg.Data, nil
+	case <-timer.C:
+		return nil, fmt.Errorf("wait response timeout, err: %w", os.ErrDeadlineExceeded)
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// Events returns the event messages sent to the system interface, like the events
+// generated by the sensors of the BMC. Use SELStandard.EventString to describe the events.
+//
+// The driver delivers the event messages with the format of SEL records,
+// the Record ID and Timestamp fields are not always filled.
+//
+// The channel is closed when ctx is done or the client is closed.
+// Events are dropped if the channel is not read in time.
+//
+// Only supported for open interface.
+func (c *Client) Events(ctx context.Context) (<-chan *SEL, error) {
+	r, err := c.startOpenReceiver()
+	if err != nil {
+		return nil, err
+	}
+
+	ch := make(chan *SEL, eventBufferSize)
+	r.mu.Lock()
+	if r.stopped {
+		r.mu.Unlock()
+		return nil, fmt.Errorf("client closed")
+	}
+	r.events[ch] = struct{}{}
+	r.mu.Unlock()
+
+	go func() {
+		select {
+		case <-ctx.Done():
+		case <-r.done:
+		}
+
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		if _, ok := r.events[ch]; ok {
+			delete(r.events, ch)
+			close(ch)
+		}
+	}()
+
+	return ch, nil
+}
+
+// RegisterCommandHandler registers to receive the requests of the command sent by other entities to the BMC,
+// which are answered by handler. Only one user of the ipmi driver can register for a command.
+//
+// Only supported for open interface.
+func (c *Client) RegisterCommandHandler(ctx context.Context, command Command, handler CommandHandler) error {
+	r, err := c.startOpenReceiver()
+	if err != nil {
+		return err
+	}
+
+	key := commandKey{netFn: command.NetFn, cmd: command.ID}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.handlers[key]; !ok {
+		if err := open.RegisterForCommand(r.file, uint8(command.NetFn), command.ID); err != nil {
+			return err
+		}
+	}
+	r.handlers[key] = handler
+	return nil
+}
+
+// UnregisterCommandHandler cancels the registration of RegisterCommandHandler.
+func (c *Client) UnregisterCommandHandler(ctx context.Context, command Command) error {
+	r, err := c.startOpenReceiver()
+	if err != nil {
+		return err
+	}
+
+	key := commandKey{netFn: command.NetFn, cmd: command.ID}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.handlers[key]; !ok {
+		return fmt.Errorf("no handler registered for command %s", command.Name)
+	}
+	if err := open.UnregisterForCommand(r.file, uint8(command.NetFn), command.ID); err != nil {
+		return err
+	}
+	delete(r.handlers, key)
+	return nil
+}
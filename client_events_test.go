@@ -0,0 +1,145 @@
+package ipmi
+
+import (
+	"context"
+	"errors"
+	"os"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/bougou/go-ipmi/open"
+)
+
+// fakeOpenDriver queues the messages of the ipmi driver, each queued message is signaled
+// by one byte written to a pipe, so the reads are bounded by the read deadline like the ipmi dev file.
+type fakeOpenDriver struct {
+	r, w *os.File
+
+	mu    sync.Mutex
+	queue []*open.Message
+}
+
+func newFakeOpenClient(t *testing.T) (*Client, *fakeOpenDriver) {
+	t.Helper()
+
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("os.Pipe failed, err: %s", err)
+	}
+	t.Cleanup(func() {
+		_ = w.Close()
+	})
+
+	d := &fakeOpenDriver{r: r, w: w}
+
+	c, _ := NewOpenClient()
+	c.openipmi.file = r
+	c.openipmi.sendRequest = d.sendRequest
+	c.openipmi.receiveMessage = d.receiveMessage
+	return c, d
+}
+
+func (d *fakeOpenDriver) post(msg *open.Message) {
+	d.mu.Lock()
+	d.queue = append(d.queue, msg)
+	d.mu.Unlock()
+	_, _ = d.w.Write([]byte{0})
+}
+
+// sendRequest answers all the requests as Get Device ID.
+func (d *fakeOpenDriver) sendRequest(file *os.File, req *open.IPMI_REQ) error {
+	d.post(&open.Message{
+		RecvType: open.IPMI_RESPONSE_RECV_TYPE,
+		MsgID:    req.MsgID,
+		NetFn:    req.Msg.NetFn | 0x01,
+		Cmd:      req.Msg.Cmd,
+		Data:     []byte{0x00, 0x20, 0x81, 0x01, 0x02, 0x02, 0xbf, 0xa2, 0x02, 0x00, 0x34, 0x12, 0x00, 0x00, 0x00, 0x00},
+	})
+	return nil
+}
+
+func (d *fakeOpenDriver) receiveMessage(file *os.File) (*open.Message, error) {
+	if _, err := file.Read(make([]byte, 1)); err != nil {
+		return nil, err
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	msg := d.queue[0]
+	d.queue = d.queue[1:]
+	return msg, nil
+}
+
+func TestEvents_AfterCommand(t *testing.T) {
+	c, d := newFakeOpenClient(t)
+	c.timeout = 50 * time.Millisecond
+
+	ctx := context.Background()
+
+	// the command leaves its read deadline on the file
+	if _, err := c.GetDeviceID(ctx); err != nil {
+		t.Fatalf("GetDeviceID failed, err: %s", err)
+	}
+	time.Sleep(2 * c.timeout)
+
+	events, err := c.Events(ctx)
+	if err != nil {
+		t.Fatalf("Events failed, err: %s", err)
+	}
+
+	res, err := c.GetDeviceID(ctx)
+	if err != nil {
+		t.Fatalf("GetDeviceID after Events failed, err: %s", err)
+	}
+	if res.ManufacturerID != 0x0002a2 {
+		t.Errorf("ManufacturerID = %#x, want %#x", res.ManufacturerID, 0x0002a2)
+	}
+
+	d.post(&open.Message{
+		RecvType: open.IPMI_ASYNC_EVENT_RECV_TYPE,
+		Data:     []byte{0x00, 0x00, 0x02, 0x00, 0x00, 0x00, 0x00, 0x20, 0x00, 0x04, 0x01, 0x30, 0x01, 0x57, 0x00, 0x00},
+	})
+
+	select {
+	case sel := <-events:
+		if sel.Standard == nil || sel.Standard.SensorNumber != 0x30 {
+			t.Errorf("unexpected event %v", sel)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("event not received")
+	}
+
+	if err := c.Close(ctx); err != nil {
+		t.Fatalf("Close failed, err: %s", err)
+	}
+	if _, ok := <-events; ok {
+		t.Error("events channel not closed by Close")
+	}
+}
+
+func TestEvents_ReceiveErrorBackoff(t *testing.T) {
+	c, _ := newFakeOpenClient(t)
+
+	var reads atomic.Int32
+	c.openipmi.receiveMessage = func(file *os.File) (*open.Message, error) {
+		reads.Add(1)
+		return nil, errors.New("GetRecv failed")
+	}
+
+	ctx := context.Background()
+	if _, err := c.Events(ctx); err != nil {
+		t.Fatalf("Events failed, err: %s", err)
+	}
+
+	time.Sleep(300 * time.Millisecond)
+	// 10ms, 20ms, 40ms, 80ms, 160ms
+	if n := reads.Load(); n > 10 {
+		t.Errorf("receiver read the ipmi dev file %d times in 300ms after persistent errors", n)
+	}
+
+	if err := c.Close(ctx); err != nil {
+		t.Fatalf("Close failed, err: %s", err)
+	}
+}
@@ -0,0 +1,264 @@
+package ipmi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"sync"
+	"time"
+)
+
+// MaxInFlightLimit is the max count of requests kept in flight on one RMCP+ session,
+// the session sequence numbers of the requests must fit in the window accepted by the BMC.
+// see 6.12.13 Session Sequence Number Tracking and Handling
+const MaxInFlightLimit = 16
+
+type inFlightKey struct {
+	requesterSequence uint8
+	command           uint8
+}
+
+// inFlight tracks the requests sent on the session and not answered yet.
+type inFlight struct {
+	// slots limits the count of requests in flight
+	slots chan struct{}
+
+	// stopReceiving removes dispatchInFlight from the handlers of the UDPClient
+	stopReceiving func()
+	// receivingDone is closed once the receiving goroutine of the UDPClient exits
+	receivingDone <-chan struct{}
+
+	mu      sync.Mutex
+	waiters map[inFlightKey]chan []byte
+}
+
+// WithMaxInFlight lets up to maxInFlight requests be in flight on the lanplus session at the same time,
+// so the requests of concurrent callers sharing the Client are not sent one after another.
+// Bulk methods like GetSensors also send their requests concurrently.
+//
+// The BMC does not echo the session sequence number, so the responses are matched back to
+// the requests by the IPMI requester sequence number and the command.
+// The bridged requests and the session setup requests are still sent one at a time.
+//
+// maxInFlight is limited to MaxInFlightLimit, 0 or 1 means one request at a time (the default).
+func (c *Client) WithMaxInFlight(maxInFlight int) *Client {
+	if maxInFlight < 1 {
+		maxInFlight = 1
+	}
+	if maxInFlight > MaxInFlightLimit {
+		maxInFlight = MaxInFlightLimit
+	}
+	c.maxInFlight = maxInFlight
+	return c
+}
+
+// concurrency returns how many requests the bulk methods should send concurrently.
+func (c *Client) concurrency() int {
+	if c.Interface != InterfaceLanplus || c.maxInFlight < 1 {
+		return 1
+	}
+	return c.maxInFlight
+}
+
+// inFlightFor returns the inFlight tracker if the request can be sent without waiting
+// for the replies of other requests, or nil.
+func (c *Client) inFlightFor(request Request, bridge *ipmbBridge) *inFlight {
+	if c.maxInFlight <= 1 || !c.v20 || bridge != nil {
+		return nil
+	}
+	switch request.(type) {
+	case *RmcpPingRequest, *OpenSessionRequest, *RAKPMessage1, *RAKPMessage3:
+		return nil
+	}
+
+	c.lock()
+	defer c.unlock()
+
+	if c.session.v20.state != SessionStateActive {
+		return nil
+	}
+
+	if c.inFlight != nil {
+		select {
+		case <-c.inFlight.receivingDone:
+			// the receiving goroutine exited with the conn closed, start another one
+			c.inFlight.stopReceiving()
+			c.inFlight = nil
+		default:
+		}
+	}
+
+	if c.inFlight == nil {
+		// the receiving goroutine passes the replies to the waiting requests,
+		// it is shared with the SOL session opened on the same UDPClient
+		stop, done, err := c.udpClient.startReceiving(c.dispatchInFlight)
+		if err != nil {
+			c.DebugfRed("start receiving failed, err: %s\n", err)
+			return nil
+		}
+		c.inFlight = &inFlight{
+			slots:         make(chan struct{}, c.maxInFlight),
+			stopReceiving: stop,
+			receivingDone: done,
+			waiters:       make(map[inFlightKey]chan []byte),
+		}
+	}
+	return c.inFlight
+}
+
+// stopInFlight stops receiving the replies of the requests in flight.
+func (c *Client) stopInFlight() {
+	c.lock()
+	f := c.inFlight
+	c.inFlight = nil
+	c.unlock()
+
+	if f != nil {
+		f.stopReceiving()
+	}
+}
+
+// exchangeInFlight sends the request without waiting for the replies of the other requests in flight.
+func (c *Client) exchangeInFlight(ctx context.Context, f *inFlight, request Request, response Response) error {
+	select {
+	case f.slots <- struct{}{}:
+	case <-ctx.Done():
+		return fmt.Errorf("canceled from caller")
+	}
+	defer func() { <-f.slots }()
+
+	ipmiReq, err := c.BuildIPMIRequest(ctx, request)
+	if err != nil {
+		return fmt.Errorf("BuildIPMIRequest failed, err: %w", err)
+	}
+	c.Debug(">>>> IPMI Request", ipmiReq)
+
+	session20, err := c.genSession20(PayloadTypeIPMI, ipmiReq.Pack())
+	if err != nil {
+		return fmt.Errorf("genSession20 failed, err: %w", err)
+	}
+	rmcp := &Rmcp{
+		RmcpHeader: NewRmcpHeader(),
+		Session20:  session20,
+	}
+	c.Debug(">>>>>> RMCP Request", rmcp)
+	sent := rmcp.Pack()
+	c.DebugBytes("sent", sent, 16)
+
+	key := inFlightKey{
+		requesterSequence: ipmiReq.RequesterSequence,
+		command:           ipmiReq.Command,
+	}
+	recvCh := make(chan []byte, 1)
+
+	f.mu.Lock()
+	f.waiters[key] = recvCh
+	f.mu.Unlock()
+
+	defer func() {
+		f.mu.Lock()
+		delete(f.waiters, key)
+		f.mu.Unlock()
+	}()
+
+	var recv []byte
+	attempts := c.retryCount + 1 // initial try plus retries
+
+	attemptCount := 0
+	for attempt := 1; attempt <= attempts; attempt += 1 {
+		attemptCount = attempt
+		if err = c.udpClient.Send(sent); err != nil {
+			break
+		}
+
+		recv, err = c.waitInFlight(ctx, f, recvCh)
+		var netErr net.Error
+		if errors.As(err, &netErr) && netErr.Timeout() && attempt < attempts {
+			c.Debugf("Attempt %d/%d: timeout error: %v. Retrying...\n", attempt, attempts, err)
+			time.Sleep(c.retryInterval)
+			continue
+		}
+		break
+	}
+	if err != nil {
+		return fmt.Errorf("client udp exchange msg failed, attempts %d times, err: %w", attemptCount, err)
+	}
+	c.DebugBytes("recv", recv, 16)
+
+	if err := c.ParseRmcpResponse(ctx, recv, response); err != nil {
+		return err
+	}
+
+	c.Debug("<< Command Response", response)
+	return nil
+}
+
+func (c *Client) waitInFlight(ctx context.Context, f *inFlight, recvCh chan []byte) ([]byte, error) {
+	timer := time.NewTimer(c.timeout)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return nil, fmt.Errorf("canceled from caller")
+	case <-timer.C:
+		// same as the error returned by the conn read when the deadline is exceeded
+		return nil, &net.OpError{Op: "read", Net: "udp", Err: os.ErrDeadlineExceeded}
+	case <-f.receivingDone:
+		return nil, fmt.Errorf("receive from conn failed, err: %w", net.ErrClosed)
+	case msg := <-recvCh:
+		return msg, nil
+	}
+}
+
+// dispatchInFlight passes the reply to the request in flight it answers,
+// it is called on the receiving goroutine of the UDPClient.
+// The replies not matched are left to UDPClient.Exchange.
+func (c *Client) dispatchInFlight(msg []byte) bool {
+	rmcp := &Rmcp{}
+	if err := rmcp.Unpack(msg); err != nil {
+		return false
+	}
+	if rmcp.Session20 == nil || rmcp.Session20.SessionHeader20.PayloadType != PayloadTypeIPMI {
+		return false
+	}
+
+	payload := rmcp.Session20.SessionPayload
+	if rmcp.Session20.SessionHeader20.PayloadEncrypted {
+		c.lock()
+		d, err := c.decryptPayload(payload)
+		c.unlock()
+		if err != nil {
+			return false
+		}
+		payload = d
+	}
+
+	ipmiRes := &IPMIResponse{}
+	if err := ipmiRes.Unpack(payload); err != nil {
+		return false
+	}
+	key := inFlightKey{
+		requesterSequence: ipmiRes.RequesterSequence,
+		command:           ipmiRes.Command,
+	}
+
+	c.lock()
+	f := c.inFlight
+	c.unlock()
+	if f == nil {
+		return false
+	}
+
+	f.mu.Lock()
+	recvCh, ok := f.waiters[key]
+	delete(f.waiters, key)
+	f.mu.Unlock()
+	if !ok {
+		return false
+	}
+
+	recvCh <- msg
+	return true
+}
@@ -0,0 +1,196 @@
+package ipmi_test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/bougou/go-ipmi"
+	"github.com/bougou/go-ipmi/ipmitest"
+)
+
+func TestClient_MaxInFlight(t *testing.T) {
+	const (
+		sensorCount = 16
+		latency     = 10 * time.Millisecond
+	)
+
+	s := newTestServer(t, func(s *ipmitest.Server) {
+		s.Latency = latency
+		for i := uint8(2); i <= sensorCount; i++ {
+			s.AddSDR(fullSensorSDR(i, fmt.Sprintf("Temp %d", i)))
+			s.SetSensor(i, ipmitest.Sensor{Reading: 40 + i})
+		}
+	})
+
+	ctx := context.Background()
+	c := newTestClient(t, s, ipmi.InterfaceLanplus, testPassword).WithMaxInFlight(8)
+	if err := c.Connect(ctx); err != nil {
+		t.Fatalf("Connect failed, err: %s", err)
+	}
+	defer c.Close(ctx)
+
+	sensors, err := c.GetSensors(ctx)
+	if err != nil {
+		t.Fatalf("GetSensors failed, err: %s", err)
+	}
+	if len(sensors) != sensorCount {
+		t.Fatalf("GetSensors returned %d sensors, want %d", len(sensors), sensorCount)
+	}
+	for i, sensor := range sensors {
+		want := float64(40)
+		if sensor.Number != 1 {
+			want += float64(sensor.Number)
+		}
+		if sensor.Number != uint8(i+1) || sensor.Value != want {
+			t.Errorf("sensors[%d] is sensor %d with value %v, want sensor %d with value %v", i, sensor.Number, sensor.Value, i+1, want)
+		}
+	}
+
+	// concurrent callers sharing the client
+	start := time.Now()
+	var wg sync.WaitGroup
+	for i := uint8(2); i <= sensorCount; i++ {
+		wg.Add(1)
+		go func(number uint8) {
+			defer wg.Done()
+			res, err := c.GetSensorReading(ctx, number)
+			if err != nil {
+				t.Errorf("GetSensorReading(%d) failed, err: %s", number, err)
+				return
+			}
+			if res.Reading != 40+number {
+				t.Errorf("GetSensorReading(%d) returned %d, want %d", number, res.Reading, 40+number)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	// one request after another takes at least one round trip for each request
+	if elapsed, serial := time.Since(start), (sensorCount-1)*latency; elapsed >= serial {
+		t.Errorf("GetSensorReading calls took %s, not faster than one after another (%s)", elapsed, serial)
+	}
+}
+
+func TestClient_MaxInFlightSOL(t *testing.T) {
+	s := newTestServer(t, func(s *ipmitest.Server) {
+		s.SOLHandler = func(data []byte) []byte {
+			return bytes.ToUpper(data)
+		}
+	})
+
+	ctx := context.Background()
+	c := newTestClient(t, s, ipmi.InterfaceLanplus, testPassword).WithMaxInFlight(8)
+	if err := c.Connect(ctx); err != nil {
+		t.Fatalf("Connect failed, err: %s", err)
+	}
+	defer c.Close(ctx)
+
+	// starts receiving the replies of the requests in flight
+	if _, err := c.GetDeviceID(ctx); err != nil {
+		t.Fatalf("GetDeviceID failed, err: %s", err)
+	}
+
+	sol, err := c.OpenSOL(ctx, 1)
+	if err != nil {
+		t.Fatalf("OpenSOL failed, err: %s", err)
+	}
+
+	// the requests in flight are answered while the SOL session is open
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := c.GetSensorReading(ctx, 0x01); err != nil {
+				t.Errorf("GetSensorReading failed, err: %s", err)
+			}
+		}()
+	}
+
+	input := []byte("hello in flight")
+	if _, err := sol.Write(input); err != nil {
+		t.Fatalf("Write failed, err: %s", err)
+	}
+	got := make([]byte, len(input))
+	if _, err := io.ReadFull(sol, got); err != nil {
+		t.Fatalf("Read failed, err: %s", err)
+	}
+	if want := bytes.ToUpper(input); !bytes.Equal(got, want) {
+		t.Errorf("Read got %q, want %q", got, want)
+	}
+	wg.Wait()
+
+	if err := sol.Close(); err != nil {
+		t.Fatalf("Close failed, err: %s", err)
+	}
+
+	// closing the SOL session keeps the replies of the requests in flight received
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := c.GetSensorReading(ctx, 0x01); err != nil {
+				t.Errorf("GetSensorReading after closing SOL failed, err: %s", err)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestClient_MaxInFlightBMCReset(t *testing.T) {
+	s := newTestServer(t)
+
+	ctx := context.Background()
+	var reconnects []error
+	c := newTestClient(t, s, ipmi.InterfaceLanplus, testPassword).
+		WithTimeout(200 * time.Millisecond).
+		WithMaxInFlight(8).
+		WithAutoReconnect(func(cause error, err error) {
+			reconnects = append(reconnects, err)
+		})
+	if err := c.Connect(ctx); err != nil {
+		t.Fatalf("Connect failed, err: %s", err)
+	}
+	defer c.Close(ctx)
+
+	// starts receiving the replies of the requests in flight
+	if _, err := c.GetDeviceID(ctx); err != nil {
+		t.Fatalf("GetDeviceID failed, err: %s", err)
+	}
+
+	// the requests are refused while the BMC is resetting
+	if err := s.Close(); err != nil {
+		t.Fatalf("Close failed, err: %s", err)
+	}
+	if _, err := c.GetDeviceID(ctx); err == nil {
+		t.Fatalf("GetDeviceID succeeded while the BMC is resetting")
+	}
+	if err := s.Start(); err != nil {
+		t.Fatalf("Start failed, err: %s", err)
+	}
+
+	// the session lost in the reset is re-established, and the requests are kept in flight
+	var wg sync.WaitGroup
+	if _, err := c.GetDeviceID(ctx); err != nil {
+		t.Fatalf("GetDeviceID after the BMC reset failed, err: %s", err)
+	}
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := c.GetSensorReading(ctx, 0x01); err != nil {
+				t.Errorf("GetSensorReading after the BMC reset failed, err: %s", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if n := len(reconnects); n == 0 || reconnects[n-1] != nil {
+		t.Errorf("got reconnects %v, want the session re-established", reconnects)
+	}
+}
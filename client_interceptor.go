@@ -0,0 +1,87 @@
+package ipmi
+
+import (
+	"context"
+)
+
+// ExchangeFunc sends the request and unpacks the response.
+type ExchangeFunc func(ctx context.Context, request Request, response Response) error
+
+// Interceptor is called around each exchange of the Client, it must call next to send the request,
+// or it can return without calling next, like injecting a fault.
+//
+// The Command of the request is got by request.Command(), and more details of the exchange
+// like the attempt count and the completion code are got by GetExchangeInfo(ctx).
+type Interceptor func(ctx context.Context, request Request, response Response, next ExchangeFunc) error
+
+// ExchangeInfo holds the details of the exchange passed to the interceptors.
+type ExchangeInfo struct {
+	// Attempt is 1 for the first attempt, and increased for each retry of the RetryPolicy.
+	Attempt int
+
+	// RequestData is the packed data of the request.
+	RequestData []byte
+
+	// CompletionCode and ResponseData are set when the response is received,
+	// ResponseData does not include the completion code.
+	// For bridged requests, they are of the response of the bridged request.
+	Received       bool
+	CompletionCode CompletionCode
+	ResponseData   []byte
+}
+
+// exchangeInfoKeyType is a custom type for the context key to avoid collisions
+type exchangeInfoKeyType string
+
+// exchangeInfoKey is the key used to store the ExchangeInfo of the exchange
+const exchangeInfoKey exchangeInfoKeyType = "exchangeInfo"
+
+// GetExchangeInfo returns the ExchangeInfo of the exchange, it is only available for the ctx passed to interceptors.
+func GetExchangeInfo(ctx context.Context) *ExchangeInfo {
+	info, _ := ctx.Value(exchangeInfoKey).(*ExchangeInfo)
+	return info
+}
+
+// WithInterceptor adds the interceptors around the exchanges of the Client on all interfaces.
+// The interceptors added first are the outer ones.
+//
+// For example, measure the latency of the commands:
+//
+//	client.WithInterceptor(func(ctx context.Context, request ipmi.Request, response ipmi.Response, next ipmi.ExchangeFunc) error {
+//		start := time.Now()
+//		err := next(ctx, request, response)
+//		info := ipmi.GetExchangeInfo(ctx)
+//		log.Printf("%s attempt %d cc %#02x took %s", request.Command().Name, info.Attempt, info.CompletionCode, time.Since(start))
+//		return err
+//	})
+func (c *Client) WithInterceptor(interceptors ...Interceptor) *Client {
+	c.interceptors = append(c.interceptors, interceptors...)
+	return c
+}
+
+// intercept runs the exchange through the interceptors.
+func (c *Client) intercept(ctx context.Context, attempt int, request Request, response Response, exchange ExchangeFunc) error {
+	info := &ExchangeInfo{
+		Attempt:     attempt,
+		RequestData: request.Pack(),
+	}
+	ctx = context.WithValue(ctx, exchangeInfoKey, info)
+
+	next := exchange
+	for i := len(c.interceptors) - 1; i >= 0; i-- {
+		interceptor, inner := c.interceptors[i], next
+		next = func(ctx context.Context, request Request, response Response) error {
+			return interceptor(ctx, request, response, inner)
+		}
+	}
+	return next(ctx, request, response)
+}
+
+// setReceived records the completion code and the data of the response for the interceptors.
+func setReceived(ctx context.Context, ccode uint8, data []byte) {
+	if info := GetExchangeInfo(ctx); info != nil {
+		info.Received = true
+		info.CompletionCode = CompletionCode(ccode)
+		info.ResponseData = data
+	}
+}
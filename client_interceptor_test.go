@@ -0,0 +1,86 @@
+package ipmi_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/bougou/go-ipmi"
+	"github.com/bougou/go-ipmi/ipmitest"
+)
+
+func TestClient_Interceptor(t *testing.T) {
+	ctx := context.Background()
+
+	busy := 1
+	s := newTestServer(t, func(s *ipmitest.Server) {
+		s.Handle(ipmi.CommandGetDeviceID, func(req *ipmitest.Request) (ipmi.CompletionCode, []byte) {
+			if busy > 0 {
+				busy--
+				return ipmi.CompletionCodeNodeBusy, nil
+			}
+			return ipmi.CompletionCodeNormal, ipmitest.DefaultDeviceInfo.Pack()
+		})
+	})
+
+	type call struct {
+		name     string
+		attempt  int
+		received bool
+		cc       ipmi.CompletionCode
+	}
+	var calls []call
+	errInjected := fmt.Errorf("injected fault")
+
+	policy := ipmi.DefaultRetryPolicy()
+	policy.InitialBackoff = time.Millisecond
+
+	c := newTestClient(t, s, ipmi.InterfaceLanplus, testPassword).WithRetryPolicy(policy)
+	if err := c.Connect(ctx); err != nil {
+		t.Fatalf("Connect failed, err: %s", err)
+	}
+	defer c.Close(ctx)
+
+	c.WithInterceptor(
+		func(ctx context.Context, request ipmi.Request, response ipmi.Response, next ipmi.ExchangeFunc) error {
+			err := next(ctx, request, response)
+			info := ipmi.GetExchangeInfo(ctx)
+			calls = append(calls, call{
+				name:     request.Command().Name,
+				attempt:  info.Attempt,
+				received: info.Received,
+				cc:       info.CompletionCode,
+			})
+			return err
+		},
+		func(ctx context.Context, request ipmi.Request, response ipmi.Response, next ipmi.ExchangeFunc) error {
+			if request.Command() == ipmi.CommandGetSystemGUID {
+				return errInjected
+			}
+			return next(ctx, request, response)
+		},
+	)
+
+	if _, err := c.GetDeviceID(ctx); err != nil {
+		t.Fatalf("GetDeviceID failed, err: %s", err)
+	}
+	if _, err := c.GetSystemGUID(ctx); !errors.Is(err, errInjected) {
+		t.Fatalf("GetSystemGUID error = %v, want the injected fault", err)
+	}
+
+	want := []call{
+		{name: ipmi.CommandGetDeviceID.Name, attempt: 1, received: true, cc: ipmi.CompletionCodeNodeBusy},
+		{name: ipmi.CommandGetDeviceID.Name, attempt: 2, received: true, cc: ipmi.CompletionCodeNormal},
+		{name: ipmi.CommandGetSystemGUID.Name, attempt: 1, received: false},
+	}
+	if len(calls) != len(want) {
+		t.Fatalf("got calls %+v, want %+v", calls, want)
+	}
+	for i := range want {
+		if calls[i] != want[i] {
+			t.Errorf("calls[%d] = %+v, want %+v", i, calls[i], want[i])
+		}
+	}
+}
@@ -0,0 +1,163 @@
+package ipmi
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/kr/pretty"
+)
+
+// redacted replaces the key material, passwords and auth codes in the logger output.
+const redacted = "[REDACTED]"
+
+// WithLogger sends the debug messages of the Client to the logger at the debug level instead of stdout,
+// and logs the exchanges and the session events with structured attributes
+// (host, command, netfn, cc, session id, attempt and so on).
+//
+// The key material, passwords and auth codes are redacted from the logger output: the byte dumps
+// are logged by length only, and the messages carrying secrets are summarized or redacted.
+// The stdout output of WithDebug is not redacted, it is meant for debugging the protocol.
+func (c *Client) WithLogger(logger *slog.Logger) *Client {
+	c.logger = logger
+	return c
+}
+
+func (c *Client) logEnabled(ctx context.Context, level slog.Level) bool {
+	return c.logger != nil && c.logger.Enabled(ctx, level)
+}
+
+func (c *Client) log(ctx context.Context, level slog.Level, msg string, attrs ...slog.Attr) {
+	if !c.logEnabled(ctx, level) {
+		return
+	}
+
+	attrs = append([]slog.Attr{
+		slog.String("host", c.Host),
+		slog.String("interface", string(c.Interface)),
+	}, attrs...)
+	c.logger.LogAttrs(ctx, level, msg, attrs...)
+}
+
+// sessionAttr returns the attribute of the session id used by the lan/lanplus client.
+func (c *Client) sessionAttr() slog.Attr {
+	if c.session == nil {
+		return slog.Attr{}
+	}
+
+	c.lock()
+	defer c.unlock()
+
+	if c.v20 {
+		return slog.String("session_id", fmt.Sprintf("%#08x", c.session.v20.bmcSessionID))
+	}
+	return slog.String("session_id", fmt.Sprintf("%#08x", c.session.v15.sessionID))
+}
+
+// logExchange logs the result of an exchange attempt.
+func (c *Client) logExchange(ctx context.Context, attempt int, request Request, start time.Time, err error) {
+	if !c.logEnabled(ctx, slog.LevelDebug) {
+		return
+	}
+
+	cmd := request.Command()
+	attrs := []slog.Attr{
+		slog.String("command", cmd.Name),
+		slog.String("netfn", fmt.Sprintf("%#02x", uint8(cmd.NetFn))),
+		slog.String("cmd", fmt.Sprintf("%#02x", cmd.ID)),
+		slog.Int("attempt", attempt),
+		slog.Duration("duration", time.Since(start)),
+		c.sessionAttr(),
+	}
+
+	if respErr, ok := isResponseError(err); ok {
+		attrs = append(attrs, slog.String("cc", fmt.Sprintf("%#02x", uint8(respErr.CompletionCode()))))
+	} else if err == nil {
+		attrs = append(attrs, slog.String("cc", fmt.Sprintf("%#02x", uint8(CompletionCodeNormal))))
+	}
+	if err != nil {
+		attrs = append(attrs, slog.String("err", err.Error()))
+	}
+
+	c.log(ctx, slog.LevelDebug, "exchange", attrs...)
+}
+
+// logDebugf logs the message of Debugf.
+func (c *Client) logDebugf(format string, object ...interface{}) {
+	ctx := context.Background()
+	if !c.logEnabled(ctx, slog.LevelDebug) {
+		return
+	}
+	c.log(ctx, slog.LevelDebug, strings.TrimSpace(pretty.Sprintf(format, object...)))
+}
+
+// logDebug logs the object of Debug, the objects carrying secrets are summarized or redacted.
+func (c *Client) logDebug(header string, object interface{}) {
+	ctx := context.Background()
+	if !c.logEnabled(ctx, slog.LevelDebug) {
+		return
+	}
+	c.log(ctx, slog.LevelDebug, strings.TrimSpace(header), objectAttr(object))
+}
+
+// logDebugBytes logs the length of the bytes of DebugBytes, the bytes are not logged
+// as the packets, keys and auth codes dumped in the session activation are not told apart.
+func (c *Client) logDebugBytes(header string, data []byte) {
+	ctx := context.Background()
+	if !c.logEnabled(ctx, slog.LevelDebug) {
+		return
+	}
+	c.log(ctx, slog.LevelDebug, strings.TrimSpace(header), slog.Int("length", len(data)))
+}
+
+func objectAttr(object interface{}) slog.Attr {
+	switch v := object.(type) {
+	case
+		*RAKPMessage1, *RAKPMessage2, *RAKPMessage3, *RAKPMessage4,
+		*GetSessionChallengeResponse, *ActivateSessionRequest,
+		*SetUserPasswordRequest,
+		*SetChannelSecurityKeysRequest, *SetChannelSecurityKeysResponse:
+		return slog.String("object", redacted)
+
+	case *IPMIRequest:
+		// the command data may carry passwords or keys
+		return slog.Group("object",
+			slog.String("netfn", fmt.Sprintf("%#02x", uint8(v.NetFn))),
+			slog.String("cmd", fmt.Sprintf("%#02x", v.Command)),
+			slog.Int("seq", int(v.RequesterSequence)),
+			slog.Int("data_length", len(v.CommandData)),
+		)
+
+	case *IPMIResponse:
+		return slog.Group("object",
+			slog.String("netfn", fmt.Sprintf("%#02x", uint8(v.NetFn))),
+			slog.String("cmd", fmt.Sprintf("%#02x", v.Command)),
+			slog.Int("seq", int(v.RequesterSequence)),
+			slog.String("cc", fmt.Sprintf("%#02x", v.CompletionCode)),
+			slog.Int("data_length", len(v.Data)),
+		)
+
+	case *Rmcp:
+		// the session headers and trailers carry auth codes, the payloads may carry RAKP messages
+		switch {
+		case v.Session20 != nil:
+			return slog.Group("object",
+				slog.String("payload_type", fmt.Sprintf("%#02x", uint8(v.Session20.SessionHeader20.PayloadType))),
+				slog.String("session_id", fmt.Sprintf("%#08x", v.Session20.SessionHeader20.SessionID)),
+				slog.Uint64("sequence", uint64(v.Session20.SessionHeader20.Sequence)),
+				slog.Int("payload_length", len(v.Session20.SessionPayload)),
+			)
+		case v.Session15 != nil:
+			return slog.Group("object",
+				slog.String("session_id", fmt.Sprintf("%#08x", v.Session15.SessionHeader15.SessionID)),
+				slog.Uint64("sequence", uint64(v.Session15.SessionHeader15.Sequence)),
+				slog.Int("payload_length", len(v.Session15.Payload)),
+			)
+		}
+		return slog.String("object", "rmcp")
+	}
+
+	return slog.String("object", fmt.Sprintf("%+v", object))
+}
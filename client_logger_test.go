@@ -0,0 +1,60 @@
+package ipmi_test
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/bougou/go-ipmi"
+)
+
+func TestClient_Logger(t *testing.T) {
+	ctx := context.Background()
+
+	s := newTestServer(t)
+
+	tests := []struct {
+		name  string
+		iface ipmi.Interface
+	}{
+		{"lanplus", ipmi.InterfaceLanplus},
+		{"lan", ipmi.InterfaceLan},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			logger := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+			c := newTestClient(t, s, tt.iface, testPassword).WithLogger(logger)
+			if err := c.Connect(ctx); err != nil {
+				t.Fatalf("Connect failed, err: %s", err)
+			}
+			defer c.Close(ctx)
+
+			if _, err := c.GetDeviceID(ctx); err != nil {
+				t.Fatalf("GetDeviceID failed, err: %s", err)
+			}
+
+			out := buf.String()
+			for _, want := range []string{
+				`"msg":"session established"`,
+				`"msg":"exchange","host":"127.0.0.1","interface":"` + string(tt.iface) + `","command":"Get Device ID","netfn":"0x06","cmd":"0x01","attempt":1`,
+				`"session_id":"0x`,
+				`"cc":"0x00"`,
+			} {
+				if !strings.Contains(out, want) {
+					t.Errorf("log output does not contain %s", want)
+				}
+			}
+			if strings.Contains(out, testPassword) {
+				t.Errorf("log output contains the password")
+			}
+			if tt.iface == ipmi.InterfaceLanplus && !strings.Contains(out, `"object":"[REDACTED]"`) {
+				t.Errorf("RAKP messages are not redacted")
+			}
+		})
+	}
+}
@@ -0,0 +1,162 @@
+package ipmi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"syscall"
+)
+
+// errSessionNotActive means the session is not re-established after it was lost.
+var errSessionNotActive = errors.New("session not active")
+
+// ReconnectFunc is called after the client tried to re-establish the lost session,
+// cause is the error which revealed the session was lost, and err is the result of the login.
+type ReconnectFunc func(cause error, err error)
+
+// reloginKeyType is a custom type for the context key to avoid collisions
+type reloginKeyType string
+
+// reloginKey marks the requests sent while the session is being re-established
+const reloginKey reloginKeyType = "relogin"
+
+func withRelogin(ctx context.Context) context.Context {
+	return context.WithValue(ctx, reloginKey, true)
+}
+
+func isRelogin(ctx context.Context) bool {
+	v, _ := ctx.Value(reloginKey).(bool)
+	return v
+}
+
+// isSessionLost reports whether the exchange error may be caused by a lost session.
+// Most BMCs silently discard the packets of unknown sessions, the others answer them outside of the session.
+// BMCs refuse all packets while resetting.
+func isSessionLost(err error) bool {
+	return isTimeout(err) || errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, ErrSessionInvalid) || errors.Is(err, errSessionNotActive)
+}
+
+// sessionActive reports whether the session is established, it is not if the last reconnect failed.
+func (c *Client) sessionActive() bool {
+	c.lock()
+	defer c.unlock()
+
+	if c.v20 {
+		return c.session.v20.state == SessionStateActive
+	}
+	return c.session.v15.active
+}
+
+// reconnectable reports whether the session should be re-established if the request fails.
+func (c *Client) reconnectable(ctx context.Context, request Request) bool {
+	if !c.autoReconnect || !c.established || isRelogin(ctx) {
+		return false
+	}
+	if request.Command() == CommandCloseSession {
+		return false
+	}
+
+	select {
+	case <-c.closedCh:
+		return false
+	default:
+		return true
+	}
+}
+
+// exchangeSession exchanges the request on the lan/lanplus session,
+// the session is re-established and the request is retried once if the session is lost.
+func (c *Client) exchangeSession(ctx context.Context, request Request, response Response) error {
+	if !c.reconnectable(ctx, request) {
+		return c.exchangeLAN(ctx, request, response)
+	}
+
+	c.sessionL.RLock()
+	gen := c.sessionGen
+	// the requests are not sent out of the session if the last reconnect failed
+	err := errSessionNotActive
+	if c.sessionActive() {
+		err = c.exchangeLAN(ctx, request, response)
+	}
+	c.sessionL.RUnlock()
+
+	if !isSessionLost(err) {
+		return err
+	}
+
+	lost, reloginErr := c.relogin(ctx, gen, request, err)
+	if !lost {
+		return err
+	}
+	if reloginErr != nil {
+		return fmt.Errorf("session lost, %w, and reconnect failed, err: %s", err, reloginErr)
+	}
+
+	c.Debugf("retry request [%s] on the re-established session\n", request.Command().Name)
+	c.sessionL.RLock()
+	defer c.sessionL.RUnlock()
+	return c.exchangeLAN(ctx, request, response)
+}
+
+// relogin re-establishes the session if it is lost, cause is the error of the failed request.
+// It returns false if the session still answers requests.
+// Nothing is done if the session was re-established by another request since gen.
+func (c *Client) relogin(ctx context.Context, gen uint64, request Request, cause error) (bool, error) {
+	c.sessionL.Lock()
+	defer c.sessionL.Unlock()
+
+	if c.sessionGen != gen {
+		return true, nil
+	}
+
+	ctx = withRelogin(ctx)
+
+	if request.Command() != CommandGetSessionInfo && c.sessionActive() {
+		// the timeout may be caused by a lost packet, make sure the session is gone
+		if _, err := c.GetCurrentSessionInfo(ctx); !isSessionLost(err) {
+			return false, nil
+		}
+	}
+
+	c.DebugfRed("session lost, err: %s, reconnecting\n", cause)
+	c.log(ctx, slog.LevelWarn, "session lost, reconnecting", c.sessionAttr(), slog.String("err", cause.Error()))
+	c.resetSession()
+
+	var err error
+	if c.v20 {
+		err = c.login20(ctx)
+	} else {
+		err = c.login15(ctx)
+	}
+	if err == nil {
+		c.sessionGen += 1
+		c.log(ctx, slog.LevelInfo, "session re-established", c.sessionAttr())
+	} else {
+		c.log(ctx, slog.LevelError, "session re-establishment failed", slog.String("err", err.Error()))
+	}
+
+	if c.onReconnect != nil {
+		c.onReconnect(cause, err)
+	}
+	return true, err
+}
+
+// resetSession discards the state of the lost session, the negotiated cipher suite is kept.
+func (c *Client) resetSession() {
+	c.lock()
+	defer c.unlock()
+
+	c.session.v15.preSession = false
+	c.session.v15.active = false
+	c.session.v15.sessionID = 0
+	c.session.v15.inSeq = 0
+	c.session.v15.outSeq = 0
+
+	c.session.v20.state = SessionStatePreSession
+	c.session.v20.sequence = 0
+	c.session.v20.bmcSessionID = 0
+	c.session.v20.consoleSessionID = 0
+	c.session.v20.accumulatedPayloadSize = 0
+}
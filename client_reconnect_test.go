@@ -0,0 +1,160 @@
+package ipmi_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/bougou/go-ipmi"
+	"github.com/bougou/go-ipmi/ipmitest"
+)
+
+func TestClient_AutoReconnect(t *testing.T) {
+	tests := []struct {
+		name          string
+		intf          ipmi.Interface
+		autoReconnect bool
+	}{
+		{name: "lanplus", intf: ipmi.InterfaceLanplus, autoReconnect: true},
+		{name: "lan", intf: ipmi.InterfaceLan, autoReconnect: true},
+		{name: "lanplus without auto reconnect", intf: ipmi.InterfaceLanplus, autoReconnect: false},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			s := newTestServer(t)
+
+			var reconnects []error
+			c := newTestClient(t, s, tt.intf, testPassword).WithTimeout(300 * time.Millisecond)
+			if tt.autoReconnect {
+				c.WithAutoReconnect(func(cause error, err error) {
+					reconnects = append(reconnects, err)
+				})
+			}
+			if err := c.Connect(ctx); err != nil {
+				t.Fatalf("Connect failed, err: %s", err)
+			}
+			defer c.Close(ctx)
+
+			if _, err := c.GetDeviceID(ctx); err != nil {
+				t.Fatalf("GetDeviceID failed, err: %s", err)
+			}
+
+			s.CloseSessions()
+
+			_, err := c.GetDeviceID(ctx)
+			if !tt.autoReconnect {
+				if err == nil {
+					t.Fatalf("GetDeviceID succeeded on the closed session")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("GetDeviceID after the session closed failed, err: %s", err)
+			}
+			if len(reconnects) != 1 || reconnects[0] != nil {
+				t.Fatalf("got reconnects %v, want one successful reconnect", reconnects)
+			}
+
+			// the re-established session keeps working without reconnecting
+			if _, err := c.GetDeviceID(ctx); err != nil {
+				t.Fatalf("GetDeviceID failed, err: %s", err)
+			}
+			if len(reconnects) != 1 {
+				t.Errorf("got %d reconnects, want 1", len(reconnects))
+			}
+		})
+	}
+}
+
+func TestClient_AutoReconnectBMCReset(t *testing.T) {
+	for _, intf := range []ipmi.Interface{ipmi.InterfaceLanplus, ipmi.InterfaceLan} {
+		intf := intf
+		t.Run(string(intf), func(t *testing.T) {
+			ctx := context.Background()
+			s := newTestServer(t)
+
+			var reconnects []error
+			c := newTestClient(t, s, intf, testPassword).
+				WithTimeout(200 * time.Millisecond).
+				WithAutoReconnect(func(cause error, err error) {
+					reconnects = append(reconnects, err)
+				})
+			if err := c.Connect(ctx); err != nil {
+				t.Fatalf("Connect failed, err: %s", err)
+			}
+			defer c.Close(ctx)
+
+			// the session can not be re-established while the BMC is resetting
+			if err := s.Close(); err != nil {
+				t.Fatalf("Close failed, err: %s", err)
+			}
+			if _, err := c.GetDeviceID(ctx); err == nil {
+				t.Fatalf("GetDeviceID succeeded while the BMC is resetting")
+			}
+			if len(reconnects) != 1 || reconnects[0] == nil {
+				t.Fatalf("got reconnects %v, want one failed reconnect", reconnects)
+			}
+
+			// the next request re-establishes the session once the BMC is back
+			if err := s.Start(); err != nil {
+				t.Fatalf("Start failed, err: %s", err)
+			}
+			if _, err := c.GetDeviceID(ctx); err != nil {
+				t.Fatalf("GetDeviceID after the BMC reset failed, err: %s", err)
+			}
+			if len(reconnects) != 2 || reconnects[1] != nil {
+				t.Errorf("got reconnects %v, want a successful reconnect after the failed one", reconnects)
+			}
+		})
+	}
+}
+
+func TestClient_AutoReconnectInvalidSession(t *testing.T) {
+	tests := []struct {
+		name          string
+		intf          ipmi.Interface
+		cipherSuiteID ipmi.CipherSuiteID
+	}{
+		{name: "lanplus", intf: ipmi.InterfaceLanplus, cipherSuiteID: ipmi.CipherSuiteID2},
+		{name: "lan", intf: ipmi.InterfaceLan},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			s := newTestServer(t, func(s *ipmitest.Server) {
+				s.UnknownSessionCompletionCode = ipmi.CompletionCodeCannotExecuteCommandSecurityRestrict
+			})
+
+			var causes []error
+			c := newTestClient(t, s, tt.intf, testPassword).
+				WithCipherSuiteID(tt.cipherSuiteID).
+				WithAutoReconnect(func(cause error, err error) {
+					causes = append(causes, cause)
+				})
+			if err := c.Connect(ctx); err != nil {
+				t.Fatalf("Connect failed, err: %s", err)
+			}
+			defer c.Close(ctx)
+
+			// the BMC answers the requests of the dropped session outside of the session
+			s.CloseSessions()
+
+			start := time.Now()
+			if _, err := c.GetDeviceID(ctx); err != nil {
+				t.Fatalf("GetDeviceID after the session dropped failed, err: %s", err)
+			}
+			if elapsed := time.Since(start); elapsed >= time.Second {
+				t.Errorf("GetDeviceID took %s, want the session re-established without waiting for the timeout", elapsed)
+			}
+			if len(causes) != 1 || !errors.Is(causes[0], ipmi.ErrSessionInvalid) {
+				t.Errorf("got reconnect causes %v, want one ErrSessionInvalid", causes)
+			}
+		})
+	}
+}
@@ -0,0 +1,309 @@
+package ipmi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"net"
+	"os"
+	"strings"
+	"time"
+)
+
+// RetryPolicy decides which failed exchanges are retried and how long to wait before each retry.
+// It applies to the requests sent on all interfaces, see WithRetryPolicy.
+//
+// It works on top of WithRetry, which only resends the lan/lanplus packets timed out.
+type RetryPolicy struct {
+	// MaxRetries is the max count of retries after the first attempt.
+	MaxRetries int
+
+	// CompletionCodes lists the completion codes of the responses to retry.
+	CompletionCodes []CompletionCode
+
+	// RetryError reports whether the error which is not a ResponseError should be retried.
+	// nil means retrying the timeout errors.
+	// These errors are only retried for the requests reading the BMC, like the Get commands,
+	// as the BMC may have executed the request whose response was lost.
+	RetryError func(err error) bool
+
+	// InitialBackoff is the wait before the first retry, the wait is multiplied by Multiplier
+	// for each further retry and limited to MaxBackoff.
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+	Multiplier     float64
+
+	// Jitter is the fraction (0 to 1) of the wait which is randomly cut off,
+	// so the clients failed together do not retry together.
+	Jitter float64
+
+	// BeforeRetry is called before each retry with the error of the last attempt,
+	// it can update the request, like renewing its reservation ID, see RefreshReservation.
+	// The retry is abandoned if it returns an error.
+	BeforeRetry func(ctx context.Context, c *Client, request Request, err error) error
+}
+
+// DefaultRetryPolicy returns a RetryPolicy retrying up to 3 times the responses of node busy (0xC0),
+// timeout (0xC3), out of space (0xC4) and reservation canceled (0xC5), and the timeout errors
+// of the requests reading the BMC.
+// The reservation is renewed before retrying a request canceled for its reservation.
+func DefaultRetryPolicy() *RetryPolicy {
+	return &RetryPolicy{
+		MaxRetries: 3,
+		CompletionCodes: []CompletionCode{
+			CompletionCodeNodeBusy,
+			CompletionCodeProcessTimeout,
+			CompletionCodeOutOfSpace,
+			CompletionCodeReservationCanceled,
+		},
+		InitialBackoff: 100 * time.Millisecond,
+		MaxBackoff:     2 * time.Second,
+		Multiplier:     2,
+		Jitter:         0.2,
+		BeforeRetry:    RefreshReservation,
+	}
+}
+
+// WithRetryPolicy sets the RetryPolicy for the requests sent by Exchange, nil disables it.
+func (c *Client) WithRetryPolicy(policy *RetryPolicy) *Client {
+	c.retryPolicy = policy
+	return c
+}
+
+// ShouldRetry reports whether the exchange failed with err should be retried.
+func (p *RetryPolicy) ShouldRetry(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	if respErr, ok := isResponseError(err); ok {
+		for _, cc := range p.CompletionCodes {
+			if respErr.CompletionCode() == cc {
+				return true
+			}
+		}
+		return false
+	}
+
+	if p.RetryError != nil {
+		return p.RetryError(err)
+	}
+	return isTimeout(err)
+}
+
+// shouldRetry reports whether the request failed with err should be retried.
+// The errors other than the completion codes are only retried for the idempotent requests.
+func (p *RetryPolicy) shouldRetry(request Request, err error) bool {
+	if !p.ShouldRetry(err) {
+		return false
+	}
+	if _, ok := isResponseError(err); ok {
+		return true
+	}
+	return isIdempotent(request)
+}
+
+// Backoff returns the wait before the retry, retry starts from 1.
+func (p *RetryPolicy) Backoff(retry int) time.Duration {
+	multiplier := p.Multiplier
+	if multiplier < 1 {
+		multiplier = 1
+	}
+
+	backoff := float64(p.InitialBackoff) * math.Pow(multiplier, float64(retry-1))
+	if p.MaxBackoff > 0 && backoff > float64(p.MaxBackoff) {
+		backoff = float64(p.MaxBackoff)
+	}
+
+	if p.Jitter > 0 {
+		jitter := p.Jitter
+		if jitter > 1 {
+			jitter = 1
+		}
+		backoff -= backoff * jitter * rand.Float64()
+	}
+	return time.Duration(backoff)
+}
+
+// isTimeout reports whether err is caused by a request not answered in time.
+func isTimeout(err error) bool {
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+	return errors.Is(err, os.ErrDeadlineExceeded)
+}
+
+// isRetryable reports whether the request may be retried by the RetryPolicy,
+// the session activation requests depend on the session state changed by the previous attempt.
+//
+// The partial add requests are not retried either, their reservation ID is not renewed
+// by RefreshReservation and a lost reply leaves the offset unknown, so the whole record
+// is added again by AddSDRRecord and AddSELRecord.
+func isRetryable(request Request) bool {
+	switch request.(type) {
+	case *OpenSessionRequest, *RAKPMessage1, *RAKPMessage3:
+		return false
+	case *PartialAddSDRRequest, *PartialAddSELEntryRequest:
+		return false
+	}
+
+	switch request.Command() {
+	case CommandGetSessionChallenge, CommandActivateSession, CommandCloseSession:
+		return false
+	}
+	return true
+}
+
+// isIdempotent reports whether resending the request has no other effect than sending it once,
+// that is the request reads the BMC. The commands like Chassis Control, Add SEL Entry or Clear SEL
+// must not be resent if it is unknown whether the BMC executed them.
+//
+// Get Message and Read Event Message Buffer are not idempotent, they take the message out of the queue.
+// Reserve commands are, a reservation cancels the previous one which is not known to the client.
+func isIdempotent(request Request) bool {
+	cmd := request.Command()
+	switch cmd {
+	case CommandGetMessage, CommandReadEventMessageBuffer:
+		return false
+	}
+	for _, prefix := range []string{"Get ", "Read ", "Reserve "} {
+		if strings.HasPrefix(cmd.Name, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// exchangeRetry exchanges the request and retries it according to the RetryPolicy.
+func (c *Client) exchangeRetry(ctx context.Context, policy *RetryPolicy, request Request, response Response) error {
+	err := c.exchange(ctx, 1, request, response)
+
+	for retry := 1; retry <= policy.MaxRetries && policy.shouldRetry(request, err); retry++ {
+		backoff := policy.Backoff(retry)
+		c.Debugf("Retry %d/%d of request [%s] after %s, err: %s\n", retry, policy.MaxRetries, request.Command().Name, backoff, err)
+
+		timer := time.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+
+		if policy.BeforeRetry != nil {
+			if hookErr := policy.BeforeRetry(ctx, c, request, err); hookErr != nil {
+				return fmt.Errorf("%w, and prepare retry failed, err: %s", err, hookErr)
+			}
+		}
+
+		err = c.exchange(ctx, retry+1, request, response)
+	}
+
+	return err
+}
+
+// RefreshReservation is a BeforeRetry hook of RetryPolicy, it renews the reservation ID
+// of the request whose reservation was canceled (completion code 0xC5) by the BMC.
+//
+// The requests with a reservation ID are:
+// Get SDR, Get Device SDR, Delete SDR, Clear SDR Repository, Get SEL Entry, Delete SEL Entry and Clear SEL.
+// Partial Add SDR and Partial Add SEL Entry are not renewed, the partial add has to be restarted,
+// see AddSDRRecord and AddSELRecord.
+func RefreshReservation(ctx context.Context, c *Client, request Request, err error) error {
+	if respErr, ok := isResponseError(err); !ok || respErr.CompletionCode() != CompletionCodeReservationCanceled {
+		return nil
+	}
+
+	switch req := request.(type) {
+	case *GetSDRRequest:
+		res, err := c.ReserveSDRRepo(ctx)
+		if err != nil {
+			return fmt.Errorf("ReserveSDRRepo failed, err: %w", err)
+		}
+		req.ReservationID = res.ReservationID
+
+	case *DeleteSDRRequest:
+		res, err := c.ReserveSDRRepo(ctx)
+		if err != nil {
+			return fmt.Errorf("ReserveSDRRepo failed, err: %w", err)
+		}
+		req.ReservationID = res.ReservationID
+
+	case *ClearSDRRepoRequest:
+		res, err := c.ReserveSDRRepo(ctx)
+		if err != nil {
+			return fmt.Errorf("ReserveSDRRepo failed, err: %w", err)
+		}
+		req.ReservationID = res.ReservationID
+
+	case *GetDeviceSDRRequest:
+		res, err := c.ReserveDeviceSDRRepo(ctx)
+		if err != nil {
+			return fmt.Errorf("ReserveDeviceSDRRepo failed, err: %w", err)
+		}
+		req.ReservationID = res.ReservationID
+
+	case *GetSELEntryRequest:
+		res, err := c.ReserveSEL(ctx)
+		if err != nil {
+			return fmt.Errorf("ReserveSEL failed, err: %w", err)
+		}
+		req.ReservationID = res.ReservationID
+
+	case *DeleteSELEntryRequest:
+		res, err := c.ReserveSEL(ctx)
+		if err != nil {
+			return fmt.Errorf("ReserveSEL failed, err: %w", err)
+		}
+		req.ReservationID = res.ReservationID
+
+	case *ClearSELRequest:
+		res, err := c.ReserveSEL(ctx)
+		if err != nil {
+			return fmt.Errorf("ReserveSEL failed, err: %w", err)
+		}
+		req.ReservationID = res.ReservationID
+	}
+
+	return nil
+}
+
+// withReservation calls do with the reservation ID, and if the reservation is canceled (completion code 0xC5),
+// reserves again by reserve and calls do once more with the new reservation ID.
+// It returns the reservation ID to use for the next requests.
+//
+// do must restart the whole operation under the new reservation, like the Partial Add requests of a record.
+func (c *Client) withReservation(ctx context.Context, reservationID uint16, reserve func(ctx context.Context) (uint16, error), do func(reservationID uint16) error) (uint16, error) {
+	err := do(reservationID)
+	if respErr, ok := isResponseError(err); !ok || respErr.CompletionCode() != CompletionCodeReservationCanceled {
+		return reservationID, err
+	}
+
+	c.Debugf("reservation %#04x canceled, reserving again\n", reservationID)
+	reservationID, err = reserve(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return reservationID, do(reservationID)
+}
+
+// reserveSDRRepoID reserves the SDR Repository and returns the reservation ID, see withReservation.
+func (c *Client) reserveSDRRepoID(ctx context.Context) (uint16, error) {
+	res, err := c.ReserveSDRRepo(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("ReserveSDRRepo failed, err: %w", err)
+	}
+	return res.ReservationID, nil
+}
+
+// reserveSELID reserves the SEL and returns the reservation ID, see withReservation.
+func (c *Client) reserveSELID(ctx context.Context) (uint16, error) {
+	res, err := c.ReserveSEL(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("ReserveSEL failed, err: %w", err)
+	}
+	return res.ReservationID, nil
+}
@@ -0,0 +1,191 @@
+package ipmi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestRetryPolicy_ShouldRetry(t *testing.T) {
+	policy := DefaultRetryPolicy()
+
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{
+			name: "no error",
+			err:  nil,
+			want: false,
+		},
+		{
+			name: "node busy",
+			err:  fmt.Errorf("wrapped, err: %w", &ResponseError{completionCode: CompletionCodeNodeBusy}),
+			want: true,
+		},
+		{
+			name: "reservation canceled",
+			err:  &ResponseError{completionCode: CompletionCodeReservationCanceled},
+			want: true,
+		},
+		{
+			name: "invalid command",
+			err:  &ResponseError{completionCode: CompletionCodeInvalidCommand},
+			want: false,
+		},
+		{
+			name: "udp timeout",
+			err:  fmt.Errorf("client udp exchange msg failed, err: %w", &net.OpError{Op: "read", Net: "udp", Err: os.ErrDeadlineExceeded}),
+			want: true,
+		},
+		{
+			name: "other error",
+			err:  errors.New("unpack response failed"),
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := policy.ShouldRetry(tt.err); got != tt.want {
+				t.Errorf("ShouldRetry() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRetryPolicy_shouldRetry(t *testing.T) {
+	policy := DefaultRetryPolicy()
+
+	timeout := fmt.Errorf("client udp exchange msg failed, err: %w", &net.OpError{Op: "read", Net: "udp", Err: os.ErrDeadlineExceeded})
+	busy := &ResponseError{completionCode: CompletionCodeNodeBusy}
+
+	tests := []struct {
+		name    string
+		request Request
+		err     error
+		want    bool
+	}{
+		{name: "get device id timeout", request: &GetDeviceIDRequest{}, err: timeout, want: true},
+		{name: "get sel entry timeout", request: &GetSELEntryRequest{}, err: timeout, want: true},
+		{name: "reserve sel timeout", request: &ReserveSELRequest{}, err: timeout, want: true},
+		{name: "read fru data timeout", request: &ReadFRUDataRequest{}, err: timeout, want: true},
+		{name: "get message timeout", request: &GetMessageRequest{}, err: timeout, want: false},
+		{name: "chassis control timeout", request: &ChassisControlRequest{}, err: timeout, want: false},
+		{name: "add sel entry timeout", request: &AddSELEntryRequest{}, err: timeout, want: false},
+		{name: "add sdr timeout", request: &AddSDRRequest{}, err: timeout, want: false},
+		{name: "delete sel entry timeout", request: &DeleteSELEntryRequest{}, err: timeout, want: false},
+		{name: "clear sel timeout", request: &ClearSELRequest{}, err: timeout, want: false},
+		{name: "set user password timeout", request: &SetUserPasswordRequest{}, err: timeout, want: false},
+		{name: "chassis control node busy", request: &ChassisControlRequest{}, err: busy, want: true},
+		{name: "clear sel node busy", request: &ClearSELRequest{}, err: busy, want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := policy.shouldRetry(tt.request, tt.err); got != tt.want {
+				t.Errorf("shouldRetry() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsRetryable(t *testing.T) {
+	tests := []struct {
+		name    string
+		request Request
+		want    bool
+	}{
+		{name: "get sdr", request: &GetSDRRequest{}, want: true},
+		{name: "get sel entry", request: &GetSELEntryRequest{}, want: true},
+		{name: "open session", request: &OpenSessionRequest{}, want: false},
+		{name: "close session", request: &CloseSessionRequest{}, want: false},
+		{name: "partial add sdr", request: &PartialAddSDRRequest{}, want: false},
+		{name: "partial add sel entry", request: &PartialAddSELEntryRequest{}, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isRetryable(tt.request); got != tt.want {
+				t.Errorf("isRetryable() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRetryPolicy_Backoff(t *testing.T) {
+	policy := &RetryPolicy{
+		InitialBackoff: 100 * time.Millisecond,
+		MaxBackoff:     time.Second,
+		Multiplier:     2,
+		Jitter:         0.5,
+	}
+
+	tests := []struct {
+		retry int
+		max   time.Duration
+	}{
+		{retry: 1, max: 100 * time.Millisecond},
+		{retry: 2, max: 200 * time.Millisecond},
+		{retry: 3, max: 400 * time.Millisecond},
+		{retry: 4, max: 800 * time.Millisecond},
+		{retry: 5, max: time.Second},
+		{retry: 10, max: time.Second},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("retry %d", tt.retry), func(t *testing.T) {
+			for i := 0; i < 100; i++ {
+				got := policy.Backoff(tt.retry)
+				if got > tt.max || got < tt.max/2 {
+					t.Fatalf("Backoff() = %s, want between %s and %s", got, tt.max/2, tt.max)
+				}
+			}
+		})
+	}
+}
+
+func TestClient_withReservation(t *testing.T) {
+	canceled := &ResponseError{completionCode: CompletionCodeReservationCanceled}
+
+	tests := []struct {
+		name      string
+		errs      []error
+		wantCalls []uint16
+		wantID    uint16
+		wantErr   bool
+	}{
+		{name: "success", errs: []error{nil}, wantCalls: []uint16{1}, wantID: 1},
+		{name: "canceled once", errs: []error{canceled, nil}, wantCalls: []uint16{1, 2}, wantID: 2},
+		{name: "canceled twice", errs: []error{canceled, canceled}, wantCalls: []uint16{1, 2}, wantID: 2, wantErr: true},
+		{name: "other error", errs: []error{errors.New("failed"), nil}, wantCalls: []uint16{1}, wantID: 1, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &Client{}
+			ctx := context.Background()
+
+			next := uint16(1)
+			reserve := func(ctx context.Context) (uint16, error) {
+				next++
+				return next, nil
+			}
+
+			var calls []uint16
+			id, err := c.withReservation(ctx, 1, reserve, func(reservationID uint16) error {
+				calls = append(calls, reservationID)
+				return tt.errs[len(calls)-1]
+			})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("withReservation() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if id != tt.wantID {
+				t.Errorf("withReservation() = %d, want %d", id, tt.wantID)
+			}
+			if fmt.Sprint(calls) != fmt.Sprint(tt.wantCalls) {
+				t.Errorf("do called with %v, want %v", calls, tt.wantCalls)
+			}
+		})
+	}
+}
@@ -0,0 +1,67 @@
+package ipmi_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/bougou/go-ipmi"
+	"github.com/bougou/go-ipmi/ipmitest"
+)
+
+func TestClient_RetryPolicy(t *testing.T) {
+	policy := ipmi.DefaultRetryPolicy()
+	policy.InitialBackoff = time.Millisecond
+
+	tests := []struct {
+		name    string
+		policy  *ipmi.RetryPolicy
+		wantErr bool
+	}{
+		{name: "default policy", policy: policy},
+		{name: "no policy", policy: nil, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+
+			busy := 2
+			s := newTestServer(t, func(s *ipmitest.Server) {
+				s.Handle(ipmi.CommandGetDeviceID, func(req *ipmitest.Request) (ipmi.CompletionCode, []byte) {
+					if busy > 0 {
+						busy--
+						return ipmi.CompletionCodeNodeBusy, nil
+					}
+					return ipmi.CompletionCodeNormal, ipmitest.DefaultDeviceInfo.Pack()
+				})
+			})
+
+			c := newTestClient(t, s, ipmi.InterfaceLanplus, testPassword).WithRetryPolicy(tt.policy)
+			if err := c.Connect(ctx); err != nil {
+				t.Fatalf("Connect failed, err: %s", err)
+			}
+			defer c.Close(ctx)
+
+			if _, err := c.GetDeviceID(ctx); (err != nil) != tt.wantErr {
+				t.Fatalf("GetDeviceID error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			// a partial read with a canceled reservation
+			request := &ipmi.GetSDRRequest{
+				ReservationID: 0xdead,
+				RecordID:      0,
+				ReadOffset:    5,
+				ReadBytes:     5,
+			}
+			response := &ipmi.GetSDRResponse{}
+			if err := c.Exchange(ctx, request, response); (err != nil) != tt.wantErr {
+				t.Fatalf("Exchange GetSDR error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && len(response.RecordData) != 5 {
+				t.Errorf("GetSDR returned %d bytes, want 5", len(response.RecordData))
+			}
+		})
+	}
+}
@@ -0,0 +1,268 @@
+package ipmi
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// sdrCache holds the SDR records of the SDR Repositories read by the Client, keyed by the GUID of the controller.
+// see WithSDRCache
+type sdrCache struct {
+	// dir is the directory the records are persisted to, empty means the records are only kept in memory
+	dir string
+
+	// loaded holds the records loaded by LoadSDRCache, which are used instead of the SDR Repository
+	loaded []sdrCacheRecord
+
+	// mu is held while the records are validated or read, so concurrent callers share one repository walk
+	mu      sync.Mutex
+	guids   map[ipmbBridge]string
+	entries map[string]*sdrCacheEntry
+}
+
+// sdrCacheEntry is the cached SDR Repository of a controller, it is stored as JSON on disk.
+type sdrCacheEntry struct {
+	GUID string `json:"guid"`
+
+	// the SDR Repository Info the records were read with, see isValid
+	RecordCount  uint16    `json:"record_count"`
+	AdditionTime time.Time `json:"addition_time"`
+	EraseTime    time.Time `json:"erase_time"`
+
+	Records []sdrCacheRecord `json:"records"`
+}
+
+type sdrCacheRecord struct {
+	Data         []byte `json:"data"`
+	NextRecordID uint16 `json:"next_record_id"`
+}
+
+// isValid reports whether the SDR Repository is not changed since the records were read,
+// that is no record is added or erased according to the SDR Repository Info.
+func (e *sdrCacheEntry) isValid(repoInfo *GetSDRRepoInfoResponse) bool {
+	return e.RecordCount == repoInfo.RecordCount &&
+		e.AdditionTime.Equal(repoInfo.MostRecentAdditionTime) &&
+		e.EraseTime.Equal(repoInfo.MostRecentEraseTime)
+}
+
+// WithSDRCache caches the SDR records read by GetSDRs, GetSDRsMap, GetSDRBySensorID, GetSDRBySensorName,
+// GetSensors and GetFRUs, so only the sensor readings and FRU data are read from the BMC after the first call.
+//
+// The records are cached per controller, keyed by its Device GUID (or System GUID if Get Device GUID is not supported).
+// The cache is validated by a Get SDR Repository Info request before each use, and the records are read again once
+// the most recent addition/erase timestamp or the record count of the SDR Repository is changed.
+//
+// If dir is not empty, the records are also persisted to the "sdr-<guid>.json" files in dir,
+// so they survive the Client, like the SDR cache of ipmitool.
+func (c *Client) WithSDRCache(dir string) *Client {
+	c.sdrCache = &sdrCache{
+		dir:     dir,
+		guids:   make(map[ipmbBridge]string),
+		entries: make(map[string]*sdrCacheEntry),
+	}
+	return c
+}
+
+// InvalidateSDRCache drops the cached SDR records of all controllers, both in memory and on disk,
+// and the records loaded by LoadSDRCache.
+// It is not needed for the changes of the SDR Repository, which are detected by the timestamps.
+func (c *Client) InvalidateSDRCache() error {
+	if c.sdrCache == nil {
+		return nil
+	}
+
+	c.sdrCache.mu.Lock()
+	defer c.sdrCache.mu.Unlock()
+
+	c.sdrCache.loaded = nil
+	for guid := range c.sdrCache.entries {
+		delete(c.sdrCache.entries, guid)
+	}
+
+	if c.sdrCache.dir == "" {
+		return nil
+	}
+	files, err := filepath.Glob(filepath.Join(c.sdrCache.dir, "sdr-*.json"))
+	if err != nil {
+		return fmt.Errorf("list SDR cache files failed, err: %w", err)
+	}
+	for _, file := range files {
+		if err := os.Remove(file); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("remove SDR cache file failed, err: %w", err)
+		}
+	}
+	return nil
+}
+
+// getSDRRecords returns the raw records of the whole SDR Repository in the order of the record IDs,
+// from the cache if it is enabled and valid.
+func (c *Client) getSDRRecords(ctx context.Context) ([]sdrCacheRecord, error) {
+	if c.sdrCache == nil {
+		return c.readSDRRecords(ctx)
+	}
+
+	c.sdrCache.mu.Lock()
+	defer c.sdrCache.mu.Unlock()
+
+	if c.sdrCache.loaded != nil {
+		return c.sdrCache.loaded, nil
+	}
+
+	guid, err := c.sdrCacheGUID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	repoInfo, err := c.GetSDRRepoInfo(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("GetSDRRepoInfo failed, err: %w", err)
+	}
+
+	entry := c.sdrCache.entries[guid]
+	if entry == nil {
+		entry = c.loadSDRCacheEntry(guid)
+	}
+	if entry != nil && entry.isValid(repoInfo) {
+		c.sdrCache.entries[guid] = entry
+		c.Debugf("SDR cache hit for GUID %s, %d records\n", guid, len(entry.Records))
+		return entry.Records, nil
+	}
+
+	c.Debugf("SDR cache miss for GUID %s, reading SDR Repository\n", guid)
+	records, err := c.readSDRRecords(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	entry = &sdrCacheEntry{
+		GUID:         guid,
+		RecordCount:  repoInfo.RecordCount,
+		AdditionTime: repoInfo.MostRecentAdditionTime,
+		EraseTime:    repoInfo.MostRecentEraseTime,
+		Records:      records,
+	}
+	c.sdrCache.entries[guid] = entry
+	c.saveSDRCacheEntry(entry)
+
+	return records, nil
+}
+
+// readSDRRecords walks the SDR Repository and returns the raw records.
+func (c *Client) readSDRRecords(ctx context.Context) ([]sdrCacheRecord, error) {
+	var recordID uint16 = 0
+	var out = make([]sdrCacheRecord, 0)
+	for {
+		res, err := c.GetSDR(ctx, recordID)
+		if err != nil {
+			return nil, fmt.Errorf("GetSDR failed for recordID (%#02x), err: %w", recordID, err)
+		}
+		out = append(out, sdrCacheRecord{
+			Data:         res.RecordData,
+			NextRecordID: res.NextRecordID,
+		})
+
+		recordID = res.NextRecordID
+		if recordID == 0xffff {
+			break
+		}
+	}
+	return out, nil
+}
+
+// sdrCacheGUID returns the GUID of the controller whose SDR Repository is read by the requests,
+// the controller differs for the requests bridged by WithTarget or the CommandContext.
+// The caller must hold the lock of the cache.
+func (c *Client) sdrCacheGUID(ctx context.Context) (string, error) {
+	var key ipmbBridge
+	if bridge := c.bridgeFor(ctx, &GetSDRRequest{}); bridge != nil {
+		key = *bridge
+	}
+	if guid, ok := c.sdrCache.guids[key]; ok {
+		return guid, nil
+	}
+
+	var guid [16]byte
+	if res, err := c.GetDeviceGUID(ctx); err == nil {
+		guid = res.GUID
+	} else {
+		res, err := c.GetSystemGUID(ctx)
+		if err != nil {
+			return "", fmt.Errorf("GetDeviceGUID and GetSystemGUID failed, err: %w", err)
+		}
+		guid = res.GUID
+	}
+
+	c.sdrCache.guids[key] = hex.EncodeToString(guid[:])
+	return c.sdrCache.guids[key], nil
+}
+
+func (c *Client) sdrCacheFile(guid string) string {
+	return filepath.Join(c.sdrCache.dir, "sdr-"+guid+".json")
+}
+
+// loadSDRCacheEntry reads the cached records of the controller from disk, nil if not found or not readable.
+func (c *Client) loadSDRCacheEntry(guid string) *sdrCacheEntry {
+	if c.sdrCache.dir == "" {
+		return nil
+	}
+
+	b, err := os.ReadFile(c.sdrCacheFile(guid))
+	if err != nil {
+		if !os.IsNotExist(err) {
+			c.Debugf("read SDR cache file failed, err: %s\n", err)
+		}
+		return nil
+	}
+
+	entry := &sdrCacheEntry{}
+	if err := json.Unmarshal(b, entry); err != nil || entry.GUID != guid {
+		c.Debugf("discard invalid SDR cache file %s\n", c.sdrCacheFile(guid))
+		return nil
+	}
+	return entry
+}
+
+// saveSDRCacheEntry persists the cached records to disk, the records are still cached in memory if it fails.
+func (c *Client) saveSDRCacheEntry(entry *sdrCacheEntry) {
+	if c.sdrCache.dir == "" {
+		return
+	}
+
+	if err := writeFileAtomic(c.sdrCacheFile(entry.GUID), entry); err != nil {
+		c.DebugfRed("write SDR cache file failed, err: %s\n", err)
+	}
+}
+
+// writeFileAtomic writes the JSON of v to a temporary file which is then renamed to file,
+// so readers never see a partially written file.
+func writeFileAtomic(file string, v interface{}) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(file), 0o755); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(file), filepath.Base(file)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(b); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), file)
+}
@@ -0,0 +1,73 @@
+package ipmi_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/bougou/go-ipmi"
+	"github.com/bougou/go-ipmi/ipmitest"
+)
+
+func TestClient_SDRCache(t *testing.T) {
+	ctx := context.Background()
+
+	s := newTestServer(t)
+	dir := t.TempDir()
+
+	var getSDRs int
+	countGetSDR := func(ctx context.Context, request ipmi.Request, response ipmi.Response, next ipmi.ExchangeFunc) error {
+		if request.Command() == ipmi.CommandGetSDR {
+			getSDRs++
+		}
+		return next(ctx, request, response)
+	}
+
+	newClient := func() *ipmi.Client {
+		c := newTestClient(t, s, ipmi.InterfaceLanplus, testPassword).WithSDRCache(dir).WithInterceptor(countGetSDR)
+		if err := c.Connect(ctx); err != nil {
+			t.Fatalf("Connect failed, err: %s", err)
+		}
+		t.Cleanup(func() { c.Close(ctx) })
+		return c
+	}
+
+	getSensors := func(c *ipmi.Client, want int, wantGetSDRs int) {
+		t.Helper()
+
+		getSDRs = 0
+		sensors, err := c.GetSensors(ctx)
+		if err != nil {
+			t.Fatalf("GetSensors failed, err: %s", err)
+		}
+		if len(sensors) != want {
+			t.Errorf("GetSensors returned %d sensors, want %d", len(sensors), want)
+		}
+		if getSDRs != wantGetSDRs {
+			t.Errorf("GetSensors sent %d Get SDR requests, want %d", getSDRs, wantGetSDRs)
+		}
+	}
+
+	c := newClient()
+	getSensors(c, 1, 1)
+	getSensors(c, 1, 0)
+
+	if _, err := c.GetSensorByName(ctx, "CPU Temp"); err != nil {
+		t.Errorf("GetSensorByName failed, err: %s", err)
+	}
+	if getSDRs != 0 {
+		t.Errorf("GetSensorByName sent %d Get SDR requests, want 0", getSDRs)
+	}
+
+	// the record added to the SDR Repository invalidates the cache
+	s.AddSDR(fullSensorSDR(0x02, "PCH Temp"))
+	s.SetSensor(0x02, ipmitest.Sensor{Reading: 50})
+	getSensors(c, 2, 2)
+
+	// the records persisted to disk are used by a new client
+	getSensors(newClient(), 2, 0)
+
+	if err := c.InvalidateSDRCache(); err != nil {
+		t.Fatalf("InvalidateSDRCache failed, err: %s", err)
+	}
+	getSensors(c, 2, 2)
+}
@@ -0,0 +1,136 @@
+package ipmi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+)
+
+// DumpSDRs writes the raw records of the SDR Repository (record header plus record body) back to back to w,
+// the same format as "ipmitool sdr dump". The records are read through the SDR cache if it is enabled.
+func (c *Client) DumpSDRs(ctx context.Context, w io.Writer) error {
+	records, err := c.getSDRRecords(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, record := range records {
+		if _, err := w.Write(record.Data); err != nil {
+			return fmt.Errorf("write SDR record failed, err: %w", err)
+		}
+	}
+	return nil
+}
+
+// LoadSDRs parses the raw SDR records written by DumpSDRs or "ipmitool sdr dump",
+// the returned SDRs can be formatted offline, e.g. by FormatSDRs.
+func LoadSDRs(r io.Reader) ([]*SDR, error) {
+	records, err := readSDRDump(r)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]*SDR, 0, len(records))
+	for _, record := range records {
+		sdr, err := ParseSDR(record.Data, record.NextRecordID)
+		if err != nil {
+			return nil, fmt.Errorf("ParseSDR failed, err: %w", err)
+		}
+		out = append(out, sdr)
+	}
+	return out, nil
+}
+
+// LoadSDRCache makes the Client use the raw SDR records read from r, written by DumpSDRs or "ipmitool sdr dump",
+// instead of reading the SDR Repository, like the -S option of ipmitool.
+// The records are used as is, they are not validated against the SDR Repository of the BMC.
+func (c *Client) LoadSDRCache(r io.Reader) error {
+	records, err := readSDRDump(r)
+	if err != nil {
+		return err
+	}
+
+	if c.sdrCache == nil {
+		c.WithSDRCache("")
+	}
+	c.sdrCache.mu.Lock()
+	defer c.sdrCache.mu.Unlock()
+
+	c.sdrCache.loaded = records
+	return nil
+}
+
+// readSDRDump reads the raw SDR records written back to back,
+// the next record ID of each record is the record ID of the following one, 0xffff for the last one.
+func readSDRDump(r io.Reader) ([]sdrCacheRecord, error) {
+	out := make([]sdrCacheRecord, 0)
+	for {
+		header := make([]byte, SDRRecordHeaderSize)
+		if _, err := io.ReadFull(r, header); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return nil, fmt.Errorf("read SDR record header (record %d) failed, err: %w", len(out), err)
+		}
+
+		data := make([]byte, SDRRecordHeaderSize+int(header[4]))
+		copy(data, header)
+		if _, err := io.ReadFull(r, data[SDRRecordHeaderSize:]); err != nil {
+			if errors.Is(err, io.EOF) {
+				err = io.ErrUnexpectedEOF
+			}
+			return nil, fmt.Errorf("read SDR record body (record %d) failed, err: %w", len(out), err)
+		}
+
+		if len(out) > 0 {
+			recordID, _, _ := unpackUint16L(data, 0)
+			out[len(out)-1].NextRecordID = recordID
+		}
+		out = append(out, sdrCacheRecord{Data: data, NextRecordID: 0xffff})
+	}
+	return out, nil
+}
+
+// sdrErasurePollInterval is the interval to poll the erasure status of the SDR Repository by FillSDRRepo.
+const sdrErasurePollInterval = 500 * time.Millisecond
+
+// FillSDRRepo replaces the records of the SDR Repository with the raw SDR records read from r,
+// written by DumpSDRs or "ipmitool sdr dump". The SDR Repository is cleared first,
+// then the records are added in order, the BMC assigns new Record IDs to them.
+// The modal SDR Repository is put into the update mode while it is written.
+func (c *Client) FillSDRRepo(ctx context.Context, r io.Reader) (err error) {
+	records, err := readSDRDump(r)
+	if err != nil {
+		return err
+	}
+
+	repoInfo, err := c.GetSDRRepoInfo(ctx)
+	if err != nil {
+		return fmt.Errorf("GetSDRRepoInfo failed, err: %w", err)
+	}
+
+	support := repoInfo.SDROperationSupport
+	if support.SupportModalSDRRepoUpdate && !support.SupportNonModalSDRRepoUpdate {
+		if _, err := c.EnterSDRRepoUpdateMode(ctx); err != nil {
+			return fmt.Errorf("EnterSDRRepoUpdateMode failed, err: %w", err)
+		}
+		defer func() {
+			if _, exitErr := c.ExitSDRRepoUpdateMode(ctx); exitErr != nil && err == nil {
+				err = fmt.Errorf("ExitSDRRepoUpdateMode failed, err: %w", exitErr)
+			}
+		}()
+	}
+
+	if err := c.ClearSDRRepoAndWait(ctx, sdrErasurePollInterval); err != nil {
+		return err
+	}
+
+	for i, record := range records {
+		if _, err := c.AddSDRRecord(ctx, record.Data); err != nil {
+			return fmt.Errorf("add SDR record %d failed, err: %w", i, err)
+		}
+	}
+	return nil
+}
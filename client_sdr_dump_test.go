@@ -0,0 +1,75 @@
+package ipmi_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/bougou/go-ipmi"
+	"github.com/bougou/go-ipmi/ipmitest"
+)
+
+func TestClient_DumpSDRs(t *testing.T) {
+	ctx := context.Background()
+
+	s := newTestServer(t, func(s *ipmitest.Server) {
+		s.AddSDR(fullSensorSDR(0x02, "PCH Temp"))
+		s.SetSensor(0x02, ipmitest.Sensor{Reading: 50})
+	})
+
+	c := newTestClient(t, s, ipmi.InterfaceLanplus, testPassword)
+	if err := c.Connect(ctx); err != nil {
+		t.Fatalf("Connect failed, err: %s", err)
+	}
+	defer c.Close(ctx)
+
+	var buf bytes.Buffer
+	if err := c.DumpSDRs(ctx, &buf); err != nil {
+		t.Fatalf("DumpSDRs failed, err: %s", err)
+	}
+	dump := buf.Bytes()
+
+	sdrs, err := ipmi.LoadSDRs(bytes.NewReader(dump))
+	if err != nil {
+		t.Fatalf("LoadSDRs failed, err: %s", err)
+	}
+	if len(sdrs) != 2 {
+		t.Fatalf("LoadSDRs returned %d SDRs, want 2", len(sdrs))
+	}
+	for i, want := range []string{"CPU Temp", "PCH Temp"} {
+		if got := sdrs[i].SensorName(); got != want {
+			t.Errorf("SDR %d is %q, want %q", i, got, want)
+		}
+	}
+	if sdrs[0].NextRecordID != sdrs[1].RecordHeader.RecordID || sdrs[1].NextRecordID != 0xffff {
+		t.Errorf("LoadSDRs returned next record IDs %#04x, %#04x", sdrs[0].NextRecordID, sdrs[1].NextRecordID)
+	}
+
+	if _, err := ipmi.LoadSDRs(bytes.NewReader(dump[:len(dump)-1])); !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Errorf("LoadSDRs of truncated dump returned err %v, want %v", err, io.ErrUnexpectedEOF)
+	}
+
+	// the loaded records are used instead of the SDR Repository
+	var getSDRs int
+	c.WithInterceptor(func(ctx context.Context, request ipmi.Request, response ipmi.Response, next ipmi.ExchangeFunc) error {
+		if request.Command() == ipmi.CommandGetSDR {
+			getSDRs++
+		}
+		return next(ctx, request, response)
+	})
+	if err := c.LoadSDRCache(bytes.NewReader(dump)); err != nil {
+		t.Fatalf("LoadSDRCache failed, err: %s", err)
+	}
+	sensors, err := c.GetSensors(ctx)
+	if err != nil {
+		t.Fatalf("GetSensors failed, err: %s", err)
+	}
+	if len(sensors) != 2 || sensors[1].Value != 50 {
+		t.Errorf("GetSensors returned %d sensors, want 2 with PCH Temp 50", len(sensors))
+	}
+	if getSDRs != 0 {
+		t.Errorf("GetSensors sent %d Get SDR requests, want 0", getSDRs)
+	}
+}
@@ -0,0 +1,348 @@
+package ipmi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+)
+
+// defaultSELWatchInterval is the interval WatchSEL polls the SEL at if WatchSELOptions.Interval is not set.
+const defaultSELWatchInterval = 10 * time.Second
+
+// SELResetReason tells why WatchSEL lost its position in the SEL and continues from the first record.
+type SELResetReason string
+
+const (
+	// SELResetCleared means the SEL is cleared (or the record last delivered is deleted),
+	// all the records in the SEL are new.
+	SELResetCleared SELResetReason = "cleared"
+
+	// SELResetWrapped means the record last delivered is overwritten by the BMC as the SEL is full,
+	// some records may be lost.
+	SELResetWrapped SELResetReason = "wrapped"
+)
+
+// SELBookmark is the position of WatchSEL in the SEL, that is the record last delivered.
+type SELBookmark struct {
+	RecordID uint16 `json:"record_id"`
+
+	// Data is the record, it tells whether the Record ID is reused by a new record after the SEL is cleared.
+	// Empty if not known.
+	Data []byte `json:"data,omitempty"`
+
+	// EraseTime is the most recent erase timestamp of the SEL when the record is delivered.
+	// Zero if not known.
+	EraseTime time.Time `json:"erase_time"`
+}
+
+// SELBookmarkStore persists the SELBookmark of WatchSEL, so the SEL is followed from where it is left across restarts.
+type SELBookmarkStore interface {
+	// LoadSELBookmark returns the saved bookmark, nil if nothing is saved.
+	LoadSELBookmark(ctx context.Context) (*SELBookmark, error)
+	SaveSELBookmark(ctx context.Context, bookmark *SELBookmark) error
+}
+
+type selFileBookmark struct {
+	file string
+}
+
+// SELFileBookmark returns a SELBookmarkStore which saves the bookmark as JSON to the local file.
+func SELFileBookmark(file string) SELBookmarkStore {
+	return &selFileBookmark{file: file}
+}
+
+func (s *selFileBookmark) LoadSELBookmark(ctx context.Context) (*SELBookmark, error) {
+	b, err := os.ReadFile(s.file)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("read SEL bookmark file failed, err: %w", err)
+	}
+
+	bookmark := &SELBookmark{}
+	if err := json.Unmarshal(b, bookmark); err != nil {
+		return nil, fmt.Errorf("unmarshal SEL bookmark file failed, err: %w", err)
+	}
+	return bookmark, nil
+}
+
+func (s *selFileBookmark) SaveSELBookmark(ctx context.Context, bookmark *SELBookmark) error {
+	if err := writeFileAtomic(s.file, bookmark); err != nil {
+		return fmt.Errorf("write SEL bookmark file failed, err: %w", err)
+	}
+	return nil
+}
+
+type selBMCBookmark struct {
+	client *Client
+}
+
+// SELBMCBookmark returns a SELBookmarkStore which saves the Record ID of the bookmark on the BMC,
+// as the last record processed by software, see SetLastProcessedEventId.
+// Only the Record ID is saved, so the reuse of the Record ID after the SEL is cleared is not noticed.
+func (c *Client) SELBMCBookmark() SELBookmarkStore {
+	return &selBMCBookmark{client: c}
+}
+
+func (s *selBMCBookmark) LoadSELBookmark(ctx context.Context) (*SELBookmark, error) {
+	res, err := s.client.GetLastProcessedEventId(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("GetLastProcessedEventId failed, err: %w", err)
+	}
+
+	// 0000h and FFFFh are not legal Record IDs
+	recordID := res.LastSoftwareProcessedEventRecordID
+	if recordID == 0x0000 || recordID == 0xffff {
+		return nil, nil
+	}
+	return &SELBookmark{RecordID: recordID}, nil
+}
+
+func (s *selBMCBookmark) SaveSELBookmark(ctx context.Context, bookmark *SELBookmark) error {
+	if _, err := s.client.SetLastProcessedEventId(ctx, bookmark.RecordID, false); err != nil {
+		return fmt.Errorf("SetLastProcessedEventId failed, err: %w", err)
+	}
+	return nil
+}
+
+// WatchSELOptions are the options of WatchSEL.
+type WatchSELOptions struct {
+	// Interval is the interval to poll the SEL Info at, 10 seconds if 0.
+	Interval time.Duration
+
+	// FromStart delivers the records already in the SEL if no bookmark is saved,
+	// by default only the records added after WatchSEL is called are delivered.
+	FromStart bool
+
+	// Bookmark saves the record last delivered after each poll, and WatchSEL continues after the saved record.
+	Bookmark SELBookmarkStore
+
+	// OnReset is called when the record last delivered is not found in the SEL anymore,
+	// the records are delivered again from the first record of the SEL.
+	OnReset func(reason SELResetReason)
+
+	// OnError is called with the errors of the polls, the SEL is polled again after the interval.
+	OnError func(err error)
+}
+
+type selWatch struct {
+	client *Client
+	opts   WatchSELOptions
+	ch     chan *SEL
+
+	// info is the SEL Info the records are last read with, nil to read the records on the next poll
+	info *GetSELInfoResponse
+	// bookmark is the record last delivered, nil to deliver from the first record
+	bookmark *SELBookmark
+}
+
+// WatchSEL follows the SEL like "tail -f", the records added to the SEL are sent to the returned channel.
+//
+// The SEL Info is polled on an interval, and only the records after the record last delivered are read
+// once the entry count or the timestamps of the SEL are changed.
+// The channel is closed when ctx is done. A poll is blocked until the records are received from the channel.
+//
+// Example usage:
+//
+//	ch, err := client.WatchSEL(ctx, &ipmi.WatchSELOptions{
+//	    Bookmark: ipmi.SELFileBookmark("/var/lib/myapp/sel-bookmark.json"),
+//	})
+//	for sel := range ch {
+//	    fmt.Println(sel.RecordID)
+//	}
+func (c *Client) WatchSEL(ctx context.Context, opts *WatchSELOptions) (<-chan *SEL, error) {
+	w := &selWatch{
+		client: c,
+		ch:     make(chan *SEL),
+	}
+	if opts != nil {
+		w.opts = *opts
+	}
+	if w.opts.Interval <= 0 {
+		w.opts.Interval = defaultSELWatchInterval
+	}
+
+	if err := w.init(ctx); err != nil {
+		return nil, err
+	}
+
+	go w.run(ctx)
+	return w.ch, nil
+}
+
+// init positions the watch after the saved bookmark, or after the last record of the SEL.
+func (w *selWatch) init(ctx context.Context) error {
+	if w.opts.Bookmark != nil {
+		bookmark, err := w.opts.Bookmark.LoadSELBookmark(ctx)
+		if err != nil {
+			return fmt.Errorf("LoadSELBookmark failed, err: %w", err)
+		}
+		if bookmark != nil {
+			w.bookmark = bookmark
+			return nil
+		}
+	}
+
+	if w.opts.FromStart {
+		return nil
+	}
+
+	info, err := w.client.GetSELInfo(ctx)
+	if err != nil {
+		return fmt.Errorf("GetSELInfo failed, err: %w", err)
+	}
+	w.info = info
+	if info.Entries == 0 {
+		return nil
+	}
+
+	res, err := w.client.GetSELEntry(ctx, 0, 0xffff)
+	if err != nil {
+		return fmt.Errorf("GetSELEntry failed, err: %w", err)
+	}
+	sel, err := ParseSEL(res.Data)
+	if err != nil {
+		return fmt.Errorf("ParseSEL failed, err: %w", err)
+	}
+	w.bookmark = &SELBookmark{RecordID: sel.RecordID, Data: sel.Pack(), EraseTime: info.RecentEraseTime}
+	return nil
+}
+
+func (w *selWatch) run(ctx context.Context) {
+	defer close(w.ch)
+
+	ticker := time.NewTicker(w.opts.Interval)
+	defer ticker.Stop()
+
+	for {
+		if err := w.poll(ctx); err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			if w.opts.OnError != nil {
+				w.opts.OnError(err)
+			} else {
+				w.client.Debugf("WatchSEL poll failed, err: %s\n", err)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// poll delivers the records added since the last poll.
+func (w *selWatch) poll(ctx context.Context) error {
+	info, err := w.client.GetSELInfo(ctx)
+	if err != nil {
+		return fmt.Errorf("GetSELInfo failed, err: %w", err)
+	}
+
+	if w.info != nil && info.Entries == w.info.Entries &&
+		info.RecentAdditionTime.Equal(w.info.RecentAdditionTime) &&
+		info.RecentEraseTime.Equal(w.info.RecentEraseTime) {
+		return nil
+	}
+
+	var startRecordID uint16 = 0x0000
+	if w.bookmark != nil {
+		nextRecordID, found, err := w.locate(ctx)
+		if err != nil {
+			return err
+		}
+
+		switch {
+		case !found:
+			w.reset(info)
+		case nextRecordID == 0xffff:
+			w.info = info
+			return nil
+		default:
+			startRecordID = nextRecordID
+		}
+	}
+
+	if info.Entries == 0 {
+		w.info = info
+		return nil
+	}
+
+	sels, err := w.client.GetSELEntries(ctx, startRecordID)
+	if err != nil {
+		return fmt.Errorf("GetSELEntries failed, err: %w", err)
+	}
+
+	delivered := 0
+	for _, sel := range sels {
+		select {
+		case w.ch <- sel:
+		case <-ctx.Done():
+			// the records delivered are still bookmarked
+			_ = w.saveBookmark(context.WithoutCancel(ctx), delivered)
+			return ctx.Err()
+		}
+		w.bookmark = &SELBookmark{RecordID: sel.RecordID, Data: sel.Pack(), EraseTime: info.RecentEraseTime}
+		delivered++
+	}
+	w.info = info
+
+	// the records delivered are bookmarked even if ctx is done once they are received
+	return w.saveBookmark(context.WithoutCancel(ctx), delivered)
+}
+
+// locate finds the record last delivered in the SEL, and returns the Record ID of the next record.
+// found is false if the record is deleted, or its Record ID is used by another record.
+func (w *selWatch) locate(ctx context.Context) (nextRecordID uint16, found bool, err error) {
+	res, err := w.client.GetSELEntry(ctx, 0, w.bookmark.RecordID)
+	if err != nil {
+		if isErrOfCompletionCodes(err, uint8(CompletionCodeRequestedDataNotPresent)) {
+			return 0, false, nil
+		}
+		return 0, false, fmt.Errorf("GetSELEntry failed, err: %w", err)
+	}
+
+	if len(w.bookmark.Data) != 0 {
+		sel, err := ParseSEL(res.Data)
+		if err != nil || !bytes.Equal(sel.Pack(), w.bookmark.Data) {
+			return 0, false, nil
+		}
+	}
+	return res.NextRecordID, true, nil
+}
+
+// reset drops the bookmark which is not found in the SEL anymore.
+// The SEL is considered cleared if the erase timestamp is changed since the bookmark,
+// or wrapped if not, or if the SEL reports overflow when the erase timestamp of the bookmark is not known.
+func (w *selWatch) reset(info *GetSELInfoResponse) {
+	reason := SELResetCleared
+	if w.bookmark.EraseTime.IsZero() {
+		if info.OperationSupport.Overflow {
+			reason = SELResetWrapped
+		}
+	} else if w.bookmark.EraseTime.Equal(info.RecentEraseTime) {
+		reason = SELResetWrapped
+	}
+
+	w.client.Debugf("SEL record %#04x not found, SEL %s\n", w.bookmark.RecordID, reason)
+	w.bookmark = nil
+	if w.opts.OnReset != nil {
+		w.opts.OnReset(reason)
+	}
+}
+
+func (w *selWatch) saveBookmark(ctx context.Context, delivered int) error {
+	if w.opts.Bookmark == nil || delivered == 0 {
+		return nil
+	}
+	if err := w.opts.Bookmark.SaveSELBookmark(ctx, w.bookmark); err != nil {
+		return fmt.Errorf("SaveSELBookmark failed, err: %w", err)
+	}
+	return nil
+}
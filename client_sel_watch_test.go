@@ -0,0 +1,112 @@
+package ipmi_test
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/bougou/go-ipmi"
+	"github.com/bougou/go-ipmi/ipmitest"
+)
+
+func TestClient_WatchSEL(t *testing.T) {
+	s := newTestServer(t, func(s *ipmitest.Server) {
+		s.SELMaxEntries = 4
+	})
+	c := newTestClient(t, s, ipmi.InterfaceLanplus, testPassword)
+	if err := c.Connect(context.Background()); err != nil {
+		t.Fatalf("Connect failed, err: %s", err)
+	}
+	defer c.Close(context.Background())
+
+	addSEL := func(n int) {
+		for i := 0; i < n; i++ {
+			s.AddSEL([]byte{0, 0, 0x02, 0x6f, 0x8e, 0x91, 0x5f, 0x20, 0x00, 0x04, 0x01, 0x01, 0x01, 0x57, 0x00, 0x00})
+		}
+	}
+
+	var mu sync.Mutex
+	var resets []ipmi.SELResetReason
+	bookmarkFile := filepath.Join(t.TempDir(), "sel-bookmark.json")
+
+	// watch starts WatchSEL, and returns a func which checks the record IDs received and stops the watch
+	watch := func(opts *ipmi.WatchSELOptions) func(wantRecordIDs ...uint16) {
+		ctx, cancel := context.WithCancel(context.Background())
+		opts.Interval = 10 * time.Millisecond
+		opts.OnReset = func(reason ipmi.SELResetReason) {
+			mu.Lock()
+			defer mu.Unlock()
+			resets = append(resets, reason)
+		}
+		ch, err := c.WatchSEL(ctx, opts)
+		if err != nil {
+			cancel()
+			t.Fatalf("WatchSEL failed, err: %s", err)
+		}
+
+		return func(wantRecordIDs ...uint16) {
+			t.Helper()
+			for _, want := range wantRecordIDs {
+				select {
+				case sel := <-ch:
+					if sel.RecordID != want {
+						t.Errorf("WatchSEL returned record %#04x, want %#04x", sel.RecordID, want)
+					}
+				case <-time.After(2 * time.Second):
+					t.Fatalf("WatchSEL returned no record, want %#04x", want)
+				}
+			}
+
+			cancel()
+			for sel := range ch {
+				t.Errorf("WatchSEL returned unexpected record %#04x", sel.RecordID)
+			}
+		}
+	}
+	checkResets := func(want ...ipmi.SELResetReason) {
+		t.Helper()
+		mu.Lock()
+		defer mu.Unlock()
+		if fmt.Sprint(resets) != fmt.Sprint(want) {
+			t.Errorf("OnReset is called with %v, want %v", resets, want)
+		}
+		resets = nil
+	}
+
+	// only the records added after WatchSEL are returned
+	stop := watch(&ipmi.WatchSELOptions{Bookmark: ipmi.SELFileBookmark(bookmarkFile)})
+	addSEL(1)
+	stop(0x0003)
+
+	// continue after the bookmark
+	addSEL(1)
+	stop = watch(&ipmi.WatchSELOptions{Bookmark: ipmi.SELFileBookmark(bookmarkFile)})
+	stop(0x0004)
+	checkResets()
+
+	// the bookmarked record 4 is overwritten, the SEL holds the records 6 to 9
+	addSEL(5)
+	stop = watch(&ipmi.WatchSELOptions{Bookmark: ipmi.SELFileBookmark(bookmarkFile)})
+	stop(0x0006, 0x0007, 0x0008, 0x0009)
+	checkResets(ipmi.SELResetWrapped)
+
+	stop = watch(&ipmi.WatchSELOptions{Bookmark: ipmi.SELFileBookmark(bookmarkFile)})
+	s.ClearSEL()
+	addSEL(1)
+	stop(0x000a)
+	checkResets(ipmi.SELResetCleared)
+
+	// the bookmark is saved on the BMC
+	stop = watch(&ipmi.WatchSELOptions{Bookmark: c.SELBMCBookmark(), FromStart: true})
+	stop(0x000a)
+	res, err := c.GetLastProcessedEventId(context.Background())
+	if err != nil {
+		t.Fatalf("GetLastProcessedEventId failed, err: %s", err)
+	}
+	if res.LastSoftwareProcessedEventRecordID != 0x000a {
+		t.Errorf("last software processed record is %#04x, want 0x000a", res.LastSoftwareProcessedEventRecordID)
+	}
+}
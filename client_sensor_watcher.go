@@ -0,0 +1,346 @@
+package ipmi
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+)
+
+// SensorChangeType is the kind of the change of a sensor detected by SensorWatcher.
+type SensorChangeType string
+
+const (
+	// SensorChangeReading means the reading value of a threshold based sensor is changed.
+	SensorChangeReading SensorChangeType = "reading"
+
+	// SensorChangeStatus means the threshold status of a threshold based sensor, see Sensor.Status, is changed.
+	SensorChangeStatus SensorChangeType = "status"
+
+	// SensorChangeStates means some states of a discrete sensor are asserted or deasserted.
+	SensorChangeStates SensorChangeType = "states"
+)
+
+// SensorChange is a change of a sensor between two polls of SensorWatcher.
+type SensorChange struct {
+	Type SensorChangeType
+
+	// Time is the time of the poll the change is detected by.
+	Time time.Time
+
+	// Sensor is the sensor read by the poll, Previous is the sensor read by the previous poll.
+	Sensor   *Sensor
+	Previous *Sensor
+
+	// Delta is the change of the reading value since the reading last reported, only for SensorChangeReading.
+	// It differs from the change since Previous if the smaller changes are not reported, see WithReadingDelta.
+	Delta float64
+
+	// OldStatus and NewStatus are the Sensor.Status of Previous and Sensor, only for SensorChangeStatus.
+	OldStatus string
+	NewStatus string
+
+	// AssertedStates and DeassertedStates are the offsets of the states which become active or inactive,
+	// only for SensorChangeStates.
+	AssertedStates   []uint8
+	DeassertedStates []uint8
+}
+
+func (change *SensorChange) String() string {
+	prefix := fmt.Sprintf("%s (%#02x)", change.Sensor.Name, change.Sensor.Number)
+
+	switch change.Type {
+	case SensorChangeReading:
+		return fmt.Sprintf("%s: reading %.3f -> %.3f %s (%+.3f)",
+			prefix, change.Sensor.Value-change.Delta, change.Sensor.Value, change.Sensor.SensorUnit, change.Delta)
+
+	case SensorChangeStatus:
+		return fmt.Sprintf("%s: status %s -> %s", prefix, change.OldStatus, change.NewStatus)
+
+	case SensorChangeStates:
+		eventNames := func(offsets []uint8) []string {
+			names := make([]string, 0, len(offsets))
+			for _, offset := range offsets {
+				names = append(names, SensorEventOffsetName(offset, change.Sensor.EventReadingType, change.Sensor.SensorType))
+			}
+			return names
+		}
+		return fmt.Sprintf("%s: states asserted %v, deasserted %v",
+			prefix, eventNames(change.AssertedStates), eventNames(change.DeassertedStates))
+	}
+
+	return fmt.Sprintf("%s: %s", prefix, change.Type)
+}
+
+// sensorKey identifies a sensor among the sensors of all the controllers.
+type sensorKey struct {
+	generatorID GeneratorID
+	number      uint8
+}
+
+// SensorWatcher polls the sensors on an interval and reports the changes of the sensors against the previous poll.
+// see Client.NewSensorWatcher
+type SensorWatcher struct {
+	client        *Client
+	interval      time.Duration
+	filterOptions []SensorFilterOption
+
+	// sensorNumbers are the watched sensors, all the sensors are watched if empty
+	sensorNumbers []uint8
+	readingDelta  float64
+	onChange      []func(change *SensorChange)
+	onError       []func(err error)
+
+	mu     sync.Mutex
+	polled bool
+	keys   []sensorKey
+	last   map[sensorKey]*Sensor
+
+	// reported holds the reading values last reported, the changes of the readings are computed against them
+	reported map[sensorKey]float64
+}
+
+// NewSensorWatcher returns a SensorWatcher which polls the sensors every interval.
+// The sensors are read by GetSensors, only the sensors which pass all the filters (logical AND) are watched.
+// The filters are applied on each poll, so a sensor which is filtered out is forgotten,
+// and its changes are reported again from the next poll it passes the filters.
+//
+// Example usage:
+//
+//	watcher := client.NewSensorWatcher(10*time.Second, ipmi.SensorFilterOptionIsSensorType(ipmi.SensorTypeTemperature)).
+//	    WithReadingDelta(2).
+//	    OnChange(func(change *ipmi.SensorChange) {
+//	        fmt.Println(change)
+//	    }).
+//	    OnError(func(err error) {
+//	        fmt.Println(err)
+//	    })
+//	err := watcher.Run(ctx)
+func (c *Client) NewSensorWatcher(interval time.Duration, filterOptions ...SensorFilterOption) *SensorWatcher {
+	return &SensorWatcher{
+		client:        c,
+		interval:      interval,
+		filterOptions: filterOptions,
+		last:          make(map[sensorKey]*Sensor),
+		reported:      make(map[sensorKey]float64),
+	}
+}
+
+// WithSensorNumbers watches only the given sensors of the controller the requests are sent to,
+// the sensors are read by GetSensorByID instead of GetSensors.
+func (w *SensorWatcher) WithSensorNumbers(sensorNumbers ...uint8) *SensorWatcher {
+	w.sensorNumbers = sensorNumbers
+	return w
+}
+
+// WithReadingDelta reports the change of the reading value only if it is at least delta,
+// 0 (the default) reports any change.
+// The reading is compared against the last reported reading, so slow drifts are reported too.
+func (w *SensorWatcher) WithReadingDelta(delta float64) *SensorWatcher {
+	w.readingDelta = math.Abs(delta)
+	return w
+}
+
+// OnChange registers fn to be called for each change detected by Poll, in the order of the changes.
+func (w *SensorWatcher) OnChange(fn func(change *SensorChange)) *SensorWatcher {
+	w.onChange = append(w.onChange, fn)
+	return w
+}
+
+// OnError registers fn to be called with the errors of the polls by Run, the sensors are polled again after the interval.
+// The errors are only logged in the debug mode if no function is registered.
+func (w *SensorWatcher) OnError(fn func(err error)) *SensorWatcher {
+	w.onError = append(w.onError, fn)
+	return w
+}
+
+// Last returns the sensors kept by the watcher, in the order they are read by the last poll.
+func (w *SensorWatcher) Last() []*Sensor {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	out := make([]*Sensor, 0, len(w.keys))
+	for _, key := range w.keys {
+		out = append(out, w.last[key])
+	}
+	return out
+}
+
+// Poll reads the sensors once and returns the changes against the previous poll.
+// The first poll only records the sensors and returns no change.
+func (w *SensorWatcher) Poll(ctx context.Context) ([]*SensorChange, error) {
+	sensors, err := w.readSensors(ctx)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+
+	w.mu.Lock()
+	changes := make([]*SensorChange, 0)
+	keys := make([]sensorKey, 0, len(sensors))
+	last := make(map[sensorKey]*Sensor, len(sensors))
+	reported := make(map[sensorKey]float64, len(sensors))
+	for _, sensor := range sensors {
+		key := sensorKey{generatorID: sensor.GeneratorID, number: sensor.Number}
+		keys = append(keys, key)
+		last[key] = sensor
+		if sensor.IsThresholdAndReadingValid() {
+			reported[key] = sensor.Value
+		}
+
+		previous, ok := w.last[key]
+		if !ok || !w.polled {
+			continue
+		}
+
+		for _, change := range w.compare(previous, sensor, w.reported[key]) {
+			change.Time = now
+			changes = append(changes, change)
+		}
+
+		// keep the reading last reported, so the changes smaller than the delta are accumulated
+		if value, ok := w.reported[key]; ok && sensor.IsThresholdAndReadingValid() && math.Abs(sensor.Value-value) < w.readingDelta {
+			reported[key] = value
+		}
+	}
+	w.keys, w.last, w.reported, w.polled = keys, last, reported, true
+	w.mu.Unlock()
+
+	for _, change := range changes {
+		for _, fn := range w.onChange {
+			fn(change)
+		}
+	}
+	return changes, nil
+}
+
+// Run polls the sensors immediately and then every interval until ctx is done,
+// the changes are passed to the functions registered by OnChange,
+// and the errors of Poll to the functions registered by OnError.
+// It returns the error of ctx once ctx is done.
+func (w *SensorWatcher) Run(ctx context.Context) error {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := w.Poll(ctx); err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			w.handleError(err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+func (w *SensorWatcher) handleError(err error) {
+	if len(w.onError) == 0 {
+		w.client.Debugf("SensorWatcher poll failed, err: %s\n", err)
+		return
+	}
+	for _, fn := range w.onError {
+		fn(err)
+	}
+}
+
+func (w *SensorWatcher) readSensors(ctx context.Context) ([]*Sensor, error) {
+	if len(w.sensorNumbers) == 0 {
+		sensors, err := w.client.GetSensors(ctx, w.filterOptions...)
+		if err != nil {
+			return nil, fmt.Errorf("GetSensors failed, err: %w", err)
+		}
+		return sensors, nil
+	}
+
+	out := make([]*Sensor, 0, len(w.sensorNumbers))
+	for _, sensorNumber := range w.sensorNumbers {
+		sensor, err := w.client.GetSensorByID(ctx, sensorNumber)
+		if err != nil {
+			return nil, fmt.Errorf("GetSensorByID for sensor %#02x failed, err: %w", sensorNumber, err)
+		}
+
+		choose := true
+		for _, filterOption := range w.filterOptions {
+			if !filterOption(sensor) {
+				choose = false
+				break
+			}
+		}
+		if choose {
+			out = append(out, sensor)
+		}
+	}
+	return out, nil
+}
+
+// compare returns the changes of the sensor from previous to sensor,
+// the reading is compared against the reading value last reported.
+func (w *SensorWatcher) compare(previous *Sensor, sensor *Sensor, reported float64) []*SensorChange {
+	changes := make([]*SensorChange, 0)
+
+	if sensor.IsThreshold() {
+		if previous.IsThresholdAndReadingValid() && sensor.IsThresholdAndReadingValid() {
+			delta := sensor.Value - reported
+			if delta != 0 && math.Abs(delta) >= w.readingDelta {
+				changes = append(changes, &SensorChange{
+					Type:     SensorChangeReading,
+					Sensor:   sensor,
+					Previous: previous,
+					Delta:    delta,
+				})
+			}
+		}
+
+		if oldStatus, newStatus := previous.Status(), sensor.Status(); oldStatus != newStatus {
+			changes = append(changes, &SensorChange{
+				Type:      SensorChangeStatus,
+				Sensor:    sensor,
+				Previous:  previous,
+				OldStatus: oldStatus,
+				NewStatus: newStatus,
+			})
+		}
+		return changes
+	}
+
+	if !previous.IsReadingValid() || !sensor.IsReadingValid() {
+		return changes
+	}
+
+	asserted := subtractEventOffsets(sensor.DiscreteActiveEvents(), previous.DiscreteActiveEvents())
+	deasserted := subtractEventOffsets(previous.DiscreteActiveEvents(), sensor.DiscreteActiveEvents())
+	if len(asserted) != 0 || len(deasserted) != 0 {
+		changes = append(changes, &SensorChange{
+			Type:             SensorChangeStates,
+			Sensor:           sensor,
+			Previous:         previous,
+			AssertedStates:   asserted,
+			DeassertedStates: deasserted,
+		})
+	}
+	return changes
+}
+
+// subtractEventOffsets returns the event offsets of a which are not in b.
+func subtractEventOffsets(a []uint8, b []uint8) []uint8 {
+	out := make([]uint8, 0)
+	for _, offset := range a {
+		found := false
+		for _, other := range b {
+			if offset == other {
+				found = true
+				break
+			}
+		}
+		if !found {
+			out = append(out, offset)
+		}
+	}
+	return out
+}
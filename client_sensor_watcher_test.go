@@ -0,0 +1,164 @@
+package ipmi_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bougou/go-ipmi"
+	"github.com/bougou/go-ipmi/ipmitest"
+)
+
+func TestClient_SensorWatcher(t *testing.T) {
+	ctx := context.Background()
+
+	fan := &ipmi.SDRFull{
+		GeneratorID:            ipmi.GeneratorBMC,
+		SensorNumber:           0x30,
+		SensorType:             ipmi.SensorTypeFan,
+		SensorEventReadingType: ipmi.EventReadingTypeThreshold,
+		SensorUnit:             ipmi.SensorUnit{BaseUnit: ipmi.SensorUnitType_RPM},
+		ReadingFactors:         ipmi.ReadingFactors{M: 50},
+		IDStringBytes:          []byte("FAN1"),
+	}
+	processor := &ipmi.SDRCompact{
+		GeneratorID:            ipmi.GeneratorBMC,
+		SensorNumber:           0x40,
+		SensorType:             ipmi.SensorTypeProcessor,
+		SensorEventReadingType: ipmi.EventReadingTypeSensorSpecific,
+		IDStringBytes:          []byte("CPU0 Status"),
+	}
+
+	s := newTestServer(t, func(s *ipmitest.Server) {
+		s.AddSDR(fan.Pack())
+		s.AddSDR(processor.Pack())
+		s.SetSensor(0x30, ipmitest.Sensor{Reading: 60})
+		s.SetSensor(0x40, ipmitest.Sensor{States: 0x80})
+	})
+	c := newTestClient(t, s, ipmi.InterfaceLanplus, testPassword)
+	if err := c.Connect(ctx); err != nil {
+		t.Fatalf("Connect failed, err: %s", err)
+	}
+	defer c.Close(ctx)
+
+	var notified []string
+	watcher := c.NewSensorWatcher(time.Second, ipmi.SensorFilterOptionIsSensorType(ipmi.SensorTypeFan, ipmi.SensorTypeProcessor)).
+		WithReadingDelta(100).
+		OnChange(func(change *ipmi.SensorChange) {
+			notified = append(notified, change.String())
+		})
+
+	poll := func(want ...string) {
+		t.Helper()
+		changes, err := watcher.Poll(ctx)
+		if err != nil {
+			t.Fatalf("Poll failed, err: %s", err)
+		}
+		got := make([]string, 0)
+		for _, change := range changes {
+			got = append(got, change.String())
+		}
+		if strings.Join(got, "\n") != strings.Join(want, "\n") {
+			t.Errorf("Poll returned changes %q, want %q", got, want)
+		}
+	}
+
+	// the first poll is the baseline
+	poll()
+	if got := len(watcher.Last()); got != 2 {
+		t.Errorf("Last() returned %d sensors, want 2", got)
+	}
+
+	// the changes smaller than the delta are accumulated
+	s.SetSensor(0x30, ipmitest.Sensor{Reading: 61})
+	poll()
+	s.SetSensor(0x30, ipmitest.Sensor{Reading: 62, States: 0x08})
+	s.SetSensor(0x40, ipmitest.Sensor{States: 0x01})
+	poll(
+		"FAN1 (0x30): reading 3000.000 -> 3100.000 RPM (+100.000)",
+		"FAN1 (0x30): status ok -> unc",
+		"CPU0 Status (0x40): states asserted [state0 (IERR)], deasserted [state7 (Processor Presence detected)]",
+	)
+	if len(notified) != 3 {
+		t.Errorf("OnChange is called %d times, want 3", len(notified))
+	}
+
+	s.SetSensor(0x30, ipmitest.Sensor{Reading: 62, ReadingUnavailable: true})
+	poll("FAN1 (0x30): status unc -> N/A")
+}
+
+func TestClient_SensorWatcherRunError(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	s := newTestServer(t, func(s *ipmitest.Server) {
+		s.Handle(ipmi.CommandGetSensorReading, func(req *ipmitest.Request) (ipmi.CompletionCode, []byte) {
+			return ipmi.CompletionCodeNodeBusy, nil
+		})
+	})
+	c := newTestClient(t, s, ipmi.InterfaceLanplus, testPassword)
+	if err := c.Connect(ctx); err != nil {
+		t.Fatalf("Connect failed, err: %s", err)
+	}
+	defer c.Close(ctx)
+
+	errs := make(chan error, 10)
+	changes := make(chan *ipmi.SensorChange, 10)
+	watcher := c.NewSensorWatcher(20 * time.Millisecond).
+		WithSensorNumbers(0x01).
+		OnChange(func(change *ipmi.SensorChange) {
+			changes <- change
+		}).
+		OnError(func(err error) {
+			select {
+			case errs <- err:
+			default:
+			}
+		})
+
+	done := make(chan error, 1)
+	go func() {
+		done <- watcher.Run(ctx)
+	}()
+
+	// the watcher keeps polling after the errors
+	for i := 0; i < 2; i++ {
+		select {
+		case <-errs:
+		case err := <-done:
+			t.Fatalf("Run returned on the poll error, err: %v", err)
+		case <-time.After(2 * time.Second):
+			t.Fatal("OnError not called")
+		}
+	}
+
+	s.Handle(ipmi.CommandGetSensorReading, nil)
+	for deadline := time.Now().Add(2 * time.Second); len(watcher.Last()) == 0; {
+		if time.Now().After(deadline) {
+			t.Fatal("the sensors are not polled after the errors")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	s.SetSensor(0x01, ipmitest.Sensor{Reading: 50})
+	select {
+	case change := <-changes:
+		if change.Type != ipmi.SensorChangeReading {
+			t.Errorf("OnChange called with change %s, want the reading change", change)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("OnChange not called after the errors")
+	}
+
+	cancel()
+	select {
+	case err := <-done:
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("Run returned err %v, want context.Canceled", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Run not returned after ctx is done")
+	}
+}
@@ -0,0 +1,385 @@
+package ipmi
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+const (
+	DefaultSOLRetryCount    int           = 7
+	DefaultSOLRetryInterval time.Duration = 500 * time.Millisecond
+)
+
+var (
+	ErrSOLClosed       = errors.New("sol session closed")
+	ErrSOLDeactivated  = errors.New("sol payload deactivated by BMC")
+	errSOLNoAckTimeout = errors.New("wait sol ack timeout")
+)
+
+// SOLSession exchanges the characters of the baseboard serial controller
+// over an activated SOL (Serial Over LAN) payload instance.
+//
+// SOLSession implements io.ReadWriteCloser. Read returns the characters sent by the
+// serial controller, Write sends characters to the serial controller and returns after
+// they are accepted (ACK'd) by the BMC.
+//
+// see 15 IPMI Serial Over LAN (SOL), 24 Payload Commands
+type SOLSession struct {
+	// RetryCount is the count of retries for a SOL packet not ACK'd by the BMC.
+	RetryCount int
+	// RetryInterval is the time to wait for the ACK of a sent SOL packet.
+	RetryInterval time.Duration
+
+	c        *Client
+	udp      *UDPClient
+	ownUDP   bool
+	instance uint8
+
+	// stopReceiving removes handlePacket from the handlers of udp
+	stopReceiving func()
+
+	// the max characters which can be sent in one SOL packet
+	maxChars int
+
+	// sendMu serializes the packets sent with a sequence number,
+	// only one packet is allowed to be outstanding (not ACK'd).
+	sendMu  sync.Mutex
+	seq     uint8
+	control uint8 // the CTS and DCD/DSR bits kept in the Operation field
+	ackCh   chan *SOLPacket
+
+	mu          sync.Mutex
+	cond        *sync.Cond
+	recvBuf     bytes.Buffer
+	lastRecvSeq uint8
+	err         error
+	done        chan struct{}
+	closed      bool
+}
+
+// OpenSOL activates the SOL payload instance (normally 1) on the current RMCP+ session.
+// The returned SOLSession must be closed to deactivate the payload.
+//
+// SOL is only available for the lanplus interface.
+func (c *Client) OpenSOL(ctx context.Context, instance uint8) (*SOLSession, error) {
+	if c.Interface != InterfaceLanplus || c.session.v20.state != SessionStateActive {
+		return nil, fmt.Errorf("sol requires an active lanplus session")
+	}
+
+	request := &ActivatePayloadRequest{
+		PayloadType:          PayloadTypeSOL,
+		PayloadInstance:      instance,
+		EnableEncryption:     c.session.v20.cryptAlg != CryptAlg_None,
+		EnableAuthentication: c.session.v20.integrityAlg != IntegrityAlg_None,
+	}
+	res, err := c.ActivatePayload(ctx, request)
+	if err != nil {
+		return nil, fmt.Errorf("ActivatePayload failed, err: %w", err)
+	}
+	c.Debug("SOL Activate Payload Response", res)
+
+	s := &SOLSession{
+		RetryCount:    DefaultSOLRetryCount,
+		RetryInterval: DefaultSOLRetryInterval,
+
+		c:        c,
+		udp:      c.udpClient,
+		instance: instance,
+		maxChars: int(res.InboundPayloadSize) - SOLPacketHeaderSize,
+		ackCh:    make(chan *SOLPacket, 16),
+		done:     make(chan struct{}),
+	}
+	s.cond = sync.NewCond(&s.mu)
+	if s.maxChars <= 0 || s.maxChars > 0xff {
+		// the accepted character count is a one byte field
+		s.maxChars = 0xff
+	}
+
+	// The BMC may send the SOL payload over a different port.
+	if port := int(res.PayloadUDPPort); port != 0 && port != c.Port {
+		s.udp = &UDPClient{
+			Host:       c.Host,
+			Port:       port,
+			proxy:      c.udpClient.proxy,
+			timeout:    c.timeout,
+			bufferSize: c.bufferSize,
+		}
+		s.ownUDP = true
+	}
+
+	// The UDPClient of the session may already be receiving the replies of the requests in flight,
+	// the SOL packets are consumed by handlePacket besides them.
+	stop, err := s.udp.StartReceiving(s.handlePacket)
+	if err != nil {
+		s.deactivate(ctx)
+		return nil, fmt.Errorf("start receiving sol packets failed, err: %w", err)
+	}
+	s.stopReceiving = stop
+
+	return s, nil
+}
+
+// Read reads the characters sent by the baseboard serial controller.
+// It returns io.EOF after the SOL session is closed or deactivated by the BMC.
+func (s *SOLSession) Read(p []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for s.recvBuf.Len() == 0 {
+		if s.err != nil {
+			return 0, io.EOF
+		}
+		s.cond.Wait()
+	}
+	return s.recvBuf.Read(p)
+}
+
+// Write sends the characters to the baseboard serial controller.
+// The characters not accepted by the BMC are resent in new packets.
+func (s *SOLSession) Write(p []byte) (int, error) {
+	s.sendMu.Lock()
+	defer s.sendMu.Unlock()
+
+	var written int
+	for written < len(p) {
+		chunk := p[written:]
+		if len(chunk) > s.maxChars {
+			chunk = chunk[:s.maxChars]
+		}
+
+		n, err := s.send(0, chunk)
+		written += n
+		if err != nil {
+			return written, err
+		}
+	}
+	return written, nil
+}
+
+// SendBreak asks the BMC to generate a BREAK condition on the baseboard serial controller.
+func (s *SOLSession) SendBreak() error {
+	s.sendMu.Lock()
+	defer s.sendMu.Unlock()
+
+	_, err := s.send(SOLOperationGenerateBreak, nil)
+	return err
+}
+
+// SetCTS asserts or de-asserts (pauses) the CTS signal to the baseboard serial controller.
+func (s *SOLSession) SetCTS(asserted bool) error {
+	return s.setControl(SOLOperationDeassertCTS, !asserted)
+}
+
+// SetDCDDSR asserts or de-asserts the DCD/DSR signals to the baseboard serial controller.
+func (s *SOLSession) SetDCDDSR(asserted bool) error {
+	return s.setControl(SOLOperationDeassertDCDDSR, !asserted)
+}
+
+func (s *SOLSession) setControl(bit uint8, set bool) error {
+	s.sendMu.Lock()
+	defer s.sendMu.Unlock()
+
+	if set {
+		s.control |= bit
+	} else {
+		s.control &^= bit
+	}
+
+	_, err := s.send(0, nil)
+	return err
+}
+
+// Close deactivates the SOL payload instance.
+func (s *SOLSession) Close() error {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return nil
+	}
+	s.closed = true
+	s.mu.Unlock()
+	s.closeWith(ErrSOLClosed)
+
+	err := s.deactivate(context.Background())
+
+	s.stopReceiving()
+	if s.ownUDP {
+		if e := s.udp.Close(); e != nil && err == nil {
+			err = e
+		}
+	}
+	return err
+}
+
+func (s *SOLSession) deactivate(ctx context.Context) error {
+	request := &DeactivatePayloadRequest{
+		PayloadType:     PayloadTypeSOL,
+		PayloadInstance: s.instance,
+	}
+	if _, err := s.c.DeactivatePayload(ctx, request); err != nil {
+		if respErr, ok := isResponseError(err); ok && respErr.CompletionCode() == 0x80 {
+			// already deactivated, e.g. by the BMC
+			return nil
+		}
+		return fmt.Errorf("DeactivatePayload failed, err: %w", err)
+	}
+	return nil
+}
+
+func (s *SOLSession) closeWith(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.err != nil {
+		return
+	}
+	s.err = err
+	close(s.done)
+	s.cond.Broadcast()
+}
+
+// send sends a packet with a new sequence number and waits for its ACK,
+// it returns the count of the characters accepted by the BMC.
+// The caller must hold sendMu.
+func (s *SOLSession) send(operation uint8, data []byte) (int, error) {
+	s.seq = nextSOLSequence(s.seq)
+	packet := &SOLPacket{
+		Sequence:        s.seq,
+		OperationStatus: s.control | operation,
+		Data:            data,
+	}
+
+	for attempt := 0; attempt <= s.RetryCount; attempt++ {
+		if err := s.sendPacket(packet); err != nil {
+			return 0, err
+		}
+
+		ack, err := s.waitAck(packet.Sequence)
+		if err != nil {
+			if errors.Is(err, errSOLNoAckTimeout) {
+				continue
+			}
+			return 0, err
+		}
+
+		accepted := int(ack.AcceptedCharacterCount)
+		if accepted > len(data) {
+			accepted = len(data)
+		}
+		if ack.IsNACK() || (len(data) > 0 && accepted == 0) {
+			// the BMC can not accept the characters for now, retry later
+			s.c.Debugf("sol packet (%d) refused, status: %#02x\n", packet.Sequence, ack.OperationStatus)
+			time.Sleep(s.RetryInterval)
+			continue
+		}
+		return accepted, nil
+	}
+
+	return 0, fmt.Errorf("sol packet (%d) not accepted after %d retries", packet.Sequence, s.RetryCount)
+}
+
+func (s *SOLSession) waitAck(seq uint8) (*SOLPacket, error) {
+	timer := time.NewTimer(s.RetryInterval)
+	defer timer.Stop()
+
+	for {
+		select {
+		case ack := <-s.ackCh:
+			if ack.AckSequence == seq {
+				return ack, nil
+			}
+			// stale ACK of a retried packet
+		case <-timer.C:
+			return nil, errSOLNoAckTimeout
+		case <-s.done:
+			return nil, s.err
+		}
+	}
+}
+
+func (s *SOLSession) sendPacket(packet *SOLPacket) error {
+	s.c.Debug(">>>> SOL Packet", packet)
+
+	session20, err := s.c.genSession20(PayloadTypeSOL, packet.Pack())
+	if err != nil {
+		return fmt.Errorf("genSession20 failed, err: %w", err)
+	}
+	rmcp := &Rmcp{
+		RmcpHeader: NewRmcpHeader(),
+		Session20:  session20,
+	}
+	return s.udp.Send(rmcp.Pack())
+}
+
+// handlePacket consumes the SOL packets received from the BMC,
+// it is called on the receiving goroutine of the UDPClient.
+func (s *SOLSession) handlePacket(msg []byte) bool {
+	rmcp := &Rmcp{}
+	if err := rmcp.Unpack(msg); err != nil {
+		return false
+	}
+	if rmcp.Session20 == nil || rmcp.Session20.SessionHeader20.PayloadType != PayloadTypeSOL {
+		return false
+	}
+
+	payload := rmcp.Session20.SessionPayload
+	if rmcp.Session20.SessionHeader20.PayloadEncrypted {
+		s.c.lock()
+		d, err := s.c.decryptPayload(payload)
+		s.c.unlock()
+		if err != nil {
+			s.c.DebugfRed("decrypt sol payload failed, err: %s\n", err)
+			return true
+		}
+		payload = d
+	}
+
+	packet := &SOLPacket{}
+	if err := packet.Unpack(payload); err != nil {
+		s.c.DebugfRed("unpack sol packet failed, err: %s\n", err)
+		return true
+	}
+	s.c.Debug("<<<< SOL Packet", packet)
+
+	if packet.AckSequence != 0 {
+		select {
+		case s.ackCh <- packet:
+		default:
+		}
+	}
+
+	if packet.Sequence != 0 {
+		s.receive(packet)
+	}
+
+	if packet.OperationStatus&SOLStatusDeactivating != 0 {
+		s.closeWith(ErrSOLDeactivated)
+	}
+
+	return true
+}
+
+// receive buffers the characters of the packet and ACKs it.
+func (s *SOLSession) receive(packet *SOLPacket) {
+	s.mu.Lock()
+	// the same sequence number means the BMC retried the packet as our ACK was lost
+	if packet.Sequence != s.lastRecvSeq {
+		s.lastRecvSeq = packet.Sequence
+		s.recvBuf.Write(packet.Data)
+		s.cond.Broadcast()
+	}
+	s.mu.Unlock()
+
+	ack := &SOLPacket{
+		AckSequence:            packet.Sequence,
+		AcceptedCharacterCount: uint8(len(packet.Data)),
+	}
+	if err := s.sendPacket(ack); err != nil {
+		s.c.DebugfRed("send sol ack failed, err: %s\n", err)
+	}
+}
@@ -0,0 +1,84 @@
+package ipmi_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"testing"
+
+	"github.com/bougou/go-ipmi"
+	"github.com/bougou/go-ipmi/ipmitest"
+)
+
+func TestClient_OpenSOL(t *testing.T) {
+	tests := []struct {
+		name        string
+		acceptLimit int
+		input       []byte
+	}{
+		{name: "short", input: []byte("hello\r\n")},
+		{name: "multiple packets", input: bytes.Repeat([]byte("0123456789"), 50)},
+		{name: "partial accept", acceptLimit: 3, input: []byte("partially accepted")},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestServer(t, func(s *ipmitest.Server) {
+				s.SOLAcceptLimit = tt.acceptLimit
+				s.SOLHandler = func(data []byte) []byte {
+					return bytes.ToUpper(data)
+				}
+			})
+
+			ctx := context.Background()
+			c := newTestClient(t, s, ipmi.InterfaceLanplus, testPassword)
+			if err := c.Connect(ctx); err != nil {
+				t.Fatalf("Connect failed, err: %s", err)
+			}
+			defer c.Close(ctx)
+
+			sol, err := c.OpenSOL(ctx, 1)
+			if err != nil {
+				t.Fatalf("OpenSOL failed, err: %s", err)
+			}
+
+			if _, err := c.OpenSOL(ctx, 1); err == nil {
+				t.Errorf("OpenSOL succeeded on an already activated payload instance")
+			}
+
+			n, err := sol.Write(tt.input)
+			if err != nil {
+				t.Fatalf("Write failed, err: %s", err)
+			}
+			if n != len(tt.input) {
+				t.Errorf("Write wrote %d bytes, want %d", n, len(tt.input))
+			}
+
+			want := bytes.ToUpper(tt.input)
+			got := make([]byte, len(want))
+			if _, err := io.ReadFull(sol, got); err != nil {
+				t.Fatalf("Read failed, err: %s", err)
+			}
+			if !bytes.Equal(got, want) {
+				t.Errorf("Read got %q, want %q", got, want)
+			}
+
+			if err := sol.SendBreak(); err != nil {
+				t.Errorf("SendBreak failed, err: %s", err)
+			}
+
+			if err := sol.Close(); err != nil {
+				t.Fatalf("Close failed, err: %s", err)
+			}
+			if _, err := sol.Read(got); err != io.EOF {
+				t.Errorf("Read after Close returned err %v, want io.EOF", err)
+			}
+
+			// the client is still usable after the SOL session is closed
+			if _, err := c.GetDeviceID(ctx); err != nil {
+				t.Errorf("GetDeviceID failed after closing SOL, err: %s", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,91 @@
+package ipmi_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/bougou/go-ipmi"
+	"github.com/bougou/go-ipmi/ipmitest"
+)
+
+const (
+	testUsername = "admin"
+	testPassword = "secret"
+)
+
+// newTestServer starts an ipmitest.Server with fixtures for one sensor, two SEL entries and the builtin FRU,
+// the configure funcs are applied before the Server is started.
+func newTestServer(t *testing.T, configure ...func(s *ipmitest.Server)) *ipmitest.Server {
+	t.Helper()
+
+	s := ipmitest.NewServer(testUsername, testPassword)
+	s.CipherSuites = []ipmi.CipherSuiteID{
+		ipmi.CipherSuiteID2, ipmi.CipherSuiteID3, ipmi.CipherSuiteID4, ipmi.CipherSuiteID5,
+		ipmi.CipherSuiteID7, ipmi.CipherSuiteID8, ipmi.CipherSuiteID9, ipmi.CipherSuiteID10,
+		ipmi.CipherSuiteID16, ipmi.CipherSuiteID17, ipmi.CipherSuiteID18, ipmi.CipherSuiteID19,
+	}
+	s.AuthTypes = []ipmi.AuthType{
+		ipmi.AuthTypeNone, ipmi.AuthTypeMD2, ipmi.AuthTypeMD5, ipmi.AuthTypePassword,
+	}
+
+	s.AddSDR(fullSensorSDR(0x01, "CPU Temp"))
+	s.SetSensor(0x01, ipmitest.Sensor{
+		Reading:            40,
+		ReadableThresholds: 0x3f,
+		UNC:                80, UCR: 90, UNR: 100,
+		LNC: 10, LCR: 5, LNR: 0,
+		PositiveHysteresis: 2,
+		NegativeHysteresis: 2,
+	})
+	s.AddSEL([]byte{0, 0, 0x02, 0x6d, 0x8e, 0x91, 0x5f, 0x20, 0x00, 0x04, 0x01, 0x01, 0x01, 0x57, 0x00, 0x00})
+	s.AddSEL([]byte{0, 0, 0x02, 0x6e, 0x8e, 0x91, 0x5f, 0x20, 0x00, 0x04, 0x01, 0x01, 0x01, 0x59, 0x00, 0x00})
+
+	// FRU Common Header only, no areas
+	s.SetFRU(0, []byte{0x01, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0xff})
+
+	for _, f := range configure {
+		f(s)
+	}
+
+	if err := s.Start(); err != nil {
+		t.Fatalf("Start failed, err: %s", err)
+	}
+	t.Cleanup(func() { s.Close() })
+	return s
+}
+
+func newTestClient(t *testing.T, s *ipmitest.Server, intf ipmi.Interface, password string) *ipmi.Client {
+	t.Helper()
+
+	c, err := ipmi.NewClient(s.Host(), s.Port(), testUsername, password)
+	if err != nil {
+		t.Fatalf("NewClient failed, err: %s", err)
+	}
+	return c.WithInterface(intf).WithTimeout(time.Second)
+}
+
+// fullSensorSDR returns a Full Sensor Record of a linear temperature sensor (M = 1),
+// with readable thresholds and hysteresis.
+func fullSensorSDR(sensorNumber uint8, name string) []byte {
+	r := make([]byte, 48, 48+len(name))
+	r[2] = 0x51         // SDR version
+	r[3] = 0x01         // Full Sensor Record
+	r[5] = 0x20         // generator ID, BMC
+	r[7] = sensorNumber // sensor number
+	r[8] = 0x03         // entity ID, processor
+	r[9] = 0x01         // entity instance
+	r[10] = 0x7f        // sensor initialization
+	r[11] = 0x54        // hysteresis and thresholds readable
+	r[12] = uint8(ipmi.SensorTypeTemperature)
+	r[13] = uint8(ipmi.EventReadingTypeThreshold)
+	r[21] = uint8(ipmi.SensorUnitType_DegreesC)
+	r[24] = 0x01 // M
+	r[47] = 0xc0 | uint8(len(name))
+	return append(r, name...)
+}
+
+func hasCompletionCode(err error, cc ipmi.CompletionCode) bool {
+	var respErr *ipmi.ResponseError
+	return errors.As(err, &respErr) && respErr.CompletionCode() == cc
+}
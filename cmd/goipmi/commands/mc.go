@@ -3,6 +3,7 @@ package commands
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/spf13/cobra"
 )
@@ -25,6 +26,7 @@ func NewCmdMC() *cobra.Command {
 	cmd.AddCommand(NewCmdMC_ACPI())
 	cmd.AddCommand(NewCmdMC_GUID())
 	cmd.AddCommand(NewCmdMC_Watchdog())
+	cmd.AddCommand(NewCmdMC_Time())
 
 	return cmd
 }
@@ -153,3 +155,33 @@ func NewCmdMC_Watchdog() *cobra.Command {
 	}
 	return cmd
 }
+
+func NewCmdMC_Time() *cobra.Command {
+	usage := `time [sync]
+  (none) :  Show the SEL clock, the SDR Repository clock and the SEL Time UTC Offset, with the drift from the host clock
+  sync   :  Set the SEL clock and the SDR Repository clock to the host clock
+`
+
+	cmd := &cobra.Command{
+		Use:   "time",
+		Short: "time",
+		Run: func(cmd *cobra.Command, args []string) {
+			ctx := context.Background()
+			if len(args) > 0 {
+				if args[0] != "sync" {
+					CheckErr(fmt.Errorf("usage: %s", usage))
+				}
+				if err := client.SyncBMCClocks(ctx, time.Now()); err != nil {
+					CheckErr(fmt.Errorf("SyncBMCClocks failed, err: %w", err))
+				}
+			}
+
+			clocks, err := client.GetBMCClocks(ctx)
+			if err != nil {
+				CheckErr(fmt.Errorf("GetBMCClocks failed, err: %w", err))
+			}
+			fmt.Println(clocks.Format())
+		},
+	}
+	return cmd
+}
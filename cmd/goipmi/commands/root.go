@@ -4,6 +4,7 @@ import (
 	"context"
 	"flag"
 	"fmt"
+	"os"
 	"strings"
 
 	"github.com/bougou/go-ipmi"
@@ -23,6 +24,13 @@ var (
 	privilegeLevel string
 	showVersion    bool
 
+	targetChannel  uint8
+	targetAddr     uint8
+	transitChannel uint8
+	transitAddr    uint8
+
+	sdrCacheFile string
+
 	client *ipmi.Client
 )
 
@@ -67,6 +75,20 @@ func initClient() error {
 	}
 
 	client.WithDebug(debug)
+	client.WithTarget(targetChannel, targetAddr)
+	client.WithTransit(transitChannel, transitAddr)
+
+	if sdrCacheFile != "" {
+		f, err := os.Open(sdrCacheFile)
+		if err != nil {
+			return fmt.Errorf("open sdr cache file failed, err: %w", err)
+		}
+		defer f.Close()
+
+		if err := client.LoadSDRCache(f); err != nil {
+			return fmt.Errorf("load sdr cache file failed, err: %w", err)
+		}
+	}
 
 	var privLevel ipmi.PrivilegeLevel = ipmi.PrivilegeLevelUnspecified
 	switch strings.ToUpper(privilegeLevel) {
@@ -126,6 +148,13 @@ func NewRootCommand() *cobra.Command {
 	rootCmd.PersistentFlags().BoolVarP(&showVersion, "version", "V", false, "version")
 	rootCmd.PersistentFlags().StringVarP(&privilegeLevel, "priv-level", "L", "ADMINISTRATOR", "Force session privilege level. Can be CALLBACK, USER, OPERATOR, ADMINISTRATOR.")
 
+	rootCmd.PersistentFlags().Uint8VarP(&targetChannel, "target-channel", "b", 0, "Set destination channel for bridged request")
+	rootCmd.PersistentFlags().Uint8VarP(&targetAddr, "target-addr", "t", 0, "Bridge request to remote target address")
+	rootCmd.PersistentFlags().Uint8VarP(&transitChannel, "transit-channel", "B", 0, "Set transit channel for bridged request (dual bridge)")
+	rootCmd.PersistentFlags().Uint8VarP(&transitAddr, "transit-addr", "T", 0, "Set transit address for bridge request (dual bridge)")
+
+	rootCmd.PersistentFlags().StringVarP(&sdrCacheFile, "sdr-cache-file", "S", "", "Use local file for remote SDR cache, see 'sdr dump'")
+
 	rootCmd.Flags().AddGoFlagSet(flag.CommandLine)
 
 	rootCmd.AddCommand(NewCmdMC())
@@ -3,7 +3,9 @@ package commands
 import (
 	"context"
 	"fmt"
+	"os"
 	"strconv"
+	"strings"
 
 	"github.com/bougou/go-ipmi"
 	"github.com/spf13/cobra"
@@ -27,6 +29,9 @@ func NewCmdSDR() *cobra.Command {
 	cmd.AddCommand(NewCmdSDRGet())
 	cmd.AddCommand(NewCmdSDRList())
 	cmd.AddCommand(NewCmdSDRType())
+	cmd.AddCommand(NewCmdSDRDump())
+	cmd.AddCommand(NewCmdSDRFill())
+	cmd.AddCommand(NewCmdSDREntity())
 
 	return cmd
 }
@@ -221,3 +226,116 @@ func NewCmdSDRList() *cobra.Command {
 
 	return cmd
 }
+
+func NewCmdSDRDump() *cobra.Command {
+	usage := `sdr dump <file>`
+
+	cmd := &cobra.Command{
+		Use:   "dump",
+		Short: "dump raw SDR data to a file, which can be used by --sdr-cache-file",
+		Run: func(cmd *cobra.Command, args []string) {
+			if len(args) < 1 {
+				CheckErr(fmt.Errorf("no file supplied, usage: %s", usage))
+			}
+
+			f, err := os.Create(args[0])
+			if err != nil {
+				CheckErr(fmt.Errorf("create file failed, err: %w", err))
+			}
+			defer f.Close()
+
+			ctx := context.Background()
+			if err := client.DumpSDRs(ctx, f); err != nil {
+				CheckErr(fmt.Errorf("DumpSDRs failed, err: %w", err))
+			}
+			if err := f.Close(); err != nil {
+				CheckErr(fmt.Errorf("close file failed, err: %w", err))
+			}
+
+			fmt.Printf("Dumped SDR data to %s\n", args[0])
+		},
+	}
+
+	return cmd
+}
+
+func NewCmdSDRFill() *cobra.Command {
+	usage := `sdr fill <file>`
+
+	cmd := &cobra.Command{
+		Use:   "fill",
+		Short: "clear the SDR repository and add the SDR records from a file written by 'sdr dump'",
+		Run: func(cmd *cobra.Command, args []string) {
+			if len(args) < 1 {
+				CheckErr(fmt.Errorf("no file supplied, usage: %s", usage))
+			}
+
+			f, err := os.Open(args[0])
+			if err != nil {
+				CheckErr(fmt.Errorf("open file failed, err: %w", err))
+			}
+			defer f.Close()
+
+			ctx := context.Background()
+			if err := client.FillSDRRepo(ctx, f); err != nil {
+				CheckErr(fmt.Errorf("FillSDRRepo failed, err: %w", err))
+			}
+
+			fmt.Printf("Filled SDR repository from %s\n", args[0])
+		},
+	}
+
+	return cmd
+}
+
+func NewCmdSDREntity() *cobra.Command {
+	usage := `sdr entity [<entityID>[.<entityInstance>]]`
+
+	cmd := &cobra.Command{
+		Use:   "entity",
+		Short: "print the entity tree, or the sensors of an entity",
+		Run: func(cmd *cobra.Command, args []string) {
+			ctx := context.Background()
+			tree, err := client.GetEntityTree(ctx)
+			if err != nil {
+				CheckErr(fmt.Errorf("GetEntityTree failed, err: %w", err))
+			}
+
+			if len(args) == 0 {
+				fmt.Print(tree.Format())
+				return
+			}
+
+			idStr, instanceStr, hasInstance := strings.Cut(args[0], ".")
+			id, err := strconv.ParseUint(idStr, 0, 8)
+			if err != nil {
+				CheckErr(fmt.Errorf("invalid entity ID %q, usage: %s", idStr, usage))
+			}
+			var instance *ipmi.EntityInstance
+			if hasInstance {
+				i, err := strconv.ParseUint(instanceStr, 0, 8)
+				if err != nil {
+					CheckErr(fmt.Errorf("invalid entity instance %q, usage: %s", instanceStr, usage))
+				}
+				entityInstance := ipmi.EntityInstance(i)
+				instance = &entityInstance
+			}
+
+			nodes := tree.Find(ipmi.EntityID(id), instance)
+			if len(nodes) == 0 {
+				CheckErr(fmt.Errorf("entity %s not found", args[0]))
+			}
+			for _, node := range nodes {
+				fmt.Print(node.Format())
+
+				fmt.Printf("Sensors of %s (%s):\n", node.EntityID, node.EntityKey)
+				for _, sdr := range node.AllSensors() {
+					fmt.Printf("  %-16s (%#02x)\n", sdr.SensorName(), uint8(sdr.SensorNumber()))
+				}
+				fmt.Println()
+			}
+		},
+	}
+
+	return cmd
+}
@@ -4,6 +4,11 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
 
 	"github.com/bougou/go-ipmi"
 	"github.com/spf13/cobra"
@@ -26,6 +31,13 @@ func NewCmdSEL() *cobra.Command {
 	cmd.AddCommand(NewCmdSELGet())
 	cmd.AddCommand(NewCmdSELList())
 	cmd.AddCommand(NewCmdSELElist())
+	cmd.AddCommand(NewCmdSELFollow())
+	cmd.AddCommand(NewCmdSELSave())
+	cmd.AddCommand(NewCmdSELWriteRaw())
+	cmd.AddCommand(NewCmdSELReadRaw())
+	cmd.AddCommand(NewCmdSELClear())
+	cmd.AddCommand(NewCmdSELDelete())
+	cmd.AddCommand(NewCmdSELAdd())
 
 	return cmd
 }
@@ -76,6 +88,7 @@ func NewCmdSELGet() *cobra.Command {
 			if err != nil {
 				CheckErr(fmt.Errorf("ParseSEL failed, err: %w", err))
 			}
+			sel.SetDecoder(selDecoder(ctx))
 			fmt.Println(ipmi.FormatSELs([]*ipmi.SEL{sel}, nil))
 		},
 	}
@@ -92,6 +105,7 @@ func NewCmdSELList() *cobra.Command {
 			if err != nil {
 				CheckErr(fmt.Errorf("GetSELInfo failed, err: %w", err))
 			}
+			ipmi.SetSELDecoder(selEntries, selDecoder(ctx))
 
 			fmt.Println(ipmi.FormatSELs(selEntries, nil))
 		},
@@ -114,9 +128,314 @@ func NewCmdSELElist() *cobra.Command {
 			if err != nil {
 				CheckErr(fmt.Errorf("GetSELInfo failed, err: %w", err))
 			}
+			ipmi.SetSELDecoder(selEntries, selDecoder(ctx))
 
 			fmt.Println(ipmi.FormatSELs(selEntries, sdrsMap))
 		},
 	}
 	return cmd
 }
+
+func NewCmdSELFollow() *cobra.Command {
+	var interval time.Duration
+	var fromStart bool
+	var bookmarkFile string
+	var bmcBookmark bool
+
+	cmd := &cobra.Command{
+		Use:   "follow",
+		Short: "print the SEL records as they are added, until interrupted by Ctrl-C",
+		Run: func(cmd *cobra.Command, args []string) {
+			ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+			defer stop()
+
+			opts := &ipmi.WatchSELOptions{
+				Interval:  interval,
+				FromStart: fromStart,
+				OnReset: func(reason ipmi.SELResetReason) {
+					fmt.Fprintf(os.Stderr, "SEL %s, following from the first record\n", reason)
+				},
+				OnError: func(err error) {
+					fmt.Fprintf(os.Stderr, "poll SEL failed, err: %s\n", err)
+				},
+			}
+			switch {
+			case bookmarkFile != "" && bmcBookmark:
+				CheckErr(errors.New("--bookmark-file and --bmc-bookmark are exclusive"))
+			case bookmarkFile != "":
+				opts.Bookmark = ipmi.SELFileBookmark(bookmarkFile)
+			case bmcBookmark:
+				opts.Bookmark = client.SELBMCBookmark()
+			}
+
+			// the sensor names are only for display, the sensor numbers are printed if not read
+			sdrsMap, err := client.GetSDRsMap(ctx)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "GetSDRsMap failed, err: %s\n", err)
+			}
+
+			ch, err := client.WatchSEL(ctx, opts)
+			if err != nil {
+				CheckErr(fmt.Errorf("WatchSEL failed, err: %w", err))
+			}
+			decoder := selDecoder(ctx)
+			for sel := range ch {
+				sel.SetDecoder(decoder)
+				fmt.Println(ipmi.FormatSELLine(sel, sdrsMap))
+			}
+		},
+	}
+
+	cmd.PersistentFlags().DurationVarP(&interval, "interval", "", 10*time.Second, "poll interval")
+	cmd.PersistentFlags().BoolVarP(&fromStart, "from-start", "", false, "print the records already in the SEL if no bookmark is saved")
+	cmd.PersistentFlags().StringVarP(&bookmarkFile, "bookmark-file", "", "", "save the last printed record to the file, and continue after it")
+	cmd.PersistentFlags().BoolVarP(&bmcBookmark, "bmc-bookmark", "", false, "save the last printed record on the BMC as the last record processed by software, and continue after it")
+
+	return cmd
+}
+
+func NewCmdSELSave() *cobra.Command {
+	usage := `sel save <file> [--format text|json|csv]`
+
+	var format string
+
+	cmd := &cobra.Command{
+		Use:   "save",
+		Short: "save the decoded SEL records to a file",
+		Run: func(cmd *cobra.Command, args []string) {
+			if len(args) < 1 {
+				CheckErr(fmt.Errorf("no file supplied, usage: %s", usage))
+			}
+
+			var write func(w io.Writer, records []*ipmi.SEL, sdrMap ipmi.SDRMapBySensorNumber) error
+			switch format {
+			case "text":
+				write = ipmi.WriteSELsText
+			case "json":
+				write = ipmi.WriteSELsJSON
+			case "csv":
+				write = ipmi.WriteSELsCSV
+			default:
+				CheckErr(fmt.Errorf("unsupported format %q, usage: %s", format, usage))
+			}
+
+			ctx := context.Background()
+			sdrsMap, err := client.GetSDRsMap(ctx)
+			if err != nil {
+				CheckErr(fmt.Errorf("GetSDRsMap failed, err: %w", err))
+			}
+
+			selEntries, err := client.GetSELEntries(ctx, 0)
+			if err != nil {
+				CheckErr(fmt.Errorf("GetSELEntries failed, err: %w", err))
+			}
+			ipmi.SetSELDecoder(selEntries, selDecoder(ctx))
+
+			f, err := os.Create(args[0])
+			if err != nil {
+				CheckErr(fmt.Errorf("create file failed, err: %w", err))
+			}
+			defer f.Close()
+
+			if err := write(f, selEntries, sdrsMap); err != nil {
+				CheckErr(err)
+			}
+			if err := f.Close(); err != nil {
+				CheckErr(fmt.Errorf("close file failed, err: %w", err))
+			}
+
+			fmt.Printf("Saved %d SEL records to %s\n", len(selEntries), args[0])
+		},
+	}
+
+	cmd.PersistentFlags().StringVarP(&format, "format", "", "text", "file format, text, json or csv")
+
+	return cmd
+}
+
+func NewCmdSELWriteRaw() *cobra.Command {
+	usage := `sel writeraw <file>`
+
+	cmd := &cobra.Command{
+		Use:   "writeraw",
+		Short: "write the raw SEL records to a file, which can be decoded offline by 'sel readraw'",
+		Run: func(cmd *cobra.Command, args []string) {
+			if len(args) < 1 {
+				CheckErr(fmt.Errorf("no file supplied, usage: %s", usage))
+			}
+
+			ctx := context.Background()
+			selEntries, err := client.GetSELEntries(ctx, 0)
+			if err != nil {
+				CheckErr(fmt.Errorf("GetSELEntries failed, err: %w", err))
+			}
+
+			f, err := os.Create(args[0])
+			if err != nil {
+				CheckErr(fmt.Errorf("create file failed, err: %w", err))
+			}
+			defer f.Close()
+
+			if err := ipmi.WriteSELsRaw(f, selEntries); err != nil {
+				CheckErr(err)
+			}
+			if err := f.Close(); err != nil {
+				CheckErr(fmt.Errorf("close file failed, err: %w", err))
+			}
+
+			fmt.Printf("Wrote %d raw SEL records to %s\n", len(selEntries), args[0])
+		},
+	}
+
+	return cmd
+}
+
+func NewCmdSELReadRaw() *cobra.Command {
+	usage := `sel readraw <file>
+	The sensor names are resolved offline if the SDRs written by 'sdr dump' are supplied by --sdr-cache-file,
+	and the vendor specific records are decoded if the manufacturer of the BMC is supplied by --manufacturer-id.
+	`
+
+	var manufacturerID uint32
+	var productID uint16
+
+	cmd := &cobra.Command{
+		Use:   "readraw",
+		Short: "decode the raw SEL records written by 'sel writeraw', no BMC is connected",
+		// decoded offline, overrides the hooks connecting the BMC
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			return nil
+		},
+		PersistentPostRunE: func(cmd *cobra.Command, args []string) error {
+			return nil
+		},
+		Run: func(cmd *cobra.Command, args []string) {
+			if len(args) < 1 {
+				CheckErr(fmt.Errorf("no file supplied, usage: %s", usage))
+			}
+
+			f, err := os.Open(args[0])
+			if err != nil {
+				CheckErr(fmt.Errorf("open file failed, err: %w", err))
+			}
+			defer f.Close()
+
+			selEntries, err := ipmi.ReadSELsRaw(f)
+			if err != nil {
+				CheckErr(fmt.Errorf("ReadSELsRaw failed, err: %w", err))
+			}
+
+			var sdrsMap ipmi.SDRMapBySensorNumber
+			if sdrCacheFile != "" {
+				sdrFile, err := os.Open(sdrCacheFile)
+				if err != nil {
+					CheckErr(fmt.Errorf("open sdr cache file failed, err: %w", err))
+				}
+				defer sdrFile.Close()
+
+				sdrs, err := ipmi.LoadSDRs(sdrFile)
+				if err != nil {
+					CheckErr(fmt.Errorf("LoadSDRs failed, err: %w", err))
+				}
+				sdrsMap = ipmi.NewSDRMapBySensorNumber(sdrs)
+			}
+
+			if manufacturerID != 0 {
+				ipmi.SetSELDecoder(selEntries, ipmi.FindSELDecoder(ipmi.OEM(manufacturerID), productID))
+			}
+
+			fmt.Println(ipmi.FormatSELs(selEntries, sdrsMap))
+		},
+	}
+
+	cmd.PersistentFlags().Uint32VarP(&manufacturerID, "manufacturer-id", "", 0, "manufacturer ID (IANA Enterprise Number) of the BMC, see 'mc info'")
+	cmd.PersistentFlags().Uint16VarP(&productID, "product-id", "", 0, "product ID of the BMC, see 'mc info'")
+
+	return cmd
+}
+
+func NewCmdSELClear() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "clear",
+		Short: "clear all the SEL records, and wait for the erasure to complete",
+		Run: func(cmd *cobra.Command, args []string) {
+			ctx := context.Background()
+			if err := client.ClearSELAndWait(ctx, 500*time.Millisecond); err != nil {
+				CheckErr(fmt.Errorf("ClearSELAndWait failed, err: %w", err))
+			}
+			fmt.Println("SEL cleared")
+		},
+	}
+	return cmd
+}
+
+func NewCmdSELDelete() *cobra.Command {
+	usage := `sel delete <id>...`
+
+	cmd := &cobra.Command{
+		Use:   "delete",
+		Short: "delete the SEL records by Record IDs",
+		Run: func(cmd *cobra.Command, args []string) {
+			if len(args) < 1 {
+				CheckErr(fmt.Errorf("no Record ID supplied, usage: %s", usage))
+			}
+
+			recordIDs := make([]uint16, 0, len(args))
+			for _, arg := range args {
+				id, err := parseStringToInt64(arg)
+				if err != nil {
+					CheckErr(fmt.Errorf("invalid Record ID passed, err: %w", err))
+				}
+				recordIDs = append(recordIDs, uint16(id))
+			}
+
+			ctx := context.Background()
+			if err := client.DeleteSELEntries(ctx, recordIDs); err != nil {
+				CheckErr(fmt.Errorf("DeleteSELEntries failed, err: %w", err))
+			}
+			fmt.Printf("Deleted %d SEL records\n", len(recordIDs))
+		},
+	}
+	return cmd
+}
+
+func NewCmdSELAdd() *cobra.Command {
+	presets := make([]string, 0, len(ipmi.SELEventPresets))
+	for _, preset := range ipmi.SELEventPresets {
+		presets = append(presets, fmt.Sprintf("  %-12s %s", preset.Name, preset.Description))
+	}
+	usage := fmt.Sprintf("sel add <event-preset>\n\nevent presets:\n%s", strings.Join(presets, "\n"))
+
+	cmd := &cobra.Command{
+		Use:   "add",
+		Short: "add a predefined SEL record, to test the SEL and the alerting",
+		Long:  usage,
+		Run: func(cmd *cobra.Command, args []string) {
+			if len(args) < 1 {
+				CheckErr(fmt.Errorf("no event preset supplied, usage: %s", usage))
+			}
+
+			preset := ipmi.FindSELEventPreset(args[0])
+			if preset == nil {
+				CheckErr(fmt.Errorf("unknown event preset %q, usage: %s", args[0], usage))
+			}
+
+			ctx := context.Background()
+			res, err := client.AddSELEntry(ctx, preset.SEL())
+			if err != nil {
+				CheckErr(fmt.Errorf("AddSELEntry failed, err: %w", err))
+			}
+			fmt.Printf("Added SEL record %#04x: %s\n", res.RecordID, preset.Description)
+		},
+	}
+	return cmd
+}
+
+// selDecoder returns the decoder of the vendor specific SEL records of the BMC, nil if not known.
+func selDecoder(ctx context.Context) ipmi.SELDecoder {
+	decoder, err := client.SELDecoder(ctx)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "SELDecoder failed, err: %s\n", err)
+	}
+	return decoder
+}
@@ -2,7 +2,13 @@ package commands
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/bougou/go-ipmi"
 	"github.com/spf13/cobra"
@@ -25,11 +31,14 @@ func NewCmdSensor() *cobra.Command {
 	cmd.AddCommand(NewCmdSensorGet())
 	cmd.AddCommand(NewCmdSensorList())
 	cmd.AddCommand(NewCmdSensorThreshold())
+	cmd.AddCommand(NewCmdSensorThresh())
 	cmd.AddCommand(NewCmdSensorEventEnable())
+	cmd.AddCommand(NewCmdSensorEvents())
 	cmd.AddCommand(NewCmdSensorEventStatus())
 	cmd.AddCommand(NewCmdSensorReading())
 	cmd.AddCommand(NewCmdSensorReadingFactors())
 	cmd.AddCommand(NewCmdSensorDetail())
+	cmd.AddCommand(NewCmdSensorWatch())
 
 	return cmd
 }
@@ -164,6 +173,152 @@ sensor threshold get <sensor_number>
 	return cmd
 }
 
+func NewCmdSensorThresh() *cobra.Command {
+	usage := `
+sensor thresh <sensorName> <threshold> <value>
+	threshold is one of unr, ucr, unc, lnc, lcr, lnr
+sensor thresh <sensorName> lower <lnr> <lcr> <lnc>
+sensor thresh <sensorName> upper <unc> <ucr> <unr>
+	sensorName should be quoted if contains space
+	`
+	cmd := &cobra.Command{
+		Use:   "thresh",
+		Short: "set the thresholds of a sensor by the values in the units of the sensor",
+		Run: func(cmd *cobra.Command, args []string) {
+			if len(args) < 3 {
+				CheckErr(fmt.Errorf("usage: %s", usage))
+			}
+
+			sensorName := args[0]
+
+			var thresholdTypes []ipmi.SensorThresholdType
+			var valueArgs []string
+			switch args[1] {
+			case "lower":
+				thresholdTypes = []ipmi.SensorThresholdType{ipmi.SensorThresholdType_LNR, ipmi.SensorThresholdType_LCR, ipmi.SensorThresholdType_LNC}
+				valueArgs = args[2:]
+			case "upper":
+				thresholdTypes = []ipmi.SensorThresholdType{ipmi.SensorThresholdType_UNC, ipmi.SensorThresholdType_UCR, ipmi.SensorThresholdType_UNR}
+				valueArgs = args[2:]
+			default:
+				for _, thresholdType := range []ipmi.SensorThresholdType{
+					ipmi.SensorThresholdType_UNR, ipmi.SensorThresholdType_UCR, ipmi.SensorThresholdType_UNC,
+					ipmi.SensorThresholdType_LNC, ipmi.SensorThresholdType_LCR, ipmi.SensorThresholdType_LNR,
+				} {
+					if thresholdType.Abbr() == args[1] {
+						thresholdTypes = []ipmi.SensorThresholdType{thresholdType}
+					}
+				}
+				if len(thresholdTypes) == 0 {
+					CheckErr(fmt.Errorf("invalid threshold %q, usage: %s", args[1], usage))
+				}
+				valueArgs = args[2:]
+			}
+			if len(valueArgs) != len(thresholdTypes) {
+				CheckErr(fmt.Errorf("%d values are required, usage: %s", len(thresholdTypes), usage))
+			}
+
+			values := make(map[ipmi.SensorThresholdType]float64)
+			for i, thresholdType := range thresholdTypes {
+				value, err := strconv.ParseFloat(valueArgs[i], 64)
+				if err != nil {
+					CheckErr(fmt.Errorf("invalid %s value %q, err: %w", thresholdType.Abbr(), valueArgs[i], err))
+				}
+				values[thresholdType] = value
+			}
+
+			ctx := context.Background()
+			sensor, err := client.SetSensorThresholdsByValue(ctx, sensorName, values)
+			if err != nil {
+				CheckErr(fmt.Errorf("SetSensorThresholdsByValue failed, err: %w", err))
+			}
+
+			for _, thresholdType := range thresholdTypes {
+				threshold := sensor.SensorThreshold(thresholdType)
+				if !threshold.Mask.Readable {
+					fmt.Printf("Set %s threshold of %s, it is not readable to confirm\n", thresholdType.Abbr(), sensor.Name)
+					continue
+				}
+				fmt.Printf("Set %s threshold of %s to %s %s (raw %#02x)\n",
+					thresholdType.Abbr(), sensor.Name, sensor.ThresholdStr(thresholdType), sensor.SensorUnit, threshold.Raw)
+			}
+		},
+	}
+	return cmd
+}
+
+func NewCmdSensorEvents() *cobra.Command {
+	usage := `
+sensor events enable <sensorNumber> or <sensorName> [events...]
+sensor events disable <sensorNumber> or <sensorName> [events...]
+sensor events rearm <sensorNumber> or <sensorName> [events...]
+	Without events, the event messages of the whole sensor are enabled or disabled, or all the events are re-armed.
+	An event is the event offset (0-14, or state0-state14), the threshold event (like ucr+, lnc-, or ucr for both directions),
+	or the event name (like "Thermal Trip"), which should be quoted if contains space.
+	`
+	cmd := &cobra.Command{
+		Use:   "events",
+		Short: "enable, disable or re-arm the events of a sensor",
+		Run: func(cmd *cobra.Command, args []string) {
+			if len(args) < 2 {
+				CheckErr(fmt.Errorf("usage: %s", usage))
+			}
+
+			action := args[0]
+			events := args[2:]
+			ctx := context.Background()
+
+			var sdr *ipmi.SDR
+			var err error
+			if id, err := parseStringToInt64(args[1]); err == nil {
+				sdr, err = client.GetSDRBySensorID(ctx, uint8(id))
+				if err != nil {
+					CheckErr(fmt.Errorf("GetSDRBySensorID failed, err: %w", err))
+				}
+			} else {
+				sdr, err = client.GetSDRBySensorName(ctx, args[1])
+				if err != nil {
+					CheckErr(fmt.Errorf("GetSDRBySensorName failed, err: %w", err))
+				}
+			}
+			sensorName := sdr.SensorName()
+
+			var res *ipmi.GetSensorEventEnableResponse
+			switch action {
+			case "enable", "disable":
+				res, err = client.SetSensorEventsEnabled(ctx, sensorName, action == "enable", events...)
+				if err != nil {
+					CheckErr(fmt.Errorf("SetSensorEventsEnabled failed, err: %w", err))
+				}
+			case "rearm":
+				if err := client.RearmSensorEventsByName(ctx, sensorName, events...); err != nil {
+					CheckErr(fmt.Errorf("RearmSensorEventsByName failed, err: %w", err))
+				}
+				fmt.Printf("Re-armed the events of %s\n", sensorName)
+				return
+			default:
+				CheckErr(fmt.Errorf("usage: %s", usage))
+			}
+
+			eventNames := func(mask uint16) []string {
+				out := make([]string, 0)
+				for offset := uint8(0); offset <= 14; offset++ {
+					if mask&(1<<offset) != 0 {
+						out = append(out, ipmi.SensorEventOffsetName(offset, sdr.EventReadingType(), sdr.SensorType()))
+					}
+				}
+				return out
+			}
+			assertMask, deassertMask := res.SensorEventFlag.EventOffsets(sdr.EventReadingType().IsThreshold())
+			fmt.Printf("Sensor                     : %s (%#02x)\n", sensorName, uint8(sdr.SensorNumber()))
+			fmt.Printf("Event Messages Disabled    : %v\n", res.EventMessagesDisabled)
+			fmt.Printf("Enabled Assertion Events   : %s\n", strings.Join(eventNames(assertMask), ", "))
+			fmt.Printf("Enabled Deassertion Events : %s\n", strings.Join(eventNames(deassertMask), ", "))
+		},
+	}
+	return cmd
+}
+
 func NewCmdSensorEventStatus() *cobra.Command {
 	usage := `
 sensor event-status get <sensor_number>
@@ -355,3 +510,64 @@ sensor detail <sensor_number>
 	}
 	return cmd
 }
+
+func NewCmdSensorWatch() *cobra.Command {
+	usage := `
+sensor watch [sensorNumbers...]
+	Poll the sensors (all the sensors if no sensor number is supplied) and print the changes,
+	until interrupted by Ctrl-C.
+	`
+
+	var interval time.Duration
+	var delta float64
+	var filterThreshold bool
+	var filterReadingValid bool
+
+	cmd := &cobra.Command{
+		Use:   "watch",
+		Short: "poll the sensors and print the changes",
+		Run: func(cmd *cobra.Command, args []string) {
+			sensorNumbers := make([]uint8, 0)
+			for _, arg := range args {
+				id, err := parseStringToInt64(arg)
+				if err != nil {
+					CheckErr(fmt.Errorf("invalid sensor number %q, usage: %s", arg, usage))
+				}
+				sensorNumbers = append(sensorNumbers, uint8(id))
+			}
+
+			filterOptions := make([]ipmi.SensorFilterOption, 0)
+			if filterThreshold {
+				filterOptions = append(filterOptions, ipmi.SensorFilterOptionIsThreshold)
+			}
+			if filterReadingValid {
+				filterOptions = append(filterOptions, ipmi.SensorFilterOptionIsReadingValid)
+			}
+
+			ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+			defer stop()
+
+			watcher := client.NewSensorWatcher(interval, filterOptions...).
+				WithSensorNumbers(sensorNumbers...).
+				WithReadingDelta(delta).
+				OnChange(func(change *ipmi.SensorChange) {
+					fmt.Printf("%s %s\n", change.Time.Format(time.RFC3339), change)
+				}).
+				OnError(func(err error) {
+					fmt.Fprintf(os.Stderr, "poll sensors failed, err: %s\n", err)
+				})
+
+			err := watcher.Run(ctx)
+			if err != nil && !errors.Is(err, context.Canceled) {
+				CheckErr(fmt.Errorf("SensorWatcher failed, err: %w", err))
+			}
+		},
+	}
+
+	cmd.PersistentFlags().DurationVarP(&interval, "interval", "", 5*time.Second, "poll interval")
+	cmd.PersistentFlags().Float64VarP(&delta, "delta", "", 0, "print the reading changes only if at least delta")
+	cmd.PersistentFlags().BoolVarP(&filterThreshold, "threshold", "", false, "filter threshold sensor class")
+	cmd.PersistentFlags().BoolVarP(&filterReadingValid, "valid", "", false, "filter sensor that has valid reading")
+
+	return cmd
+}
@@ -3,8 +3,12 @@ package commands
 import (
 	"context"
 	"fmt"
+	"io"
+	"os"
 
+	"github.com/bougou/go-ipmi"
 	"github.com/spf13/cobra"
+	"golang.org/x/term"
 )
 
 func NewCmdSOL() *cobra.Command {
@@ -21,6 +25,8 @@ func NewCmdSOL() *cobra.Command {
 		},
 	}
 	cmd.AddCommand(NewCmdSOLInfo())
+	cmd.AddCommand(NewCmdSOLActivate())
+	cmd.AddCommand(NewCmdSOLDeactivate())
 
 	return cmd
 }
@@ -40,3 +46,160 @@ func NewCmdSOLInfo() *cobra.Command {
 	}
 	return cmd
 }
+
+func NewCmdSOLActivate() *cobra.Command {
+	var instance uint8
+	var escapeChar string
+
+	cmd := &cobra.Command{
+		Use:   "activate",
+		Short: "activate",
+		Run: func(cmd *cobra.Command, args []string) {
+			if len(escapeChar) != 1 {
+				CheckErr(fmt.Errorf("escape char must be a single character, got %q", escapeChar))
+			}
+
+			ctx := context.Background()
+			sol, err := client.OpenSOL(ctx, instance)
+			if err != nil {
+				CheckErr(fmt.Errorf("OpenSOL failed, err: %w", err))
+			}
+			defer sol.Close()
+
+			fmt.Printf("[SOL Session operational.  Use %s? for help]\r\n", escapeChar)
+
+			if term.IsTerminal(int(os.Stdin.Fd())) {
+				oldState, err := term.MakeRaw(int(os.Stdin.Fd()))
+				if err != nil {
+					CheckErr(fmt.Errorf("set terminal raw mode failed, err: %w", err))
+				}
+				defer term.Restore(int(os.Stdin.Fd()), oldState)
+			}
+
+			errCh := make(chan error, 2)
+			go func() {
+				_, err := io.Copy(os.Stdout, sol)
+				errCh <- err
+			}()
+			go func() {
+				errCh <- solConsoleInput(sol, os.Stdin, escapeChar[0])
+			}()
+
+			if err := <-errCh; err != nil {
+				fmt.Printf("\r\n[SOL Session error: %s]\r\n", err)
+				return
+			}
+			fmt.Printf("\r\n[terminated SOL session]\r\n")
+		},
+	}
+
+	cmd.Flags().Uint8VarP(&instance, "instance", "", 1, "SOL payload instance")
+	cmd.Flags().StringVarP(&escapeChar, "escape-char", "e", "~", "escape character, recognized at the beginning of a line")
+
+	return cmd
+}
+
+// solConsoleInput sends the characters read from r to the SOL session,
+// until the escape sequence to terminate the session is read.
+//
+// Supported escape sequences (the escape char is only recognized at the beginning of a line):
+//
+//	~.  terminate the SOL session
+//	~B  send a BREAK to the serial controller
+//	~?  print the supported escape sequences
+//	~~  send the escape character
+func solConsoleInput(sol *ipmi.SOLSession, r io.Reader, escape byte) error {
+	buf := make([]byte, 1024)
+	lineStart := true
+	escaped := false
+
+	for {
+		n, err := r.Read(buf)
+		if err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return err
+		}
+
+		out := make([]byte, 0, n)
+		for _, b := range buf[:n] {
+			if escaped {
+				escaped = false
+				switch b {
+				case '.':
+					if len(out) > 0 {
+						if _, err := sol.Write(out); err != nil {
+							return err
+						}
+					}
+					return nil
+				case 'B':
+					if len(out) > 0 {
+						if _, err := sol.Write(out); err != nil {
+							return err
+						}
+						out = out[:0]
+					}
+					if err := sol.SendBreak(); err != nil {
+						return err
+					}
+					fmt.Printf("[sent BREAK]\r\n")
+					continue
+				case '?':
+					fmt.Printf("\r\nSupported escape sequences:\r\n")
+					fmt.Printf("  %c.  - terminate connection\r\n", escape)
+					fmt.Printf("  %cB  - send a BREAK\r\n", escape)
+					fmt.Printf("  %c?  - this message\r\n", escape)
+					fmt.Printf("  %c%c  - send the escape character by typing it twice\r\n", escape, escape)
+					fmt.Printf("  (Note that escapes are only recognized immediately after newline.)\r\n")
+					continue
+				case escape:
+					out = append(out, escape)
+				default:
+					out = append(out, escape, b)
+				}
+				lineStart = false
+				continue
+			}
+
+			if lineStart && b == escape {
+				escaped = true
+				continue
+			}
+
+			out = append(out, b)
+			lineStart = b == '\r' || b == '\n'
+		}
+
+		if len(out) > 0 {
+			if _, err := sol.Write(out); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func NewCmdSOLDeactivate() *cobra.Command {
+	var instance uint8
+
+	cmd := &cobra.Command{
+		Use:   "deactivate",
+		Short: "deactivate",
+		Run: func(cmd *cobra.Command, args []string) {
+			ctx := context.Background()
+			request := &ipmi.DeactivatePayloadRequest{
+				PayloadType:     ipmi.PayloadTypeSOL,
+				PayloadInstance: instance,
+			}
+			if _, err := client.DeactivatePayload(ctx, request); err != nil {
+				CheckErr(fmt.Errorf("DeactivatePayload failed, err: %w", err))
+			}
+			fmt.Println("SOL payload deactivated")
+		},
+	}
+
+	cmd.Flags().Uint8VarP(&instance, "instance", "", 1, "SOL payload instance")
+
+	return cmd
+}
@@ -0,0 +1,128 @@
+package ipmi
+
+import (
+	"context"
+	"fmt"
+)
+
+// 33.13 Add SDR Command
+type AddSDRRequest struct {
+	// RecordData is the complete SDR record, starting with the record header.
+	// The Record ID field in the header is ignored, the BMC assigns the Record ID.
+	RecordData []byte
+}
+
+type AddSDRResponse struct {
+	RecordID uint16 // Record ID for added record, LS Byte first
+}
+
+func (req *AddSDRRequest) Command() Command {
+	return CommandAddSDR
+}
+
+func (req *AddSDRRequest) Pack() []byte {
+	return req.RecordData
+}
+
+func (res *AddSDRResponse) Unpack(msg []byte) error {
+	if len(msg) < 2 {
+		return ErrUnpackedDataTooShortWith(len(msg), 2)
+	}
+	res.RecordID, _, _ = unpackUint16L(msg, 0)
+	return nil
+}
+
+func (res *AddSDRResponse) CompletionCodes() map[uint8]string {
+	// no command-specific cc
+	return map[uint8]string{}
+}
+
+func (res *AddSDRResponse) Format() string {
+	return fmt.Sprintf("Record ID : %d (%#02x)", res.RecordID, res.RecordID)
+}
+
+// AddSDR adds the complete SDR record to the SDR Repository in one request,
+// see AddSDRRecord for the records exceeding the request size of the interface.
+func (c *Client) AddSDR(ctx context.Context, recordData []byte) (response *AddSDRResponse, err error) {
+	request := &AddSDRRequest{
+		RecordData: recordData,
+	}
+	response = &AddSDRResponse{}
+	err = c.Exchange(ctx, request, response)
+	return
+}
+
+// AddSDRRecord adds the complete SDR record to the SDR Repository and returns the Record ID assigned by the BMC.
+// The record is added by one Add SDR request if it fits the request size of the interface,
+// otherwise by Partial Add SDR requests in chunks, which are restarted once if the reservation is canceled.
+// If the BMC refuses the length of the request, the record is added in smaller chunks.
+func (c *Client) AddSDRRecord(ctx context.Context, recordData []byte) (uint16, error) {
+	if len(recordData) < SDRRecordHeaderSize {
+		return 0, ErrNotEnoughDataWith("sdr record header size", len(recordData), SDRRecordHeaderSize)
+	}
+
+	// the Partial Add SDR request carries 6 bytes besides the record data
+	chunkSize := c.maxRequestDataSize(ctx, &PartialAddSDRRequest{}) - 6
+
+	if len(recordData) <= c.maxRequestDataSize(ctx, &AddSDRRequest{}) {
+		res, err := c.AddSDR(ctx, recordData)
+		if !isRequestLengthError(err) {
+			if err != nil {
+				return 0, fmt.Errorf("AddSDR failed, err: %w", err)
+			}
+			return res.RecordID, nil
+		}
+		c.Debugf("Add SDR of %d bytes refused for the length, adding in parts\n", len(recordData))
+		chunkSize = min(chunkSize, len(recordData)/2)
+	}
+
+	recordID, err := c.partialAddSDRRecord(ctx, recordData, chunkSize)
+	if err != nil {
+		return 0, fmt.Errorf("PartialAddSDR failed, err: %w", err)
+	}
+	return recordID, nil
+}
+
+// partialAddSDRRecord adds the record by Partial Add SDR requests in chunks of chunkSize bytes,
+// the chunks are halved as long as the BMC refuses the length of the requests.
+func (c *Client) partialAddSDRRecord(ctx context.Context, recordData []byte, chunkSize int) (uint16, error) {
+	if chunkSize <= 0 {
+		return 0, fmt.Errorf("no room for the record data in the request")
+	}
+
+	reservationID, err := c.reserveSDRRepoID(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	for {
+		var recordID uint16
+		reservationID, err = c.withReservation(ctx, reservationID, c.reserveSDRRepoID, func(reservationID uint16) (err error) {
+			recordID, err = c.partialAddSDRChunks(ctx, reservationID, recordData, chunkSize)
+			return err
+		})
+		if !isRequestLengthError(err) || chunkSize == 1 {
+			return recordID, err
+		}
+		c.Debugf("Partial Add SDR of %d bytes refused for the length\n", chunkSize)
+		chunkSize /= 2
+	}
+}
+
+// partialAddSDRChunks adds the record by Partial Add SDR requests under the reservation.
+func (c *Client) partialAddSDRChunks(ctx context.Context, reservationID uint16, recordData []byte, chunkSize int) (uint16, error) {
+	var recordID uint16 = 0x0000
+	for offset := 0; offset < len(recordData); offset += chunkSize {
+		end := offset + chunkSize
+		if end > len(recordData) {
+			end = len(recordData)
+		}
+
+		res, err := c.PartialAddSDR(ctx, reservationID, recordID, uint8(offset), end == len(recordData), recordData[offset:end])
+		if err != nil {
+			return 0, err
+		}
+		recordID = res.RecordID
+	}
+	return recordID, nil
+}
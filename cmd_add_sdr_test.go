@@ -0,0 +1,166 @@
+package ipmi_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/bougou/go-ipmi"
+	"github.com/bougou/go-ipmi/ipmitest"
+)
+
+func TestClient_SDRWrite(t *testing.T) {
+	ctx := context.Background()
+
+	s := newTestServer(t, func(s *ipmitest.Server) {
+		s.AddSDR(fullSensorSDR(0x02, "PCH Temp"))
+		s.SetSensor(0x02, ipmitest.Sensor{Reading: 50})
+	})
+	target := newTestServer(t, func(s *ipmitest.Server) {
+		s.SDRModalUpdate = true
+	})
+
+	commands := make(map[string]int)
+	// maxChunk is the longest record data of the Partial Add SDR requests
+	maxChunk := 0
+	countCommands := func(ctx context.Context, request ipmi.Request, response ipmi.Response, next ipmi.ExchangeFunc) error {
+		commands[request.Command().Name]++
+		if req, ok := request.(*ipmi.PartialAddSDRRequest); ok {
+			maxChunk = max(maxChunk, len(req.RecordData))
+		}
+		return next(ctx, request, response)
+	}
+
+	newClient := func(s *ipmitest.Server) *ipmi.Client {
+		c := newTestClient(t, s, ipmi.InterfaceLanplus, testPassword).WithInterceptor(countCommands)
+		if err := c.Connect(ctx); err != nil {
+			t.Fatalf("Connect failed, err: %s", err)
+		}
+		t.Cleanup(func() { c.Close(ctx) })
+		return c
+	}
+
+	var dump bytes.Buffer
+	if err := newClient(s).DumpSDRs(ctx, &dump); err != nil {
+		t.Fatalf("DumpSDRs failed, err: %s", err)
+	}
+
+	c := newClient(target)
+	for k := range commands {
+		delete(commands, k)
+	}
+	if err := c.FillSDRRepo(ctx, &dump); err != nil {
+		t.Fatalf("FillSDRRepo failed, err: %s", err)
+	}
+	if commands[ipmi.CommandEnterSDRRepoUpdateMode.Name] != 1 || commands[ipmi.CommandExitSDRRepoUpdateMode.Name] != 1 {
+		t.Errorf("FillSDRRepo did not enter and exit the update mode of the modal SDR Repository, commands: %v", commands)
+	}
+	if commands[ipmi.CommandClearSDRRepo.Name] < 2 {
+		t.Errorf("FillSDRRepo did not poll the erasure status, commands: %v", commands)
+	}
+	// the full sensor records exceed the request size, so they are added in parts
+	if commands[ipmi.CommandAddSDR.Name] != 0 || commands[ipmi.CommandPartialAddSDR.Name] < 4 {
+		t.Errorf("FillSDRRepo did not add the records in parts, commands: %v", commands)
+	}
+	// the lanplus request data is up to 38 bytes, 6 of them are taken by the Partial Add SDR fields
+	if maxChunk != 32 {
+		t.Errorf("FillSDRRepo added the records in parts of up to %d bytes, want 32", maxChunk)
+	}
+
+	sdrs, err := c.GetSDRs(ctx)
+	if err != nil {
+		t.Fatalf("GetSDRs failed, err: %s", err)
+	}
+	if len(sdrs) != 2 || sdrs[0].SensorName() != "CPU Temp" || sdrs[1].SensorName() != "PCH Temp" {
+		t.Fatalf("GetSDRs returned %d SDRs after FillSDRRepo, want CPU Temp and PCH Temp", len(sdrs))
+	}
+
+	// the modal SDR Repository is not writable out of the update mode
+	oem := []byte{0, 0, 0x51, uint8(ipmi.SDRRecordTypeOEM), 4, 0x57, 0x01, 0x00, 0xaa}
+	if _, err := c.AddSDRRecord(ctx, oem); err == nil {
+		t.Errorf("AddSDRRecord succeeded out of the update mode")
+	}
+	if _, err := c.EnterSDRRepoUpdateMode(ctx); err != nil {
+		t.Fatalf("EnterSDRRepoUpdateMode failed, err: %s", err)
+	}
+	defer c.ExitSDRRepoUpdateMode(ctx)
+
+	for k := range commands {
+		delete(commands, k)
+	}
+	recordID, err := c.AddSDRRecord(ctx, oem)
+	if err != nil {
+		t.Fatalf("AddSDRRecord failed, err: %s", err)
+	}
+	if commands[ipmi.CommandAddSDR.Name] != 1 || commands[ipmi.CommandPartialAddSDR.Name] != 0 {
+		t.Errorf("AddSDRRecord of a short record did not use Add SDR, commands: %v", commands)
+	}
+
+	res, err := c.ReserveSDRRepo(ctx)
+	if err != nil {
+		t.Fatalf("ReserveSDRRepo failed, err: %s", err)
+	}
+	if _, err := c.DeleteSDR(ctx, recordID, res.ReservationID); err != nil {
+		t.Fatalf("DeleteSDR failed, err: %s", err)
+	}
+	// the reservation is canceled by the deletion
+	if _, err := c.DeleteSDR(ctx, sdrs[0].RecordHeader.RecordID, res.ReservationID); !hasCompletionCode(err, ipmi.CompletionCodeReservationCanceled) {
+		t.Errorf("DeleteSDR with canceled reservation returned err %v, want completion code 0xc5", err)
+	}
+
+	if _, err := c.RunInitializationAgent(ctx, false); err != nil {
+		t.Errorf("RunInitializationAgent failed, err: %s", err)
+	}
+}
+
+func TestClient_AddSDRRecordRequestLength(t *testing.T) {
+	ctx := context.Background()
+
+	s := newTestServer(t, func(s *ipmitest.Server) {
+		s.MaxRequestDataSize = 12
+	})
+
+	commands := make(map[string]int)
+	// maxChunk is the longest record data of the Partial Add SDR requests accepted
+	maxChunk := 0
+	countCommands := func(ctx context.Context, request ipmi.Request, response ipmi.Response, next ipmi.ExchangeFunc) error {
+		commands[request.Command().Name]++
+		err := next(ctx, request, response)
+		if req, ok := request.(*ipmi.PartialAddSDRRequest); ok && err == nil {
+			maxChunk = max(maxChunk, len(req.RecordData))
+		}
+		return err
+	}
+
+	c := newTestClient(t, s, ipmi.InterfaceLanplus, testPassword).WithInterceptor(countCommands)
+	if err := c.Connect(ctx); err != nil {
+		t.Fatalf("Connect failed, err: %s", err)
+	}
+	defer c.Close(ctx)
+
+	// an OEM record of 30 bytes fits the lanplus request size, but not the 12 bytes accepted by the BMC
+	oem := []byte{0, 0, 0x51, uint8(ipmi.SDRRecordTypeOEM), 25, 0x57, 0x01, 0x00}
+	for i := len(oem); i < 30; i++ {
+		oem = append(oem, uint8(i))
+	}
+
+	recordID, err := c.AddSDRRecord(ctx, oem)
+	if err != nil {
+		t.Fatalf("AddSDRRecord failed, err: %s", err)
+	}
+	if commands[ipmi.CommandAddSDR.Name] != 1 {
+		t.Errorf("AddSDRRecord did not try Add SDR first, commands: %v", commands)
+	}
+	// the chunks of 15 and 7 bytes are refused, the ones of 3 bytes are accepted
+	if maxChunk != 3 {
+		t.Errorf("AddSDRRecord added the record in parts of up to %d bytes, want 3", maxChunk)
+	}
+
+	res, err := c.GetSDR(ctx, recordID)
+	if err != nil {
+		t.Fatalf("GetSDR failed, err: %s", err)
+	}
+	if !bytes.Equal(res.RecordData[2:], oem[2:]) {
+		t.Errorf("GetSDR returned record % x, want % x", res.RecordData[2:], oem[2:])
+	}
+}
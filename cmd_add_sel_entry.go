@@ -3,6 +3,7 @@ package ipmi
 import (
 	"context"
 	"fmt"
+	"time"
 )
 
 // 31.6 Add SEL Entry Command
@@ -41,6 +42,8 @@ func (res *AddSELEntryResponse) Format() string {
 	return fmt.Sprintf("Record ID : %d (%#02x)", res.RecordID, res.RecordID)
 }
 
+// AddSELEntry adds the SEL record to the SEL, the BMC fills the Record ID,
+// and the timestamp of the standard and the timestamped OEM records.
 func (c *Client) AddSELEntry(ctx context.Context, sel *SEL) (response *AddSELEntryResponse, err error) {
 	request := &AddSELEntryRequest{
 		SEL: sel,
@@ -49,3 +52,152 @@ func (c *Client) AddSELEntry(ctx context.Context, sel *SEL) (response *AddSELEnt
 	err = c.Exchange(ctx, request, response)
 	return
 }
+
+// AddSELRecord adds the SEL record to the SEL and returns the Record ID assigned by the BMC.
+// The record is added by one Add SEL Entry request if it fits the request size of the interface (bridged requests),
+// otherwise by Partial Add SEL Entry requests in chunks, which are restarted once if the reservation is canceled.
+// If the BMC refuses the length of the request, the record is added in smaller chunks.
+func (c *Client) AddSELRecord(ctx context.Context, sel *SEL) (uint16, error) {
+	recordData := sel.Pack()
+
+	// the Partial Add SEL Entry request carries 6 bytes besides the record data
+	chunkSize := c.maxRequestDataSize(ctx, &PartialAddSELEntryRequest{}) - 6
+
+	if len(recordData) <= c.maxRequestDataSize(ctx, &AddSELEntryRequest{}) {
+		res, err := c.AddSELEntry(ctx, sel)
+		if !isRequestLengthError(err) {
+			if err != nil {
+				return 0, fmt.Errorf("AddSELEntry failed, err: %w", err)
+			}
+			return res.RecordID, nil
+		}
+		c.Debugf("Add SEL Entry of %d bytes refused for the length, adding in parts\n", len(recordData))
+		chunkSize = min(chunkSize, len(recordData)/2)
+	}
+
+	recordID, err := c.partialAddSELRecord(ctx, recordData, chunkSize)
+	if err != nil {
+		return 0, fmt.Errorf("PartialAddSELEntry failed, err: %w", err)
+	}
+	return recordID, nil
+}
+
+// partialAddSELRecord adds the record by Partial Add SEL Entry requests in chunks of chunkSize bytes,
+// the chunks are halved as long as the BMC refuses the length of the requests.
+func (c *Client) partialAddSELRecord(ctx context.Context, recordData []byte, chunkSize int) (uint16, error) {
+	if chunkSize <= 0 {
+		return 0, fmt.Errorf("no room for the record data in the request")
+	}
+
+	reservationID, err := c.reserveSELID(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	for {
+		var recordID uint16
+		reservationID, err = c.withReservation(ctx, reservationID, c.reserveSELID, func(reservationID uint16) (err error) {
+			recordID, err = c.partialAddSELChunks(ctx, reservationID, recordData, chunkSize)
+			return err
+		})
+		if !isRequestLengthError(err) || chunkSize == 1 {
+			return recordID, err
+		}
+		c.Debugf("Partial Add SEL Entry of %d bytes refused for the length\n", chunkSize)
+		chunkSize /= 2
+	}
+}
+
+// partialAddSELChunks adds the record by Partial Add SEL Entry requests under the reservation.
+func (c *Client) partialAddSELChunks(ctx context.Context, reservationID uint16, recordData []byte, chunkSize int) (uint16, error) {
+	var recordID uint16 = 0x0000
+	for offset := 0; offset < len(recordData); offset += chunkSize {
+		end := offset + chunkSize
+		if end > len(recordData) {
+			end = len(recordData)
+		}
+
+		res, err := c.PartialAddSELEntry(ctx, reservationID, recordID, uint8(offset), end == len(recordData), recordData[offset:end])
+		if err != nil {
+			return 0, err
+		}
+		recordID = res.RecordID
+	}
+	return recordID, nil
+}
+
+// SELEventPreset is a predefined standard SEL record, like the events generated by "ipmitool event 1|2|3",
+// which is added to test the SEL and the alerting of the BMC.
+// The sensor numbers are not checked against the SDRs.
+type SELEventPreset struct {
+	Name        string
+	Description string
+
+	SensorType       SensorType
+	SensorNumber     SensorNumber
+	EventReadingType EventReadingType
+	EventDir         EventDir
+	EventData        EventData
+}
+
+// SELEventPresets are the predefined SEL records, see FindSELEventPreset.
+var SELEventPresets = []SELEventPreset{
+	{
+		Name:             "temperature",
+		Description:      "Temperature - Upper Critical - Going High",
+		SensorType:       SensorTypeTemperature,
+		SensorNumber:     0x30,
+		EventReadingType: EventReadingTypeThreshold,
+		EventDir:         EventDirAssertion,
+		// offset 09h, trigger reading in byte 2 and trigger threshold in byte 3
+		EventData: EventData{EventData1: 0x59, EventData2: 0x5a, EventData3: 0x55},
+	},
+	{
+		Name:             "voltage",
+		Description:      "Voltage - Lower Critical - Going Low",
+		SensorType:       SensorTypeVoltage,
+		SensorNumber:     0x60,
+		EventReadingType: EventReadingTypeThreshold,
+		EventDir:         EventDirAssertion,
+		// offset 02h, trigger reading in byte 2 and trigger threshold in byte 3
+		EventData: EventData{EventData1: 0x52, EventData2: 0x9c, EventData3: 0xa0},
+	},
+	{
+		Name:             "memory",
+		Description:      "Memory - Correctable ECC",
+		SensorType:       SensorTypeMemory,
+		SensorNumber:     0x53,
+		EventReadingType: EventReadingTypeSensorSpecific,
+		EventDir:         EventDirAssertion,
+		// offset 00h, byte 2 and byte 3 unspecified
+		EventData: EventData{EventData1: 0x00, EventData2: 0xff, EventData3: 0xff},
+	},
+}
+
+// FindSELEventPreset returns the SELEventPreset of the name, nil if not found.
+func FindSELEventPreset(name string) *SELEventPreset {
+	for i := range SELEventPresets {
+		if SELEventPresets[i].Name == name {
+			return &SELEventPresets[i]
+		}
+	}
+	return nil
+}
+
+// SEL returns the standard SEL record of the preset generated by the BMC,
+// the timestamp is replaced by the BMC when the record is added by AddSELEntry.
+func (preset *SELEventPreset) SEL() *SEL {
+	return &SEL{
+		RecordType: 0x02, // system event record
+		Standard: &SELStandard{
+			Timestamp:        time.Now(),
+			GeneratorID:      GeneratorBMC,
+			EvMRev:           0x04, // IPMI v2.0
+			SensorType:       preset.SensorType,
+			SensorNumber:     preset.SensorNumber,
+			EventDir:         preset.EventDir,
+			EventReadingType: preset.EventReadingType,
+			EventData:        preset.EventData,
+		},
+	}
+}
@@ -0,0 +1,180 @@
+package ipmi_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/bougou/go-ipmi"
+	"github.com/bougou/go-ipmi/ipmitest"
+)
+
+func TestClient_AddSELRecord(t *testing.T) {
+	const (
+		transitChannel uint8 = 0x07
+		transitAddr    uint8 = 0x82
+		targetChannel  uint8 = 0x00
+		targetAddr     uint8 = 0x2c
+	)
+
+	ctx := context.Background()
+	s := newTestServer(t, func(s *ipmitest.Server) {
+		s.AddController(transitChannel, transitAddr).AddController(targetChannel, targetAddr)
+	})
+
+	commands := make(map[string]int)
+	// maxChunk is the longest record data of the Partial Add SEL Entry requests
+	maxChunk := 0
+	// cancelReservation makes another reservation before the next Partial Add SEL Entry request
+	cancelReservation := false
+	countCommands := func(ctx context.Context, request ipmi.Request, response ipmi.Response, next ipmi.ExchangeFunc) error {
+		commands[request.Command().Name]++
+		if req, ok := request.(*ipmi.PartialAddSELEntryRequest); ok {
+			maxChunk = max(maxChunk, len(req.RecordData))
+		}
+		if _, ok := request.(*ipmi.PartialAddSELEntryRequest); ok && cancelReservation {
+			cancelReservation = false
+			if err := next(ctx, &ipmi.ReserveSELRequest{}, &ipmi.ReserveSELResponse{}); err != nil {
+				return err
+			}
+		}
+		return next(ctx, request, response)
+	}
+	newClient := func(bridged bool) *ipmi.Client {
+		c := newTestClient(t, s, ipmi.InterfaceLanplus, testPassword).WithInterceptor(countCommands)
+		if bridged {
+			c.WithTarget(targetChannel, targetAddr).WithTransit(transitChannel, transitAddr)
+		}
+		if err := c.Connect(ctx); err != nil {
+			t.Fatalf("Connect failed, err: %s", err)
+		}
+		t.Cleanup(func() { c.Close(ctx) })
+		return c
+	}
+
+	marker, err := ipmi.NewSELOEMTimestamped(0xc1, 0x002a7c, []byte{0x01, 0x00, 0x00, 0x30, 0x39})
+	if err != nil {
+		t.Fatalf("NewSELOEMTimestamped failed, err: %s", err)
+	}
+	// the timestamp is replaced by the BMC
+	marker.OEMTimestamped.Timestamp = time.Unix(0x10000000, 0)
+
+	checkAdded := func(c *ipmi.Client, recordID uint16) {
+		t.Helper()
+		res, err := c.GetSELEntry(ctx, 0, recordID)
+		if err != nil {
+			t.Fatalf("GetSELEntry failed, err: %s", err)
+		}
+		sel, err := ipmi.ParseSEL(res.Data)
+		if err != nil {
+			t.Fatalf("ParseSEL failed, err: %s", err)
+		}
+		if sel.OEMTimestamped == nil || sel.OEMTimestamped.OEMDefined != marker.OEMTimestamped.OEMDefined ||
+			time.Since(sel.OEMTimestamped.Timestamp) > time.Minute {
+			t.Errorf("added record = %+v, want the marker timestamped by the BMC", sel.OEMTimestamped)
+		}
+	}
+
+	// the record fits the request of the BMC
+	c := newClient(false)
+	recordID, err := c.AddSELRecord(ctx, marker)
+	if err != nil {
+		t.Fatalf("AddSELRecord failed, err: %s", err)
+	}
+	if commands[ipmi.CommandAddSELEntry.Name] != 1 || commands[ipmi.CommandPartialAddSELEntry.Name] != 0 {
+		t.Errorf("AddSELRecord did not use Add SEL Entry, commands: %v", commands)
+	}
+	checkAdded(c, recordID)
+
+	// the record does not fit the double bridged request, and it is restarted once the reservation is canceled
+	c = newClient(true)
+	for k := range commands {
+		delete(commands, k)
+	}
+	cancelReservation = true
+	recordID, err = c.AddSELRecord(ctx, marker)
+	if err != nil {
+		t.Fatalf("AddSELRecord failed, err: %s", err)
+	}
+	if commands[ipmi.CommandAddSELEntry.Name] != 0 || commands[ipmi.CommandPartialAddSELEntry.Name] < 6 ||
+		commands[ipmi.CommandReserveSEL.Name] != 2 {
+		t.Errorf("AddSELRecord did not restart the partial add, commands: %v", commands)
+	}
+	// the IPMB request data is up to 25 bytes, less 8 bytes for each Send Message and 6 bytes of the Partial Add SEL Entry fields
+	if maxChunk != 3 {
+		t.Errorf("AddSELRecord added the record in parts of up to %d bytes, want 3", maxChunk)
+	}
+	checkAdded(c, recordID)
+	info, err := c.GetSELInfo(ctx)
+	if err != nil {
+		t.Fatalf("GetSELInfo failed, err: %s", err)
+	}
+	if info.Entries != 1 {
+		t.Errorf("target has %d SEL records, want 1", info.Entries)
+	}
+
+	// the complete record is checked when the last part is added
+	res, err := c.ReserveSEL(ctx)
+	if err != nil {
+		t.Fatalf("ReserveSEL failed, err: %s", err)
+	}
+	if _, err := c.PartialAddSELEntry(ctx, res.ReservationID, 0, 0, true, []byte{0, 0, 0xc1}); !hasCompletionCode(err, 0x80) {
+		t.Errorf("PartialAddSELEntry of a short record returned err %v, want completion code 0x80", err)
+	}
+}
+
+func TestClient_AddSELRecordRequestLength(t *testing.T) {
+	ctx := context.Background()
+
+	s := newTestServer(t, func(s *ipmitest.Server) {
+		s.MaxRequestDataSize = 12
+	})
+
+	commands := make(map[string]int)
+	// maxChunk is the longest record data of the Partial Add SEL Entry requests accepted
+	maxChunk := 0
+	countCommands := func(ctx context.Context, request ipmi.Request, response ipmi.Response, next ipmi.ExchangeFunc) error {
+		commands[request.Command().Name]++
+		err := next(ctx, request, response)
+		if req, ok := request.(*ipmi.PartialAddSELEntryRequest); ok && err == nil {
+			maxChunk = max(maxChunk, len(req.RecordData))
+		}
+		return err
+	}
+
+	c := newTestClient(t, s, ipmi.InterfaceLanplus, testPassword).WithInterceptor(countCommands)
+	if err := c.Connect(ctx); err != nil {
+		t.Fatalf("Connect failed, err: %s", err)
+	}
+	defer c.Close(ctx)
+
+	// the record of 16 bytes fits the lanplus request size, but not the 12 bytes accepted by the BMC
+	marker, err := ipmi.NewSELOEMTimestamped(0xc1, 0x002a7c, []byte{0x01, 0x00, 0x00, 0x30, 0x39})
+	if err != nil {
+		t.Fatalf("NewSELOEMTimestamped failed, err: %s", err)
+	}
+
+	recordID, err := c.AddSELRecord(ctx, marker)
+	if err != nil {
+		t.Fatalf("AddSELRecord failed, err: %s", err)
+	}
+	if commands[ipmi.CommandAddSELEntry.Name] != 1 {
+		t.Errorf("AddSELRecord did not try Add SEL Entry first, commands: %v", commands)
+	}
+	// the chunks of 8 bytes are refused, the ones of 4 bytes are accepted
+	if maxChunk != 4 {
+		t.Errorf("AddSELRecord added the record in parts of up to %d bytes, want 4", maxChunk)
+	}
+
+	res, err := c.GetSELEntry(ctx, 0, recordID)
+	if err != nil {
+		t.Fatalf("GetSELEntry failed, err: %s", err)
+	}
+	sel, err := ipmi.ParseSEL(res.Data)
+	if err != nil {
+		t.Fatalf("ParseSEL failed, err: %s", err)
+	}
+	if sel.OEMTimestamped == nil || sel.OEMTimestamped.OEMDefined != marker.OEMTimestamped.OEMDefined {
+		t.Errorf("added record = %+v, want the marker", sel.OEMTimestamped)
+	}
+}
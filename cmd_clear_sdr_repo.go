@@ -0,0 +1,117 @@
+package ipmi
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// 33.16 Clear SDR Repository Command
+type ClearSDRRepoRequest struct {
+	ReservationID        uint16 // LS Byte first
+	GetErasureStatusFlag bool
+}
+
+type ClearSDRRepoResponse struct {
+	ErasureProgressStatus uint8
+}
+
+func (req *ClearSDRRepoRequest) Pack() []byte {
+	var out = make([]byte, 6)
+	packUint16L(req.ReservationID, out, 0)
+	packUint8('C', out, 2) // fixed 'C' char
+	packUint8('L', out, 3) // fixed 'L' char
+	packUint8('R', out, 4) // fixed 'R' char
+	if req.GetErasureStatusFlag {
+		packUint8(0x00, out, 5) //  get erasure status
+	} else {
+		packUint8(0xaa, out, 5) //  initiate erase
+	}
+	return out
+}
+
+func (req *ClearSDRRepoRequest) Command() Command {
+	return CommandClearSDRRepo
+}
+
+func (res *ClearSDRRepoResponse) Unpack(msg []byte) error {
+	if len(msg) < 1 {
+		return ErrUnpackedDataTooShortWith(len(msg), 1)
+	}
+
+	res.ErasureProgressStatus, _, _ = unpackUint8(msg, 0)
+	return nil
+}
+
+func (res *ClearSDRRepoResponse) CompletionCodes() map[uint8]string {
+	// no command-specific cc
+	return map[uint8]string{}
+}
+
+// ErasureCompleted reports whether the erasure of the SDR Repository is completed.
+func (res *ClearSDRRepoResponse) ErasureCompleted() bool {
+	return res.ErasureProgressStatus&0x0f == 0x01
+}
+
+func (res *ClearSDRRepoResponse) Format() string {
+	return fmt.Sprintf("Erasure Completed : %s", formatBool(res.ErasureCompleted(), "yes", "no"))
+}
+
+// ClearSDRRepo initiates the erasure of the SDR Repository, see ClearSDRRepoAndWait.
+func (c *Client) ClearSDRRepo(ctx context.Context, reservationID uint16) (response *ClearSDRRepoResponse, err error) {
+	request := &ClearSDRRepoRequest{
+		ReservationID:        reservationID,
+		GetErasureStatusFlag: false,
+	}
+	response = &ClearSDRRepoResponse{}
+	err = c.Exchange(ctx, request, response)
+	return
+}
+
+// GetSDRRepoErasureStatus returns the progress of the erasure initiated by ClearSDRRepo.
+func (c *Client) GetSDRRepoErasureStatus(ctx context.Context, reservationID uint16) (response *ClearSDRRepoResponse, err error) {
+	request := &ClearSDRRepoRequest{
+		ReservationID:        reservationID,
+		GetErasureStatusFlag: true,
+	}
+	response = &ClearSDRRepoResponse{}
+	err = c.Exchange(ctx, request, response)
+	return
+}
+
+// ClearSDRRepoAndWait reserves the SDR Repository, initiates the erasure, and polls the erasure status
+// every pollInterval until it is completed or ctx is done.
+// The SDR Repository is reserved again if the reservation is canceled while polling.
+func (c *Client) ClearSDRRepoAndWait(ctx context.Context, pollInterval time.Duration) error {
+	reservationID, err := c.reserveSDRRepoID(ctx)
+	if err != nil {
+		return err
+	}
+
+	res, err := c.ClearSDRRepo(ctx, reservationID)
+	if err != nil {
+		return fmt.Errorf("ClearSDRRepo failed, err: %w", err)
+	}
+
+	for !res.ErasureCompleted() {
+		c.Debugf("SDR Repository erasure in progress\n")
+
+		timer := time.NewTimer(pollInterval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return fmt.Errorf("SDR Repository erasure not completed, err: %w", ctx.Err())
+		case <-timer.C:
+		}
+
+		reservationID, err = c.withReservation(ctx, reservationID, c.reserveSDRRepoID, func(reservationID uint16) (err error) {
+			res, err = c.GetSDRRepoErasureStatus(ctx, reservationID)
+			return err
+		})
+		if err != nil {
+			return fmt.Errorf("GetSDRRepoErasureStatus failed, err: %w", err)
+		}
+	}
+
+	return nil
+}
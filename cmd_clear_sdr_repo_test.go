@@ -0,0 +1,49 @@
+package ipmi_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/bougou/go-ipmi"
+)
+
+func TestClient_ClearSDRRepoAndWait(t *testing.T) {
+	s := newTestServer(t)
+
+	commands := make(map[string]int)
+	// cancelReservation makes another reservation before the first poll of the erasure status
+	cancelReservation := true
+	interceptor := func(ctx context.Context, request ipmi.Request, response ipmi.Response, next ipmi.ExchangeFunc) error {
+		commands[request.Command().Name]++
+		if req, ok := request.(*ipmi.ClearSDRRepoRequest); ok && req.GetErasureStatusFlag && cancelReservation {
+			cancelReservation = false
+			if err := next(ctx, &ipmi.ReserveSDRRepoRequest{}, &ipmi.ReserveSDRRepoResponse{}); err != nil {
+				return err
+			}
+		}
+		return next(ctx, request, response)
+	}
+
+	ctx := context.Background()
+	c := newTestClient(t, s, ipmi.InterfaceLanplus, testPassword).WithInterceptor(interceptor)
+	if err := c.Connect(ctx); err != nil {
+		t.Fatalf("Connect failed, err: %s", err)
+	}
+	defer c.Close(ctx)
+
+	if err := c.ClearSDRRepoAndWait(ctx, 10*time.Millisecond); err != nil {
+		t.Fatalf("ClearSDRRepoAndWait failed, err: %s", err)
+	}
+	if commands[ipmi.CommandReserveSDRRepo.Name] != 2 {
+		t.Errorf("ClearSDRRepoAndWait did not reserve the SDR Repository again after the reservation is canceled, commands: %v", commands)
+	}
+
+	info, err := c.GetSDRRepoInfo(ctx)
+	if err != nil {
+		t.Fatalf("GetSDRRepoInfo failed, err: %s", err)
+	}
+	if info.RecordCount != 0 {
+		t.Errorf("GetSDRRepoInfo returned %d records after ClearSDRRepoAndWait, want 0", info.RecordCount)
+	}
+}
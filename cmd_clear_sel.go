@@ -3,6 +3,7 @@ package ipmi
 import (
 	"context"
 	"fmt"
+	"time"
 )
 
 // 31.9 Clear SEL Command
@@ -47,10 +48,16 @@ func (res *ClearSELResponse) CompletionCodes() map[uint8]string {
 	return map[uint8]string{}
 }
 
+// ErasureCompleted reports whether the erasure of the SEL is completed.
+func (res *ClearSELResponse) ErasureCompleted() bool {
+	return res.ErasureProgressStatus&0x0f == 0x01
+}
+
 func (res *ClearSELResponse) Format() string {
-	return fmt.Sprintf("%v", res)
+	return fmt.Sprintf("Erasure Completed : %s", formatBool(res.ErasureCompleted(), "yes", "no"))
 }
 
+// ClearSEL initiates the erasure of the SEL, see ClearSELAndWait.
 func (c *Client) ClearSEL(ctx context.Context, reservationID uint16) (response *ClearSELResponse, err error) {
 	request := &ClearSELRequest{
 		ReservationID:        reservationID,
@@ -60,3 +67,51 @@ func (c *Client) ClearSEL(ctx context.Context, reservationID uint16) (response *
 	err = c.Exchange(ctx, request, response)
 	return
 }
+
+// GetSELErasureStatus returns the progress of the erasure initiated by ClearSEL.
+func (c *Client) GetSELErasureStatus(ctx context.Context, reservationID uint16) (response *ClearSELResponse, err error) {
+	request := &ClearSELRequest{
+		ReservationID:        reservationID,
+		GetErasureStatusFlag: true,
+	}
+	response = &ClearSELResponse{}
+	err = c.Exchange(ctx, request, response)
+	return
+}
+
+// ClearSELAndWait reserves the SEL, initiates the erasure, and polls the erasure status
+// every pollInterval until it is completed or ctx is done.
+// The SEL is reserved again if the reservation is canceled while polling.
+func (c *Client) ClearSELAndWait(ctx context.Context, pollInterval time.Duration) error {
+	reservationID, err := c.reserveSELID(ctx)
+	if err != nil {
+		return err
+	}
+
+	res, err := c.ClearSEL(ctx, reservationID)
+	if err != nil {
+		return fmt.Errorf("ClearSEL failed, err: %w", err)
+	}
+
+	for !res.ErasureCompleted() {
+		c.Debugf("SEL erasure in progress\n")
+
+		timer := time.NewTimer(pollInterval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return fmt.Errorf("SEL erasure not completed, err: %w", ctx.Err())
+		case <-timer.C:
+		}
+
+		reservationID, err = c.withReservation(ctx, reservationID, c.reserveSELID, func(reservationID uint16) (err error) {
+			res, err = c.GetSELErasureStatus(ctx, reservationID)
+			return err
+		})
+		if err != nil {
+			return fmt.Errorf("GetSELErasureStatus failed, err: %w", err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,106 @@
+package ipmi_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/bougou/go-ipmi"
+)
+
+func TestClient_SELMaintenance(t *testing.T) {
+	ctx := context.Background()
+	s := newTestServer(t)
+
+	commands := make(map[string]int)
+	// cancelReservation makes another reservation before the next poll of the erasure status
+	cancelReservation := false
+	countCommands := func(ctx context.Context, request ipmi.Request, response ipmi.Response, next ipmi.ExchangeFunc) error {
+		commands[request.Command().Name]++
+		if req, ok := request.(*ipmi.ClearSELRequest); ok && req.GetErasureStatusFlag && cancelReservation {
+			cancelReservation = false
+			if err := next(ctx, &ipmi.ReserveSELRequest{}, &ipmi.ReserveSELResponse{}); err != nil {
+				return err
+			}
+		}
+		return next(ctx, request, response)
+	}
+	c := newTestClient(t, s, ipmi.InterfaceLanplus, testPassword).WithInterceptor(countCommands)
+	if err := c.Connect(ctx); err != nil {
+		t.Fatalf("Connect failed, err: %s", err)
+	}
+	defer c.Close(ctx)
+
+	if ipmi.FindSELEventPreset("unknown") != nil {
+		t.Errorf("FindSELEventPreset returned a preset for an unknown name")
+	}
+	preset := ipmi.FindSELEventPreset("temperature")
+	if preset == nil {
+		t.Fatalf("FindSELEventPreset(temperature) returned nil")
+	}
+	sel := preset.SEL()
+	// the timestamp is replaced by the BMC
+	sel.Standard.Timestamp = time.Unix(0x10000000, 0)
+	addRes, err := c.AddSELEntry(ctx, sel)
+	if err != nil {
+		t.Fatalf("AddSELEntry failed, err: %s", err)
+	}
+
+	entryRes, err := c.GetSELEntry(ctx, 0, addRes.RecordID)
+	if err != nil {
+		t.Fatalf("GetSELEntry failed, err: %s", err)
+	}
+	added, err := ipmi.ParseSEL(entryRes.Data)
+	if err != nil {
+		t.Fatalf("ParseSEL failed, err: %s", err)
+	}
+	if added.Standard.SensorType != ipmi.SensorTypeTemperature || added.Standard.EventData != preset.EventData {
+		t.Errorf("added record = %+v, want the temperature preset", added.Standard)
+	}
+	if time.Since(added.Standard.Timestamp) > time.Minute {
+		t.Errorf("added record is timestamped %s, want the present time", added.Standard.Timestamp)
+	}
+
+	// each deletion cancels the reservation, so the SEL is reserved again for the second record
+	for k := range commands {
+		delete(commands, k)
+	}
+	if err := c.DeleteSELEntries(ctx, []uint16{0x0001, 0x0002}); err != nil {
+		t.Fatalf("DeleteSELEntries failed, err: %s", err)
+	}
+	if commands[ipmi.CommandReserveSEL.Name] != 2 || commands[ipmi.CommandDeleteSELEntry.Name] != 3 {
+		t.Errorf("DeleteSELEntries did not reserve the SEL again, commands: %v", commands)
+	}
+	if err := c.DeleteSELEntries(ctx, []uint16{0x0001}); !hasCompletionCode(err, ipmi.CompletionCodeRequestedDataNotPresent) {
+		t.Errorf("DeleteSELEntries of a deleted record returned err %v, want completion code 0xcb", err)
+	}
+
+	sels, err := c.GetSELEntries(ctx, 0)
+	if err != nil {
+		t.Fatalf("GetSELEntries failed, err: %s", err)
+	}
+	if len(sels) != 1 || sels[0].RecordID != addRes.RecordID {
+		t.Fatalf("GetSELEntries returned %d records after the deletion, want the added record", len(sels))
+	}
+
+	for k := range commands {
+		delete(commands, k)
+	}
+	cancelReservation = true
+	if err := c.ClearSELAndWait(ctx, 10*time.Millisecond); err != nil {
+		t.Fatalf("ClearSELAndWait failed, err: %s", err)
+	}
+	if commands[ipmi.CommandClearSEL.Name] < 3 {
+		t.Errorf("ClearSELAndWait did not poll the erasure status, commands: %v", commands)
+	}
+	if commands[ipmi.CommandReserveSEL.Name] != 2 {
+		t.Errorf("ClearSELAndWait did not reserve the SEL again after the reservation is canceled, commands: %v", commands)
+	}
+	sels, err = c.GetSELEntries(ctx, 0)
+	if err != nil {
+		t.Fatalf("GetSELEntries failed, err: %s", err)
+	}
+	if len(sels) != 0 {
+		t.Errorf("GetSELEntries returned %d records after ClearSELAndWait, want 0", len(sels))
+	}
+}
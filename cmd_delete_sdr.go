@@ -0,0 +1,54 @@
+package ipmi
+
+import (
+	"context"
+	"fmt"
+)
+
+// 33.15 Delete SDR Command
+type DeleteSDRRequest struct {
+	ReservationID uint16 // LS Byte first
+	RecordID      uint16 // LS Byte first
+}
+
+type DeleteSDRResponse struct {
+	RecordID uint16 // Record ID for deleted record, LS Byte first
+}
+
+func (req *DeleteSDRRequest) Command() Command {
+	return CommandDeleteSDR
+}
+
+func (req *DeleteSDRRequest) Pack() []byte {
+	out := make([]byte, 4)
+	packUint16L(req.ReservationID, out, 0)
+	packUint16L(req.RecordID, out, 2)
+	return out
+}
+
+func (res *DeleteSDRResponse) Unpack(msg []byte) error {
+	if len(msg) < 2 {
+		return ErrUnpackedDataTooShortWith(len(msg), 2)
+	}
+	res.RecordID, _, _ = unpackUint16L(msg, 0)
+	return nil
+}
+
+func (res *DeleteSDRResponse) CompletionCodes() map[uint8]string {
+	// no command-specific cc
+	return map[uint8]string{}
+}
+
+func (res *DeleteSDRResponse) Format() string {
+	return fmt.Sprintf("Record ID : %d (%#02x)", res.RecordID, res.RecordID)
+}
+
+func (c *Client) DeleteSDR(ctx context.Context, recordID uint16, reservationID uint16) (response *DeleteSDRResponse, err error) {
+	request := &DeleteSDRRequest{
+		ReservationID: reservationID,
+		RecordID:      recordID,
+	}
+	response = &DeleteSDRResponse{}
+	err = c.Exchange(ctx, request, response)
+	return
+}
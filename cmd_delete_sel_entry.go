@@ -54,3 +54,29 @@ func (c *Client) DeleteSELEntry(ctx context.Context, recordID uint16, reservatio
 	err = c.Exchange(ctx, request, response)
 	return
 }
+
+// DeleteSELEntries reserves the SEL and deletes the SEL records one by one.
+// The deletion of a record cancels the reservation on most BMCs, so the SEL is reserved again
+// whenever the reservation is canceled. It stops at the first record failed to delete.
+func (c *Client) DeleteSELEntries(ctx context.Context, recordIDs []uint16) error {
+	if len(recordIDs) == 0 {
+		return nil
+	}
+
+	reservationID, err := c.reserveSELID(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, recordID := range recordIDs {
+		reservationID, err = c.withReservation(ctx, reservationID, c.reserveSELID, func(reservationID uint16) error {
+			_, err := c.DeleteSELEntry(ctx, recordID, reservationID)
+			return err
+		})
+		if err != nil {
+			return fmt.Errorf("DeleteSELEntry for record %#04x failed, err: %w", recordID, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,42 @@
+package ipmi
+
+import "context"
+
+// 33.19 Enter SDR Repository Update Mode Command
+type EnterSDRRepoUpdateModeRequest struct {
+	// empty
+}
+
+type EnterSDRRepoUpdateModeResponse struct {
+	// empty
+}
+
+func (req *EnterSDRRepoUpdateModeRequest) Command() Command {
+	return CommandEnterSDRRepoUpdateMode
+}
+
+func (req *EnterSDRRepoUpdateModeRequest) Pack() []byte {
+	return []byte{}
+}
+
+func (res *EnterSDRRepoUpdateModeResponse) Unpack(msg []byte) error {
+	return nil
+}
+
+func (res *EnterSDRRepoUpdateModeResponse) CompletionCodes() map[uint8]string {
+	// no command-specific cc
+	return map[uint8]string{}
+}
+
+func (res *EnterSDRRepoUpdateModeResponse) Format() string {
+	return ""
+}
+
+// EnterSDRRepoUpdateMode puts the modal SDR Repository into the update mode,
+// in which the SDR Repository can be written but the commands other than the SDR Repository commands may be rejected.
+func (c *Client) EnterSDRRepoUpdateMode(ctx context.Context) (response *EnterSDRRepoUpdateModeResponse, err error) {
+	request := &EnterSDRRepoUpdateModeRequest{}
+	response = &EnterSDRRepoUpdateModeResponse{}
+	err = c.Exchange(ctx, request, response)
+	return
+}
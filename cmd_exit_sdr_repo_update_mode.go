@@ -0,0 +1,40 @@
+package ipmi
+
+import "context"
+
+// 33.20 Exit SDR Repository Update Mode Command
+type ExitSDRRepoUpdateModeRequest struct {
+	// empty
+}
+
+type ExitSDRRepoUpdateModeResponse struct {
+	// empty
+}
+
+func (req *ExitSDRRepoUpdateModeRequest) Command() Command {
+	return CommandExitSDRRepoUpdateMode
+}
+
+func (req *ExitSDRRepoUpdateModeRequest) Pack() []byte {
+	return []byte{}
+}
+
+func (res *ExitSDRRepoUpdateModeResponse) Unpack(msg []byte) error {
+	return nil
+}
+
+func (res *ExitSDRRepoUpdateModeResponse) CompletionCodes() map[uint8]string {
+	// no command-specific cc
+	return map[uint8]string{}
+}
+
+func (res *ExitSDRRepoUpdateModeResponse) Format() string {
+	return ""
+}
+
+func (c *Client) ExitSDRRepoUpdateMode(ctx context.Context) (response *ExitSDRRepoUpdateModeResponse, err error) {
+	request := &ExitSDRRepoUpdateModeRequest{}
+	response = &ExitSDRRepoUpdateModeResponse{}
+	err = c.Exchange(ctx, request, response)
+	return
+}
@@ -149,62 +149,88 @@ func (c *Client) getSDR(ctx context.Context, recordID uint16) (response *GetSDRR
 	}, nil
 }
 
-func (c *Client) GetSDRBySensorID(ctx context.Context, sensorNumber uint8) (*SDR, error) {
-	if SensorNumber(sensorNumber) == SensorNumberReserved {
-		return nil, fmt.Errorf("not valid sensorNumber, %#0x is reserved", sensorNumber)
+// walkSDRs parses the records of the SDR Repository in order and calls fn with each of them until fn returns true.
+// The records are read from the SDR cache if it is enabled, see WithSDRCache.
+func (c *Client) walkSDRs(ctx context.Context, fn func(sdr *SDR) (stop bool, err error)) error {
+	if c.sdrCache != nil {
+		records, err := c.getSDRRecords(ctx)
+		if err != nil {
+			return err
+		}
+		for _, record := range records {
+			sdr, err := ParseSDR(record.Data, record.NextRecordID)
+			if err != nil {
+				return fmt.Errorf("ParseSDR failed, err: %w", err)
+			}
+			if stop, err := fn(sdr); stop || err != nil {
+				return err
+			}
+		}
+		return nil
 	}
 
 	var recordID uint16 = 0
 	for {
 		res, err := c.GetSDR(ctx, recordID)
 		if err != nil {
-			return nil, fmt.Errorf("GetSDR failed for recordID (%#02x), err: %w", recordID, err)
+			return fmt.Errorf("GetSDR failed for recordID (%#02x), err: %w", recordID, err)
 		}
 		sdr, err := ParseSDR(res.RecordData, res.NextRecordID)
 		if err != nil {
-			return nil, fmt.Errorf("ParseSDR failed, err: %w", err)
+			return fmt.Errorf("ParseSDR failed, err: %w", err)
 		}
-		if uint8(sdr.SensorNumber()) != sensorNumber {
-			recordID = sdr.NextRecordID
-			if recordID == 0xffff {
-				break
-			}
-			continue
+		if stop, err := fn(sdr); stop || err != nil {
+			return err
 		}
 
-		if err := c.enhanceSDR(ctx, sdr); err != nil {
-			return sdr, fmt.Errorf("enhanceSDR failed, err: %w", err)
+		recordID = sdr.NextRecordID
+		if recordID == 0xffff {
+			return nil
 		}
-		return sdr, nil
 	}
-
-	return nil, fmt.Errorf("not found SDR for sensor id (%#0x)", sensorNumber)
 }
 
-func (c *Client) GetSDRBySensorName(ctx context.Context, sensorName string) (*SDR, error) {
-	var recordID uint16 = 0
-	for {
-		res, err := c.GetSDR(ctx, recordID)
-		if err != nil {
-			return nil, fmt.Errorf("GetSDR failed for recordID (%#02x), err: %w", recordID, err)
-		}
-		sdr, err := ParseSDR(res.RecordData, res.NextRecordID)
-		if err != nil {
-			return nil, fmt.Errorf("ParseSDR failed, err: %w", err)
+// findSDR returns the first SDR matched by match, enhanced with the sensor reading.
+func (c *Client) findSDR(ctx context.Context, match func(sdr *SDR) bool) (*SDR, error) {
+	var found *SDR
+	err := c.walkSDRs(ctx, func(sdr *SDR) (bool, error) {
+		if !match(sdr) {
+			return false, nil
 		}
+		found = sdr
+		return true, nil
+	})
+	if err != nil || found == nil {
+		return nil, err
+	}
 
-		if sdr.SensorName() != sensorName {
-			recordID = sdr.NextRecordID
-			if recordID == 0xffff {
-				break
-			}
-			continue
-		}
+	if err := c.enhanceSDR(ctx, found); err != nil {
+		return found, fmt.Errorf("enhanceSDR failed, err: %w", err)
+	}
+	return found, nil
+}
 
-		if err := c.enhanceSDR(ctx, sdr); err != nil {
-			return sdr, fmt.Errorf("enhanceSDR failed, err: %w", err)
-		}
-		return sdr, nil
+func (c *Client) GetSDRBySensorID(ctx context.Context, sensorNumber uint8) (*SDR, error) {
+	if SensorNumber(sensorNumber) == SensorNumberReserved {
+		return nil, fmt.Errorf("not valid sensorNumber, %#0x is reserved", sensorNumber)
+	}
+
+	sdr, err := c.findSDR(ctx, func(sdr *SDR) bool {
+		return uint8(sdr.SensorNumber()) == sensorNumber
+	})
+	if sdr != nil || err != nil {
+		return sdr, err
+	}
+
+	return nil, fmt.Errorf("not found SDR for sensor id (%#0x)", sensorNumber)
+}
+
+func (c *Client) GetSDRBySensorName(ctx context.Context, sensorName string) (*SDR, error) {
+	sdr, err := c.findSDR(ctx, func(sdr *SDR) bool {
+		return sdr.SensorName() == sensorName
+	})
+	if sdr != nil || err != nil {
+		return sdr, err
 	}
 
 	return nil, fmt.Errorf("not found SDR for sensor name (%s)", sensorName)
@@ -214,29 +240,29 @@ func (c *Client) GetSDRBySensorName(ctx context.Context, sensorName string) (*SD
 // The parameter is a slice of SDRRecordType used as filter.
 // Empty means to get all SDR records.
 func (c *Client) GetSDRs(ctx context.Context, recordTypes ...SDRRecordType) ([]*SDR, error) {
-	var recordID uint16 = 0
 	var out = make([]*SDR, 0)
-	for {
-		sdr, err := c.GetSDREnhanced(ctx, recordID)
-		if err != nil {
-			return nil, fmt.Errorf("GetSDR for recordID (%#0x) failed, err: %w", recordID, err)
-		}
-
-		if len(recordTypes) == 0 {
-			out = append(out, sdr)
-		} else {
+	err := c.walkSDRs(ctx, func(sdr *SDR) (bool, error) {
+		if len(recordTypes) != 0 {
+			matched := false
 			for _, v := range recordTypes {
 				if sdr.RecordHeader.RecordType == v {
-					out = append(out, sdr)
+					matched = true
 					break
 				}
 			}
+			if !matched {
+				return false, nil
+			}
 		}
 
-		recordID = sdr.NextRecordID
-		if recordID == 0xffff {
-			break
+		if err := c.enhanceSDR(ctx, sdr); err != nil {
+			return false, fmt.Errorf("enhanceSDR for recordID (%#0x) failed, err: %w", sdr.RecordHeader.RecordID, err)
 		}
+		out = append(out, sdr)
+		return false, nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	return out, nil
@@ -248,13 +274,7 @@ func (c *Client) GetSDRs(ctx context.Context, recordTypes ...SDRRecordType) ([]*
 func (c *Client) GetSDRsMap(ctx context.Context) (SDRMapBySensorNumber, error) {
 	var out = make(map[GeneratorID]map[SensorNumber]*SDR)
 
-	var recordID uint16 = 0
-	for {
-		sdr, err := c.GetSDREnhanced(ctx, recordID)
-		if err != nil {
-			return nil, fmt.Errorf("GetSDR for recordID (%#0x) failed, err: %w", recordID, err)
-		}
-
+	err := c.walkSDRs(ctx, func(sdr *SDR) (bool, error) {
 		var generatorID GeneratorID
 		var sensorNumber SensorNumber
 
@@ -266,19 +286,22 @@ func (c *Client) GetSDRsMap(ctx context.Context) (SDRMapBySensorNumber, error) {
 		case SDRRecordTypeCompactSensor:
 			generatorID = sdr.Compact.GeneratorID
 			sensorNumber = sdr.Compact.SensorNumber
+		default:
+			return false, nil
 		}
 
-		if recordType == SDRRecordTypeFullSensor || recordType == SDRRecordTypeCompactSensor {
-			if _, ok := out[generatorID]; !ok {
-				out[generatorID] = make(map[SensorNumber]*SDR)
-			}
-			out[generatorID][sensorNumber] = sdr
+		if err := c.enhanceSDR(ctx, sdr); err != nil {
+			return false, fmt.Errorf("enhanceSDR for recordID (%#0x) failed, err: %w", sdr.RecordHeader.RecordID, err)
 		}
 
-		recordID = sdr.NextRecordID
-		if recordID == 0xffff {
-			break
+		if _, ok := out[generatorID]; !ok {
+			out[generatorID] = make(map[SensorNumber]*SDR)
 		}
+		out[generatorID][sensorNumber] = sdr
+		return false, nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	return out, nil
@@ -0,0 +1,53 @@
+package ipmi
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// 33.11 Get SDR Repository Time Command
+type GetSDRRepoTimeRequest struct {
+	// empty
+}
+
+type GetSDRRepoTimeResponse struct {
+	// Present Timestamp clock reading
+	Time time.Time
+}
+
+func (req *GetSDRRepoTimeRequest) Pack() []byte {
+	return []byte{}
+}
+
+func (req *GetSDRRepoTimeRequest) Command() Command {
+	return CommandGetSDRRepoTime
+}
+
+func (res *GetSDRRepoTimeResponse) Unpack(msg []byte) error {
+	if len(msg) < 4 {
+		return ErrUnpackedDataTooShortWith(len(msg), 4)
+	}
+
+	t, _, _ := unpackUint32L(msg, 0)
+	res.Time = parseTimestamp(t)
+	return nil
+}
+
+func (res *GetSDRRepoTimeResponse) CompletionCodes() map[uint8]string {
+	// no command-specific cc
+	return map[uint8]string{}
+}
+
+func (res *GetSDRRepoTimeResponse) Format() string {
+	return fmt.Sprintf("%v", res)
+}
+
+// GetSDRRepoTime returns the time setting from the SDR Repository Device,
+// which is used for the timestamps of the SDR Repository Info.
+func (c *Client) GetSDRRepoTime(ctx context.Context) (response *GetSDRRepoTimeResponse, err error) {
+	request := &GetSDRRepoTimeRequest{}
+	response = &GetSDRRepoTimeResponse{}
+	err = c.Exchange(ctx, request, response)
+	return
+}
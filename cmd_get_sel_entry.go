@@ -78,6 +78,7 @@ func (c *Client) GetSELEntry(ctx context.Context, reservationID uint16, recordID
 
 // GetSELEntries return all SEL records starting from the specified recordID.
 // Pass 0 means retrieve all SEL entries starting from the first record.
+// An empty slice is returned if the SEL is empty.
 func (c *Client) GetSELEntries(ctx context.Context, startRecordID uint16) ([]*SEL, error) {
 	// Todo
 	// Notice, this extra GetSELInfo call is used to make sure the GetSELEntry works properly.
@@ -91,11 +92,17 @@ func (c *Client) GetSELEntries(ctx context.Context, startRecordID uint16) ([]*SE
 	// ff ff
 	//
 	// This extra GetSELInfo can avoid it. (I don't known why!)
-	if _, err := c.GetSELInfo(ctx); err != nil {
+	selInfo, err := c.GetSELInfo(ctx)
+	if err != nil {
 		return nil, fmt.Errorf("GetSELInfo failed, err: %w", err)
 	}
 
 	var out = make([]*SEL, 0)
+	if selInfo.Entries == 0 {
+		// the BMC answers Get SEL Entry with "record not present" if the SEL is empty
+		return out, nil
+	}
+
 	var recordID uint16 = startRecordID
 	for {
 		selEntry, err := c.GetSELEntry(ctx, 0, recordID)
@@ -109,24 +109,26 @@ func (r *GetSensorReadingResponse) CompletionCodes() map[uint8]string {
 	return map[uint8]string{}
 }
 
+// ThresholdStatus returns the most severe threshold status of the present threshold comparison status,
+// the upper thresholds are checked before the lower thresholds.
 func (r *GetSensorReadingResponse) ThresholdStatus() SensorThresholdStatus {
+	if r.Above_UNR {
+		return SensorThresholdStatus_UNR
+	}
 	if r.Above_UCR {
 		return SensorThresholdStatus_UCR
 	}
 	if r.Above_UNC {
-		return SensorThresholdStatus_UCR
+		return SensorThresholdStatus_UNC
 	}
-	if r.Above_UNR {
-		return SensorThresholdStatus_UCR
+	if r.Below_LNR {
+		return SensorThresholdStatus_LNR
 	}
 	if r.Below_LCR {
-		return SensorThresholdStatus_UCR
+		return SensorThresholdStatus_LCR
 	}
 	if r.Below_LNC {
-		return SensorThresholdStatus_UCR
-	}
-	if r.Below_LNR {
-		return SensorThresholdStatus_UCR
+		return SensorThresholdStatus_LNC
 	}
 	return SensorThresholdStatus_OK
 }
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"sync"
 )
 
 type SensorFilterOption func(sensor *Sensor) bool
@@ -52,12 +53,12 @@ func (c *Client) GetSensors(ctx context.Context, filterOptions ...SensorFilterOp
 		return nil, fmt.Errorf("GetSDRs failed, err: %w", err)
 	}
 
-	for _, sdr := range sdrs {
-		sensor, err := c.sdrToSensor(ctx, sdr)
-		if err != nil {
-			return nil, fmt.Errorf("sdrToSensor failed, err: %w", err)
-		}
+	sensors, err := c.sdrsToSensors(ctx, sdrs)
+	if err != nil {
+		return nil, err
+	}
 
+	for _, sensor := range sensors {
 		var choose bool = true
 		for _, filterOption := range filterOptions {
 			if !filterOption(sensor) {
@@ -95,12 +96,12 @@ func (c *Client) GetSensorsAny(ctx context.Context, filterOptions ...SensorFilte
 		return nil, fmt.Errorf("GetSDRs failed, err: %w", err)
 	}
 
-	for _, sdr := range sdrs {
-		sensor, err := c.sdrToSensor(ctx, sdr)
-		if err != nil {
-			return nil, fmt.Errorf("sdrToSensor failed, err: %w", err)
-		}
+	sensors, err := c.sdrsToSensors(ctx, sdrs)
+	if err != nil {
+		return nil, err
+	}
 
+	for _, sensor := range sensors {
 		var choose bool = false
 		for _, filterOption := range filterOptions {
 			if filterOption(sensor) {
@@ -117,6 +118,35 @@ func (c *Client) GetSensorsAny(ctx context.Context, filterOptions ...SensorFilte
 	return out, nil
 }
 
+// sdrsToSensors converts the SDRs to sensors in the same order,
+// the sensors are read concurrently if the client allows requests in flight, see WithMaxInFlight.
+func (c *Client) sdrsToSensors(ctx context.Context, sdrs []*SDR) ([]*Sensor, error) {
+	sensors := make([]*Sensor, len(sdrs))
+	errs := make([]error, len(sdrs))
+
+	sem := make(chan struct{}, c.concurrency())
+	var wg sync.WaitGroup
+	for i, sdr := range sdrs {
+		sem <- struct{}{}
+		wg.Add(1)
+		go func(i int, sdr *SDR) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			sensors[i], errs[i] = c.sdrToSensor(ctx, sdr)
+		}(i, sdr)
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return nil, fmt.Errorf("sdrToSensor failed, err: %w", err)
+		}
+	}
+	return sensors, nil
+}
+
 // GetSensorByID returns the sensor with current reading and status by specified sensor number.
 func (c *Client) GetSensorByID(ctx context.Context, sensorNumber uint8) (*Sensor, error) {
 	sdr, err := c.GetSDRBySensorID(ctx, sensorNumber)
@@ -204,15 +234,7 @@ func (c *Client) sdrToSensor(ctx context.Context, sdr *SDR) (*Sensor, error) {
 		return nil, fmt.Errorf("only support Full or Compact SDR record type, input is %s", sdr.RecordHeader.RecordType)
 	}
 
-	sensorOwner := uint8(sensor.GeneratorID.OwnerID())
-	sensorLUN := uint8(sensor.GeneratorID.LUN())
-	commandContext := &CommandContext{}
-	commandContext.
-		WithResponderAddr(sensorOwner).
-		WithResponderLUN(sensorLUN)
-
-	ctx = WithCommandContext(ctx, commandContext)
-	c.Debug("Set CommandContext:", commandContext)
+	ctx = c.withSensorOwner(ctx, sensor.GeneratorID)
 
 	if err := c.fillSensorReading(ctx, sensor); err != nil {
 		return nil, fmt.Errorf("fillSensorReading failed, err: %w", err)
@@ -244,6 +266,18 @@ func (c *Client) sdrToSensor(ctx context.Context, sdr *SDR) (*Sensor, error) {
 	return sensor, nil
 }
 
+// withSensorOwner returns the context whose requests are sent to the owner of the sensor.
+func (c *Client) withSensorOwner(ctx context.Context, generatorID GeneratorID) context.Context {
+	commandContext := &CommandContext{}
+	commandContext.
+		WithResponderAddr(uint8(generatorID.OwnerID())).
+		WithResponderLUN(uint8(generatorID.LUN())).
+		WithResponderChannel(generatorID.ChannelNumber())
+
+	c.Debug("Set CommandContext:", commandContext)
+	return WithCommandContext(ctx, commandContext)
+}
+
 func (c *Client) fillSensorReading(ctx context.Context, sensor *Sensor) error {
 	c.Debug("try to fill sensor reading for sensor", sensor.Number)
 
@@ -0,0 +1,73 @@
+package ipmi
+
+import (
+	"context"
+	"fmt"
+)
+
+// 33.14 Partial Add SDR Command
+type PartialAddSDRRequest struct {
+	ReservationID uint16 // LS Byte first
+	// RecordID is 0000h for the first part of the record,
+	// and the Record ID returned by the first part for the following parts.
+	RecordID uint16 // LS Byte first
+	// OffsetIntoRecord is the offset of the RecordData in the complete record, starting with the record header.
+	OffsetIntoRecord uint8
+	// LastPart means the RecordData is the last part of the record.
+	LastPart   bool
+	RecordData []byte
+}
+
+type PartialAddSDRResponse struct {
+	RecordID uint16 // Record ID for added record, LS Byte first
+}
+
+func (req *PartialAddSDRRequest) Command() Command {
+	return CommandPartialAddSDR
+}
+
+func (req *PartialAddSDRRequest) Pack() []byte {
+	out := make([]byte, 6+len(req.RecordData))
+	packUint16L(req.ReservationID, out, 0)
+	packUint16L(req.RecordID, out, 2)
+	packUint8(req.OffsetIntoRecord, out, 4)
+	if req.LastPart {
+		packUint8(0x01, out, 5) // last record data being transferred with this request
+	} else {
+		packUint8(0x00, out, 5) // partial add in progress
+	}
+	packBytes(req.RecordData, out, 6)
+	return out
+}
+
+func (res *PartialAddSDRResponse) Unpack(msg []byte) error {
+	if len(msg) < 2 {
+		return ErrUnpackedDataTooShortWith(len(msg), 2)
+	}
+	res.RecordID, _, _ = unpackUint16L(msg, 0)
+	return nil
+}
+
+func (res *PartialAddSDRResponse) CompletionCodes() map[uint8]string {
+	return map[uint8]string{
+		0x80: "record rejected due to length mismatch",
+	}
+}
+
+func (res *PartialAddSDRResponse) Format() string {
+	return fmt.Sprintf("Record ID : %d (%#02x)", res.RecordID, res.RecordID)
+}
+
+// PartialAddSDR adds a part of the SDR record, see AddSDRRecord which splits the record in parts.
+func (c *Client) PartialAddSDR(ctx context.Context, reservationID uint16, recordID uint16, offset uint8, lastPart bool, recordData []byte) (response *PartialAddSDRResponse, err error) {
+	request := &PartialAddSDRRequest{
+		ReservationID:    reservationID,
+		RecordID:         recordID,
+		OffsetIntoRecord: offset,
+		LastPart:         lastPart,
+		RecordData:       recordData,
+	}
+	response = &PartialAddSDRResponse{}
+	err = c.Exchange(ctx, request, response)
+	return
+}
@@ -0,0 +1,74 @@
+package ipmi
+
+import (
+	"context"
+	"fmt"
+)
+
+// 31.7 Partial Add SEL Entry Command
+type PartialAddSELEntryRequest struct {
+	ReservationID uint16 // LS Byte first
+	// RecordID is 0000h for the first part of the record,
+	// and the Record ID returned by the first part for the following parts.
+	RecordID uint16 // LS Byte first
+	// OffsetIntoRecord is the offset of the RecordData in the complete 16 bytes record, starting with the Record ID.
+	OffsetIntoRecord uint8
+	// LastPart means the RecordData is the last part of the record.
+	LastPart   bool
+	RecordData []byte
+}
+
+type PartialAddSELEntryResponse struct {
+	RecordID uint16 // Record ID for added record, LS Byte first
+}
+
+func (req *PartialAddSELEntryRequest) Command() Command {
+	return CommandPartialAddSELEntry
+}
+
+func (req *PartialAddSELEntryRequest) Pack() []byte {
+	out := make([]byte, 6+len(req.RecordData))
+	packUint16L(req.ReservationID, out, 0)
+	packUint16L(req.RecordID, out, 2)
+	packUint8(req.OffsetIntoRecord, out, 4)
+	if req.LastPart {
+		packUint8(0x01, out, 5) // last record data being transferred with this request
+	} else {
+		packUint8(0x00, out, 5) // partial add in progress
+	}
+	packBytes(req.RecordData, out, 6)
+	return out
+}
+
+func (res *PartialAddSELEntryResponse) Unpack(msg []byte) error {
+	if len(msg) < 2 {
+		return ErrUnpackedDataTooShortWith(len(msg), 2)
+	}
+	res.RecordID, _, _ = unpackUint16L(msg, 0)
+	return nil
+}
+
+func (res *PartialAddSELEntryResponse) CompletionCodes() map[uint8]string {
+	return map[uint8]string{
+		0x80: "record rejected due to length mismatch",
+		0x81: "cannot execute command, SEL erase in progress",
+	}
+}
+
+func (res *PartialAddSELEntryResponse) Format() string {
+	return fmt.Sprintf("Record ID : %d (%#02x)", res.RecordID, res.RecordID)
+}
+
+// PartialAddSELEntry adds a part of the SEL record, see AddSELRecord which splits the record in parts.
+func (c *Client) PartialAddSELEntry(ctx context.Context, reservationID uint16, recordID uint16, offset uint8, lastPart bool, recordData []byte) (response *PartialAddSELEntryResponse, err error) {
+	request := &PartialAddSELEntryRequest{
+		ReservationID:    reservationID,
+		RecordID:         recordID,
+		OffsetIntoRecord: offset,
+		LastPart:         lastPart,
+		RecordData:       recordData,
+	}
+	response = &PartialAddSELEntryResponse{}
+	err = c.Exchange(ctx, request, response)
+	return
+}
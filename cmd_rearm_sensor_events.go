@@ -2,6 +2,7 @@ package ipmi
 
 import (
 	"context"
+	"fmt"
 )
 
 // 35.12 Re-arm Sensor Events Command
@@ -61,7 +62,7 @@ func (req *RearmSensorEventsRequest) Pack() []byte {
 		b4 = setOrClearBit5(b4, req.SensorEventFlag.SensorEvent_State_5_Deassert)
 		b4 = setOrClearBit4(b4, req.SensorEventFlag.SensorEvent_State_4_Deassert)
 		b4 = setOrClearBit3(b4, req.SensorEventFlag.SensorEvent_State_3_Deassert)
-		b4 = setOrClearBit2(b4, req.SensorEventFlag.SensorEvent_State_3_Deassert)
+		b4 = setOrClearBit2(b4, req.SensorEventFlag.SensorEvent_State_2_Deassert)
 		b4 = setOrClearBit1(b4, req.SensorEventFlag.SensorEvent_State_1_Deassert)
 		b4 = setOrClearBit0(b4, req.SensorEventFlag.SensorEvent_State_0_Deassert)
 
@@ -124,3 +125,31 @@ func (c *Client) RearmSensorEvents(ctx context.Context, request *RearmSensorEven
 	err = c.Exchange(ctx, request, response)
 	return
 }
+
+// RearmSensorEventsByName re-arms the events of the sensor with the name, so the events are generated again
+// for the states which are still present. If no events are given, all the event status of the sensor is re-armed,
+// otherwise only the events are re-armed, see ParseSensorEventOffsets for the names of the events.
+func (c *Client) RearmSensorEventsByName(ctx context.Context, sensorName string, events ...string) error {
+	sdr, err := c.GetSDRBySensorName(ctx, sensorName)
+	if err != nil {
+		return fmt.Errorf("GetSDRBySensorName failed, err: %w", err)
+	}
+
+	request := &RearmSensorEventsRequest{
+		SensorNumber:        uint8(sdr.SensorNumber()),
+		RearmAllEventStatus: len(events) == 0,
+	}
+	if len(events) != 0 {
+		assertMask, deassertMask, err := sensorEventMasks(sdr, events)
+		if err != nil {
+			return err
+		}
+		request.DiscreteEvents = !sdr.EventReadingType().IsThreshold()
+		request.SensorEventFlag.SetEventOffsets(assertMask, deassertMask)
+	}
+
+	if _, err := c.RearmSensorEvents(c.withSensorOwner(ctx, sdr.GeneratorID()), request); err != nil {
+		return fmt.Errorf("RearmSensorEvents failed, err: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,56 @@
+package ipmi
+
+import (
+	"context"
+	"fmt"
+)
+
+// 33.21 Run Initialization Agent Command
+type RunInitializationAgentRequest struct {
+	// GetStatus means to get the status of the Initialization Agent instead of running it.
+	GetStatus bool
+}
+
+type RunInitializationAgentResponse struct {
+	Completed bool
+}
+
+func (req *RunInitializationAgentRequest) Command() Command {
+	return CommandRunInitializationAgent
+}
+
+func (req *RunInitializationAgentRequest) Pack() []byte {
+	if req.GetStatus {
+		return []byte{0x00} // get last execution status
+	}
+	return []byte{0x01} // run initialization agent
+}
+
+func (res *RunInitializationAgentResponse) Unpack(msg []byte) error {
+	if len(msg) < 1 {
+		return ErrUnpackedDataTooShortWith(len(msg), 1)
+	}
+	b, _, _ := unpackUint8(msg, 0)
+	res.Completed = isBit0Set(b)
+	return nil
+}
+
+func (res *RunInitializationAgentResponse) CompletionCodes() map[uint8]string {
+	// no command-specific cc
+	return map[uint8]string{}
+}
+
+func (res *RunInitializationAgentResponse) Format() string {
+	return fmt.Sprintf("Initialization Agent : %s", formatBool(res.Completed, "completed", "in progress"))
+}
+
+// RunInitializationAgent runs the Initialization Agent, which initializes the sensors and event generation
+// according to the SDR Repository, or gets its status if getStatus is true.
+func (c *Client) RunInitializationAgent(ctx context.Context, getStatus bool) (response *RunInitializationAgentResponse, err error) {
+	request := &RunInitializationAgentRequest{
+		GetStatus: getStatus,
+	}
+	response = &RunInitializationAgentResponse{}
+	err = c.Exchange(ctx, request, response)
+	return
+}
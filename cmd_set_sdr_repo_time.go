@@ -0,0 +1,48 @@
+package ipmi
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// 33.12 Set SDR Repository Time Command
+type SetSDRRepoTimeRequest struct {
+	Time time.Time
+}
+
+type SetSDRRepoTimeResponse struct {
+}
+
+func (req *SetSDRRepoTimeRequest) Pack() []byte {
+	var out = make([]byte, 4)
+	packUint32L(uint32(req.Time.Unix()), out, 0)
+	return out
+}
+
+func (req *SetSDRRepoTimeRequest) Command() Command {
+	return CommandSetSDRRepoTime
+}
+
+func (res *SetSDRRepoTimeResponse) Unpack(msg []byte) error {
+	return nil
+}
+
+func (res *SetSDRRepoTimeResponse) CompletionCodes() map[uint8]string {
+	// no command-specific cc
+	return map[uint8]string{}
+}
+
+func (res *SetSDRRepoTimeResponse) Format() string {
+	return fmt.Sprintf("%v", res)
+}
+
+// SetSDRRepoTime initializes the time setting in the SDR Repository Device.
+func (c *Client) SetSDRRepoTime(ctx context.Context, t time.Time) (response *SetSDRRepoTimeResponse, err error) {
+	request := &SetSDRRepoTimeRequest{
+		Time: t,
+	}
+	response = &SetSDRRepoTimeResponse{}
+	err = c.Exchange(ctx, request, response)
+	return
+}
@@ -2,6 +2,7 @@ package ipmi
 
 import (
 	"context"
+	"fmt"
 )
 
 type SetSensorEventEnableMode uint8
@@ -42,11 +43,13 @@ func (req *SetSensorEventEnableRequest) Pack() []byte {
 
 	var b1 uint8
 	b1 = (uint8(req.Mode) & 0x03) << 4
-	b1 = setOrClearBit7(b1, req.DisableEventMessages)
-	b1 = setOrClearBit6(b1, req.DisableSensorScanning)
+	// [7] - 0b = disable all Event Messages from this sensor
+	// [6] - 0b = disable scanning of events from this sensor
+	b1 = setOrClearBit7(b1, !req.DisableEventMessages)
+	b1 = setOrClearBit6(b1, !req.DisableSensorScanning)
 	out[1] = b1
 
-	if req.DisableEventMessages {
+	if req.Mode == SetSensorEventEnableModeNoChange {
 		return out
 	}
 
@@ -74,7 +77,7 @@ func (req *SetSensorEventEnableRequest) Pack() []byte {
 		b4 = setOrClearBit5(b4, req.SensorEventFlag.SensorEvent_State_5_Deassert)
 		b4 = setOrClearBit4(b4, req.SensorEventFlag.SensorEvent_State_4_Deassert)
 		b4 = setOrClearBit3(b4, req.SensorEventFlag.SensorEvent_State_3_Deassert)
-		b4 = setOrClearBit2(b4, req.SensorEventFlag.SensorEvent_State_3_Deassert)
+		b4 = setOrClearBit2(b4, req.SensorEventFlag.SensorEvent_State_2_Deassert)
 		b4 = setOrClearBit1(b4, req.SensorEventFlag.SensorEvent_State_1_Deassert)
 		b4 = setOrClearBit0(b4, req.SensorEventFlag.SensorEvent_State_0_Deassert)
 
@@ -137,3 +140,86 @@ func (c *Client) SetSensorEventEnable(ctx context.Context, request *SetSensorEve
 	err = c.Exchange(ctx, request, response)
 	return
 }
+
+// SetSensorEventsEnabled enables or disables the events of the sensor with the name, and returns the event enables
+// of the sensor read back after they are set.
+//
+// If no events are given, the event messages of the whole sensor are enabled or disabled,
+// the enables of the individual events are kept. Otherwise the events are enabled or disabled individually,
+// see ParseSensorEventOffsets for the names of the events. Only the assertion and deassertion events
+// the sensor supports according to its SDR are selected, and enabling events also enables the event messages of the sensor.
+func (c *Client) SetSensorEventsEnabled(ctx context.Context, sensorName string, enable bool, events ...string) (*GetSensorEventEnableResponse, error) {
+	sdr, err := c.GetSDRBySensorName(ctx, sensorName)
+	if err != nil {
+		return nil, fmt.Errorf("GetSDRBySensorName failed, err: %w", err)
+	}
+	sensorNumber := uint8(sdr.SensorNumber())
+	ctx = c.withSensorOwner(ctx, sdr.GeneratorID())
+
+	current, err := c.GetSensorEventEnable(ctx, sensorNumber)
+	if err != nil {
+		return nil, fmt.Errorf("GetSensorEventEnable failed, err: %w", err)
+	}
+
+	request := &SetSensorEventEnableRequest{
+		SensorNumber:          sensorNumber,
+		DisableEventMessages:  !enable,
+		DisableSensorScanning: current.SensorScanningDisabled,
+		Mode:                  SetSensorEventEnableModeNoChange,
+	}
+
+	if len(events) != 0 {
+		assertMask, deassertMask, err := sensorEventMasks(sdr, events)
+		if err != nil {
+			return nil, err
+		}
+
+		request.DiscreteEvents = !sdr.EventReadingType().IsThreshold()
+		request.SensorEventFlag.SetEventOffsets(assertMask, deassertMask)
+		if enable {
+			request.Mode = SetSensorEventEnableModeEnable
+		} else {
+			request.Mode = SetSensorEventEnableModeDisable
+			request.DisableEventMessages = current.EventMessagesDisabled
+		}
+	}
+
+	if _, err := c.SetSensorEventEnable(ctx, request); err != nil {
+		return nil, fmt.Errorf("SetSensorEventEnable failed, err: %w", err)
+	}
+
+	res, err := c.GetSensorEventEnable(ctx, sensorNumber)
+	if err != nil {
+		return nil, fmt.Errorf("GetSensorEventEnable failed, err: %w", err)
+	}
+	return res, nil
+}
+
+// sensorEventMasks returns the masks of the assertion and deassertion event offsets of the events,
+// limited to the events supported by the sensor according to its SDR.
+func sensorEventMasks(sdr *SDR, events []string) (assertMask uint16, deassertMask uint16, err error) {
+	var mask *Mask
+	switch sdr.RecordHeader.RecordType {
+	case SDRRecordTypeFullSensor:
+		mask = &sdr.Full.Mask
+	case SDRRecordTypeCompactSensor:
+		mask = &sdr.Compact.Mask
+	default:
+		return 0, 0, fmt.Errorf("the events of sensor %s are not defined by its %s SDR", sdr.SensorName(), sdr.RecordHeader.RecordType)
+	}
+
+	eventReadingType := sdr.EventReadingType()
+	offsets, err := ParseSensorEventOffsets(events, eventReadingType, sdr.SensorType())
+	if err != nil {
+		return 0, 0, err
+	}
+
+	supportedAssert, supportedDeassert := mask.EventOffsets(eventReadingType.IsThreshold())
+	for offset := uint8(0); offset <= 14; offset++ {
+		if offsets&(1<<offset) != 0 && (supportedAssert|supportedDeassert)&(1<<offset) == 0 {
+			return 0, 0, fmt.Errorf("event %s is not supported by sensor %s",
+				SensorEventOffsetName(offset, eventReadingType, sdr.SensorType()), sdr.SensorName())
+		}
+	}
+	return offsets & supportedAssert, offsets & supportedDeassert, nil
+}
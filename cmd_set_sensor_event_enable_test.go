@@ -0,0 +1,87 @@
+package ipmi_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/bougou/go-ipmi"
+	"github.com/bougou/go-ipmi/ipmitest"
+)
+
+func TestClient_SensorEvents(t *testing.T) {
+	ctx := context.Background()
+
+	processor := &ipmi.SDRCompact{
+		GeneratorID:            ipmi.GeneratorBMC,
+		SensorNumber:           0x40,
+		SensorType:             ipmi.SensorTypeProcessor,
+		SensorEventReadingType: ipmi.EventReadingTypeSensorSpecific,
+		IDStringBytes:          []byte("CPU0 Status"),
+	}
+	processor.Mask.Discrete.Assert.State_0 = true
+	processor.Mask.Discrete.Assert.State_1 = true
+	processor.Mask.Discrete.Deassert.State_1 = true
+
+	s := newTestServer(t, func(s *ipmitest.Server) {
+		s.AddSDR(processor.Pack())
+		s.SetSensor(0x40, ipmitest.Sensor{AssertionEvents: 0x03})
+	})
+	c := newTestClient(t, s, ipmi.InterfaceLanplus, testPassword)
+	if err := c.Connect(ctx); err != nil {
+		t.Fatalf("Connect failed, err: %s", err)
+	}
+	defer c.Close(ctx)
+
+	checkEnables := func(res *ipmi.GetSensorEventEnableResponse, messagesDisabled bool, assert uint16, deassert uint16) {
+		t.Helper()
+		gotAssert, gotDeassert := res.SensorEventFlag.EventOffsets(false)
+		if res.EventMessagesDisabled != messagesDisabled || res.SensorScanningDisabled || gotAssert != assert || gotDeassert != deassert {
+			t.Errorf("event enables are messages disabled %v, scanning disabled %v, assert %#04x, deassert %#04x, want %v, false, %#04x, %#04x",
+				res.EventMessagesDisabled, res.SensorScanningDisabled, gotAssert, gotDeassert, messagesDisabled, assert, deassert)
+		}
+	}
+
+	// only the events supported by the SDR are selected
+	res, err := c.SetSensorEventsEnabled(ctx, "CPU0 Status", true, "IERR", "thermal trip")
+	if err != nil {
+		t.Fatalf("SetSensorEventsEnabled failed, err: %s", err)
+	}
+	checkEnables(res, false, 0x03, 0x02)
+
+	res, err = c.SetSensorEventsEnabled(ctx, "CPU0 Status", false, "1")
+	if err != nil {
+		t.Fatalf("SetSensorEventsEnabled failed, err: %s", err)
+	}
+	checkEnables(res, false, 0x01, 0x00)
+
+	// the individual enables are kept when the event messages of the sensor are disabled
+	res, err = c.SetSensorEventsEnabled(ctx, "CPU0 Status", false)
+	if err != nil {
+		t.Fatalf("SetSensorEventsEnabled failed, err: %s", err)
+	}
+	checkEnables(res, true, 0x01, 0x00)
+
+	if _, err := c.SetSensorEventsEnabled(ctx, "CPU0 Status", true, "state5"); err == nil {
+		t.Errorf("SetSensorEventsEnabled of an unsupported event succeeded")
+	}
+
+	checkStatus := func(want uint16) {
+		t.Helper()
+		status, err := c.GetSensorEventStatus(ctx, 0x40)
+		if err != nil {
+			t.Fatalf("GetSensorEventStatus failed, err: %s", err)
+		}
+		if got, _ := status.SensorEventFlag.EventOffsets(false); got != want {
+			t.Errorf("asserted events are %#04x, want %#04x", got, want)
+		}
+	}
+
+	if err := c.RearmSensorEventsByName(ctx, "CPU0 Status", "Thermal Trip"); err != nil {
+		t.Fatalf("RearmSensorEventsByName failed, err: %s", err)
+	}
+	checkStatus(0x01)
+	if err := c.RearmSensorEventsByName(ctx, "CPU0 Status"); err != nil {
+		t.Fatalf("RearmSensorEventsByName failed, err: %s", err)
+	}
+	checkStatus(0x00)
+}
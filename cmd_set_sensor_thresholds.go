@@ -1,6 +1,9 @@
 package ipmi
 
-import "context"
+import (
+	"context"
+	"fmt"
+)
 
 // 35.8 Set Sensor Thresholds Command
 type SetSensorThresholdsRequest struct {
@@ -103,3 +106,84 @@ func (c *Client) SetSensorThresholds(ctx context.Context, request *SetSensorThre
 	err = c.Exchange(ctx, request, response)
 	return
 }
+
+// setThreshold sets the raw value of the threshold and marks it to be set.
+func (req *SetSensorThresholdsRequest) setThreshold(thresholdType SensorThresholdType, raw uint8) {
+	switch thresholdType {
+	case SensorThresholdType_UNR:
+		req.SetUNR, req.UNR_Raw = true, raw
+	case SensorThresholdType_UCR:
+		req.SetUCR, req.UCR_Raw = true, raw
+	case SensorThresholdType_UNC:
+		req.SetUNC, req.UNC_Raw = true, raw
+	case SensorThresholdType_LNR:
+		req.SetLNR, req.LNR_Raw = true, raw
+	case SensorThresholdType_LCR:
+		req.SetLCR, req.LCR_Raw = true, raw
+	case SensorThresholdType_LNC:
+		req.SetLNC, req.LNC_Raw = true, raw
+	}
+}
+
+// SetSensorThresholdsByValue sets the thresholds of the sensor with the name to the values in the units of the sensor,
+// and returns the sensor with the thresholds read back after they are set.
+//
+// Only the thresholds settable according to the Full SDR of the sensor can be set.
+// The values are converted to raw values by Sensor.ConvertThresholdToRaw, so upper thresholds are rounded down
+// and lower thresholds are rounded up. The readable thresholds are read back to confirm they are set to the raw values.
+func (c *Client) SetSensorThresholdsByValue(ctx context.Context, sensorName string, values map[SensorThresholdType]float64) (*Sensor, error) {
+	sdr, err := c.GetSDRBySensorName(ctx, sensorName)
+	if err != nil {
+		return nil, fmt.Errorf("GetSDRBySensorName failed, err: %w", err)
+	}
+	if sdr.RecordHeader.RecordType != SDRRecordTypeFullSensor || !sdr.Full.SensorEventReadingType.IsThreshold() {
+		return nil, fmt.Errorf("sensor %s is not a threshold based sensor with Full SDR", sensorName)
+	}
+
+	settable := make(map[SensorThresholdType]bool)
+	for _, thresholdType := range sdr.Full.Mask.SettableThresholds() {
+		settable[thresholdType] = true
+	}
+	for thresholdType := range values {
+		if !settable[thresholdType] {
+			return nil, fmt.Errorf("threshold %s of sensor %s is not settable, settable thresholds: %v",
+				thresholdType.Abbr(), sensorName, sdr.Full.Mask.SettableThresholds().Strings())
+		}
+	}
+
+	// the reading factors of non-linear sensors are read from the sensor
+	sensor, err := c.sdrToSensor(ctx, sdr)
+	if err != nil {
+		return nil, fmt.Errorf("sdrToSensor failed, err: %w", err)
+	}
+
+	request := &SetSensorThresholdsRequest{
+		SensorNumber: sensor.Number,
+	}
+	raws := make(map[SensorThresholdType]uint8)
+	for thresholdType, value := range values {
+		raw, err := sensor.ConvertThresholdToRaw(thresholdType, value)
+		if err != nil {
+			return nil, fmt.Errorf("convert threshold %s of sensor %s failed, err: %w", thresholdType.Abbr(), sensorName, err)
+		}
+		request.setThreshold(thresholdType, raw)
+		raws[thresholdType] = raw
+	}
+
+	if _, err := c.SetSensorThresholds(c.withSensorOwner(ctx, sensor.GeneratorID), request); err != nil {
+		return nil, fmt.Errorf("SetSensorThresholds failed, err: %w", err)
+	}
+
+	sensor, err = c.sdrToSensor(ctx, sdr)
+	if err != nil {
+		return nil, fmt.Errorf("sdrToSensor failed, err: %w", err)
+	}
+	for thresholdType, raw := range raws {
+		threshold := sensor.SensorThreshold(thresholdType)
+		if threshold.Mask.Readable && threshold.Raw != raw {
+			return sensor, fmt.Errorf("threshold %s of sensor %s is read back as %#02x, not the set value %#02x",
+				thresholdType.Abbr(), sensorName, threshold.Raw, raw)
+		}
+	}
+	return sensor, nil
+}
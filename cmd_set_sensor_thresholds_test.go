@@ -0,0 +1,73 @@
+package ipmi_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/bougou/go-ipmi"
+	"github.com/bougou/go-ipmi/ipmitest"
+)
+
+func TestClient_SetSensorThresholdsByValue(t *testing.T) {
+	ctx := context.Background()
+
+	fan := &ipmi.SDRFull{
+		GeneratorID:            ipmi.GeneratorBMC,
+		SensorNumber:           0x30,
+		SensorType:             ipmi.SensorTypeFan,
+		SensorEventReadingType: ipmi.EventReadingTypeThreshold,
+		SensorCapabilities:     ipmi.SensorCapabilities{ThresholdAccess: 2},
+		SensorUnit:             ipmi.SensorUnit{BaseUnit: ipmi.SensorUnitType_RPM},
+		ReadingFactors:         ipmi.ReadingFactors{M: 50},
+		IDStringBytes:          []byte("FAN1"),
+	}
+	fan.Mask.Threshold.LCR.Readable = true
+	fan.Mask.Threshold.LCR.Settable = true
+	fan.Mask.Threshold.LNC.Readable = true
+	fan.Mask.Threshold.LNC.Settable = true
+	fan.Mask.Threshold.UCR.Readable = true
+
+	s := newTestServer(t, func(s *ipmitest.Server) {
+		s.AddSDR(fan.Pack())
+		s.SetSensor(0x30, ipmitest.Sensor{Reading: 60, ReadableThresholds: 0x13, LNC: 20, LCR: 10, UCR: 200})
+	})
+	c := newTestClient(t, s, ipmi.InterfaceLanplus, testPassword)
+	if err := c.Connect(ctx); err != nil {
+		t.Fatalf("Connect failed, err: %s", err)
+	}
+	defer c.Close(ctx)
+
+	// the lower thresholds are rounded up to the readings in steps of 50 RPM
+	sensor, err := c.SetSensorThresholdsByValue(ctx, "FAN1", map[ipmi.SensorThresholdType]float64{
+		ipmi.SensorThresholdType_LNC: 800,
+		ipmi.SensorThresholdType_LCR: 520,
+	})
+	if err != nil {
+		t.Fatalf("SetSensorThresholdsByValue failed, err: %s", err)
+	}
+	if sensor.Threshold.LNC != 800 || sensor.Threshold.LCR != 550 || sensor.Threshold.UCR != 10000 {
+		t.Errorf("thresholds are LNC %v, LCR %v, UCR %v, want 800, 550, 10000", sensor.Threshold.LNC, sensor.Threshold.LCR, sensor.Threshold.UCR)
+	}
+
+	if _, err := c.SetSensorThresholdsByValue(ctx, "FAN1", map[ipmi.SensorThresholdType]float64{
+		ipmi.SensorThresholdType_UCR: 9000,
+	}); err == nil {
+		t.Errorf("SetSensorThresholdsByValue of the unsettable UCR succeeded")
+	}
+	if _, err := c.SetSensorThresholdsByValue(ctx, "FAN1", map[ipmi.SensorThresholdType]float64{
+		ipmi.SensorThresholdType_LCR: 20000,
+	}); !errors.Is(err, ipmi.ErrReadingOutOfRange) {
+		t.Errorf("SetSensorThresholdsByValue out of the range returned err %v, want ErrReadingOutOfRange", err)
+	}
+
+	// the thresholds are confirmed by reading them back
+	s.Handle(ipmi.CommandSetSensorThresholds, func(req *ipmitest.Request) (ipmi.CompletionCode, []byte) {
+		return ipmi.CompletionCodeNormal, nil
+	})
+	if _, err := c.SetSensorThresholdsByValue(ctx, "FAN1", map[ipmi.SensorThresholdType]float64{
+		ipmi.SensorThresholdType_LCR: 600,
+	}); err == nil {
+		t.Errorf("SetSensorThresholdsByValue succeeded while the BMC ignored the thresholds")
+	}
+}
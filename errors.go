@@ -8,6 +8,8 @@ import (
 var (
 	ErrUnpackedDataTooShort         = errors.New("unpacked data is too short")
 	ErrDCMIGroupExtensionIDMismatch = errors.New("DCMI group extension ID mismatch")
+	ErrReadingOutOfRange            = errors.New("value is out of the reading range of the sensor")
+	ErrSessionInvalid               = errors.New("session is invalid on the BMC")
 )
 
 func ErrUnpackedDataTooShortWith(actual int, expected int) error {
@@ -2,7 +2,7 @@ module github.com/bougou/go-ipmi
 
 replace github.com/bougou/go-ipmi v0.0.0 => ./
 
-go 1.20
+go 1.21
 
 require (
 	github.com/google/uuid v1.1.2
@@ -10,6 +10,7 @@ require (
 	github.com/olekukonko/tablewriter v0.0.5
 	github.com/spf13/cobra v1.3.0
 	golang.org/x/net v0.0.0-20210813160813-60bc85c4be6d
+	golang.org/x/term v0.13.0
 )
 
 require (
@@ -18,4 +19,5 @@ require (
 	github.com/mattn/go-runewidth v0.0.9 // indirect
 	github.com/rogpeppe/go-internal v1.6.1 // indirect
 	github.com/spf13/pflag v1.0.5 // indirect
+	golang.org/x/sys v0.13.0 // indirect
 )
@@ -54,6 +54,10 @@ func debug(header string, object interface{}) {
 }
 
 func (c *Client) Debugf(format string, object ...interface{}) {
+	if c.logger != nil {
+		c.logDebugf(format, object...)
+		return
+	}
 	if !c.debug {
 		return
 	}
@@ -61,6 +65,10 @@ func (c *Client) Debugf(format string, object ...interface{}) {
 }
 
 func (c *Client) Debug(header string, object interface{}) {
+	if c.logger != nil {
+		c.logDebug(header, object)
+		return
+	}
 	if !c.debug {
 		return
 	}
@@ -69,6 +77,10 @@ func (c *Client) Debug(header string, object interface{}) {
 
 // DebugBytes print byte slices with a fixed width of bytes on each line.
 func (c *Client) DebugBytes(header string, data []byte, width int) {
+	if c.logger != nil {
+		c.logDebugBytes(header, data)
+		return
+	}
 	if !c.debug {
 		return
 	}
@@ -76,6 +88,10 @@ func (c *Client) DebugBytes(header string, data []byte, width int) {
 }
 
 func (c *Client) DebugfRed(format string, object ...interface{}) {
+	if c.logger != nil {
+		c.logDebugf(format, object...)
+		return
+	}
 	if !c.debug {
 		return
 	}
@@ -84,6 +100,10 @@ func (c *Client) DebugfRed(format string, object ...interface{}) {
 }
 
 func (c *Client) DebugfGreen(format string, object ...interface{}) {
+	if c.logger != nil {
+		c.logDebugf(format, object...)
+		return
+	}
 	if !c.debug {
 		return
 	}
@@ -92,6 +112,10 @@ func (c *Client) DebugfGreen(format string, object ...interface{}) {
 }
 
 func (c *Client) DebugfYellow(format string, object ...interface{}) {
+	if c.logger != nil {
+		c.logDebugf(format, object...)
+		return
+	}
 	if !c.debug {
 		return
 	}
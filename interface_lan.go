@@ -5,6 +5,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"log/slog"
 	"net"
 	"time"
 )
@@ -136,6 +137,15 @@ func (c *Client) buildRawPayload(ctx context.Context, reqCmd Request) (PayloadTy
 func (c *Client) exchangeLAN(ctx context.Context, request Request, response Response) error {
 	c.Debug(">> Command Request", request)
 
+	bridge := c.bridgeFor(ctx, request)
+	if bridge != nil {
+		ctx = withIPMBBridge(ctx, bridge)
+	}
+
+	if f := c.inFlightFor(request, bridge); f != nil {
+		return c.exchangeInFlight(ctx, f, request, response)
+	}
+
 	rmcp, err := c.BuildRmcpRequest(ctx, request)
 	if err != nil {
 		return fmt.Errorf("build RMCP+ request msg failed, err: %w", err)
@@ -185,7 +195,19 @@ func (c *Client) exchangeLAN(ctx context.Context, request Request, response Resp
 	}
 	c.DebugBytes("recv", recv, 16)
 
-	if err := c.ParseRmcpResponse(ctx, recv, response); err != nil {
+	err = c.ParseRmcpResponse(ctx, recv, response)
+	for i := 0; errors.Is(err, errBridgedResponsePending) && i < bridge.levels(); i++ {
+		// the BMC sends the bridged response in a separate message after the Send Message response
+		c.Debugf("wait for bridged response\n")
+		recv, err = c.udpClient.Receive(ctx)
+		if err != nil {
+			return fmt.Errorf("client udp receive bridged response failed, err: %w", err)
+		}
+		c.DebugBytes("recv", recv, 16)
+
+		err = c.ParseRmcpResponse(ctx, recv, response)
+	}
+	if err != nil {
 		return err
 	}
 
@@ -201,6 +223,21 @@ func (c *Client) exchangeLAN(ctx context.Context, request Request, response Resp
 // 3. Get Session Challenge
 // 4. Activate Session
 func (c *Client) Connect15(ctx context.Context) error {
+	if err := c.login15(ctx); err != nil {
+		return err
+	}
+	c.established = true
+	c.log(ctx, slog.LevelInfo, "session established", c.sessionAttr())
+
+	go func() {
+		c.keepSessionAlive(ctx, DefaultKeepAliveIntervalSec)
+	}()
+
+	return nil
+}
+
+// login15 activates an IPMI v1.5 session.
+func (c *Client) login15(ctx context.Context) error {
 	var (
 		err           error
 		channelNumber uint8 = ChannelNumberSelf
@@ -232,16 +269,27 @@ func (c *Client) Connect15(ctx context.Context) error {
 		return fmt.Errorf("SetSessionPrivilegeLevel to (%s) failed, err: %w", c.maxPrivilegeLevel, err)
 	}
 
+	return nil
+}
+
+// see 13.15 IPMI v2.0/RMCP+ Session Activation
+func (c *Client) Connect20(ctx context.Context) error {
+	if err := c.login20(ctx); err != nil {
+		return err
+	}
+	c.established = true
+	c.log(ctx, slog.LevelInfo, "session established", c.sessionAttr(), slog.Int("cipher_suite", int(c.session.v20.cipherSuiteID)))
+
 	go func() {
 		c.keepSessionAlive(ctx, DefaultKeepAliveIntervalSec)
 	}()
 
 	return nil
-
 }
 
-// see 13.15 IPMI v2.0/RMCP+ Session Activation
-func (c *Client) Connect20(ctx context.Context) error {
+// login20 activates an IPMI v2.0/RMCP+ session, the cipher suite set by WithCipherSuiteID
+// or negotiated by the last login is used if any.
+func (c *Client) login20(ctx context.Context) error {
 	var (
 		err           error
 		channelNumber uint8 = ChannelNumberSelf
@@ -256,11 +304,12 @@ func (c *Client) Connect20(ctx context.Context) error {
 		return fmt.Errorf("cmd: Get Channel Authentication Capabilities failed, err: %w", err)
 	}
 
-	tryCiphers := c.findBestCipherSuites(ctx)
-
+	var tryCiphers []CipherSuiteID
 	if c.session.v20.cipherSuiteID != CipherSuiteIDReserved {
 		// client explicitly specified a cipher suite to use
 		tryCiphers = []CipherSuiteID{c.session.v20.cipherSuiteID}
+	} else {
+		tryCiphers = c.findBestCipherSuites(ctx)
 	}
 
 	c.DebugfGreen("\n\ntry ciphers (%v)\n", tryCiphers)
@@ -306,10 +355,6 @@ func (c *Client) Connect20(ctx context.Context) error {
 		return fmt.Errorf("SetSessionPrivilegeLevel to (%s) failed, err: %w", c.maxPrivilegeLevel, err)
 	}
 
-	go func() {
-		c.keepSessionAlive(ctx, DefaultKeepAliveIntervalSec)
-	}()
-
 	return nil
 }
 
@@ -360,6 +405,8 @@ func (c *Client) closeLAN(ctx context.Context) error {
 		return fmt.Errorf("CloseSession failed, err: %w", err)
 	}
 
+	c.stopInFlight()
+
 	if err := c.udpClient.Close(); err != nil {
 		return fmt.Errorf("close udp connection failed, err: %w", err)
 	}
@@ -379,6 +426,7 @@ func (c *Client) keepSessionAlive(ctx context.Context, intervalSec int) {
 		case <-ticker.C:
 			if _, err := c.GetCurrentSessionInfo(ctx); err != nil {
 				c.DebugfRed("keepSessionAlive failed, GetCurrentSessionInfo failed, err: %w", err)
+				c.log(ctx, slog.LevelWarn, "keepalive failed", c.sessionAttr(), slog.String("err", err.Error()))
 			}
 		case <-c.closedCh:
 			c.Debugf("got close signal, keepSessionAlive stopped")
@@ -5,21 +5,27 @@ import (
 	"fmt"
 	"math/rand"
 	"os"
+	"sync"
+	"time"
 	"unsafe"
 
 	"github.com/bougou/go-ipmi/open"
 )
 
 type openipmi struct {
-	myAddr         uint8
-	msgID          int64
-	targetAddr     uint8
-	targetChannel  uint8
-	targetIPMBAddr uint8
-	transitAddr    uint8
-	transitLUN     uint8
+	// the IPMB address of the BMC the driver talks to
+	myAddr uint8
 
 	file *os.File // /dev/ipmi0
+
+	// sendRequest and receiveMessage pass the requests to the driver and read its messages,
+	// they are open.SendRequest and open.ReceiveMessage except in tests.
+	sendRequest    func(file *os.File, req *open.IPMI_REQ) error
+	receiveMessage func(file *os.File) (*open.Message, error)
+
+	mu sync.Mutex
+	// receiver reads the ipmi dev file after Events or RegisterCommandHandler is called
+	receiver *openReceiver
 }
 
 // ConnectOpen try to initialize the client by open the device of linux ipmi driver.
@@ -67,6 +73,8 @@ func (c *Client) ConnectOpen(ctx context.Context, devnum int32) error {
 
 // closeOpen closes the ipmi dev file.
 func (c *Client) closeOpen(ctx context.Context) error {
+	c.stopOpenReceiver()
+
 	if err := c.openipmi.file.Close(); err != nil {
 		return fmt.Errorf("close open file failed, err: %w", err)
 	}
@@ -74,13 +82,6 @@ func (c *Client) closeOpen(ctx context.Context) error {
 }
 
 func (c *Client) exchangeOpen(ctx context.Context, request Request, response Response) error {
-	if c.openipmi.targetAddr != 0 && c.openipmi.targetAddr != c.openipmi.myAddr {
-
-	} else {
-		// otherwise use system interface
-		c.Debugf("\nSending request [%s] (%#02x) to System Interface\n", request.Command().Name, request.Command().ID)
-	}
-
 	recv, err := c.openSendRequest(ctx, request)
 	if err != nil {
 		return fmt.Errorf("openSendRequest failed, err: %w", err)
@@ -95,6 +96,8 @@ func (c *Client) exchangeOpen(ctx context.Context, request Request, response Res
 	}
 
 	ccode := recv[0]
+	setReceived(ctx, ccode, recv[1:])
+
 	if ccode != 0x00 {
 		return &ResponseError{
 			completionCode: CompletionCode(ccode),
@@ -118,53 +121,186 @@ func (c *Client) exchangeOpen(ctx context.Context, request Request, response Res
 	return nil
 }
 
-func (c *Client) openSendRequest(ctx context.Context, request Request) ([]byte, error) {
-
-	var dataPtr *byte
-
-	cmdData := request.Pack()
-	c.DebugBytes("cmdData", cmdData, 16)
-	if len(cmdData) > 0 {
-		dataPtr = &cmdData[0]
+// openBridge returns how the request is bridged to a controller on IPMB,
+// nil means the request is sent to the BMC on the system interface.
+//
+// Besides the target set by WithTarget or the CommandContext, a responder address
+// of the CommandContext other than the BMC (like the owner of a sensor) is also reached on IPMB.
+func (c *Client) openBridge(ctx context.Context, request Request) *ipmbBridge {
+	if bridge := c.bridgeFor(ctx, request); bridge != nil && bridge.targetAddr != c.openipmi.myAddr {
+		return bridge
 	}
 
-	c.DebugBytes("cmd data", cmdData, 16)
-
-	msg := &open.IPMI_MSG{
-		NetFn:   uint8(request.Command().NetFn),
-		Cmd:     uint8(request.Command().ID),
-		Data:    dataPtr,
-		DataLen: uint16(len(cmdData)),
+	commandContext := GetCommandContext(ctx)
+	if commandContext == nil || commandContext.responderAddr == nil {
+		return nil
+	}
+	responderAddr := *commandContext.responderAddr
+	if responderAddr == c.openipmi.myAddr || responderAddr&0x01 == 0x01 {
+		// the BMC itself, or a software ID whose sensors are served by the BMC
+		return nil
 	}
 
-	addr := &open.IPMI_SYSTEM_INTERFACE_ADDR{
-		AddrType: open.IPMI_SYSTEM_INTERFACE_ADDR_TYPE,
-		Channel:  open.IPMI_BMC_CHANNEL,
-		LUN:      0,
+	bridge := &ipmbBridge{
+		targetAddr: responderAddr,
+	}
+	if commandContext.responderChannel != nil {
+		bridge.targetChannel = *commandContext.responderChannel
 	}
+	return bridge
+}
 
+func (c *Client) openSendRequest(ctx context.Context, request Request) ([]byte, error) {
+	var lun uint8
 	commandContext := GetCommandContext(ctx)
 	if commandContext != nil {
 		c.Debug("Got CommandContext:", commandContext)
 
-		if commandContext.responderAddr != nil {
-		}
 		if commandContext.responderLUN != nil {
-			addr.LUN = *commandContext.responderLUN
+			lun = *commandContext.responderLUN
 		}
-		if commandContext.requesterAddr != nil {
+	}
+
+	netFn := request.Command().NetFn
+	cmd := request.Command().ID
+	cmdData := request.Pack()
+	c.DebugBytes("cmd data", cmdData, 16)
+
+	msgID := rand.Int63()
+
+	var addr *open.IPMI_ADDR
+	bridge := c.openBridge(ctx, request)
+	switch {
+	case bridge == nil:
+		c.Debugf("\nSending request [%s] (%#02x) to System Interface\n", request.Command().Name, request.Command().ID)
+		addr = open.NewSystemInterfaceAddr(lun)
+
+	case !bridge.double():
+		c.Debugf("\nSending request [%s] (%#02x) to IPMB addr %#02x on channel %#02x\n",
+			request.Command().Name, request.Command().ID, bridge.targetAddr, bridge.targetChannel)
+		addr = open.NewIPMBAddr(bridge.targetChannel, bridge.targetAddr, lun)
+
+	default:
+		// The driver bridges the request to the transit controller,
+		// which is asked to forward the wrapped request to the target by Send Message.
+		c.Debugf("\nSending request [%s] (%#02x) to IPMB addr %#02x on channel %#02x via transit addr %#02x on channel %#02x\n",
+			request.Command().Name, request.Command().ID, bridge.targetAddr, bridge.targetChannel, bridge.transitAddr, bridge.transitChannel)
+		addr = open.NewIPMBAddr(bridge.transitChannel, bridge.transitAddr, 0)
+
+		ipmiReq := &IPMIRequest{
+			ResponderAddr:     bridge.targetAddr,
+			NetFn:             netFn,
+			ResponderLUN:      lun,
+			RequesterAddr:     bridge.transitAddr,
+			RequesterSequence: uint8(msgID) & IPMIRequesterSequenceMax,
+			Command:           cmd,
+			CommandData:       cmdData,
 		}
-		if commandContext.requesterLUN != nil {
+		ipmiReq.ComputeChecksum()
+		c.Debug(">>>> Bridged IPMI Request", ipmiReq)
+
+		sendMessage := &SendMessageRequest{
+			TrackMask:     0x01, // Track Request
+			ChannelNumber: bridge.targetChannel,
+			MessageData:   ipmiReq.Pack(),
 		}
+		netFn = CommandSendMessage.NetFn
+		cmd = CommandSendMessage.ID
+		cmdData = sendMessage.Pack()
+	}
+
+	var dataPtr *byte
+	if len(cmdData) > 0 {
+		dataPtr = &cmdData[0]
+	}
+
+	msg := &open.IPMI_MSG{
+		NetFn:   uint8(netFn),
+		Cmd:     cmd,
+		Data:    dataPtr,
+		DataLen: uint16(len(cmdData)),
 	}
 
 	req := &open.IPMI_REQ{
 		Addr:    addr,
-		AddrLen: int(unsafe.Sizeof(addr)),
-		MsgID:   rand.Int63(),
+		AddrLen: addr.Len(),
+		MsgID:   msgID,
 		Msg:     *msg,
 	}
 
 	c.Debug("IPMI_REQ", req)
-	return open.SendCommand(c.openipmi.file, req, c.timeout)
+	c.openipmi.mu.Lock()
+	receiver := c.openipmi.receiver
+	c.openipmi.mu.Unlock()
+
+	var recv []byte
+	var err error
+	if receiver != nil {
+		recv, err = receiver.exchange(ctx, req, c.timeout)
+	} else {
+		recv, err = c.openSendCommand(req, c.timeout)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if bridge != nil && bridge.double() {
+		return c.openUnwrapBridged(recv)
+	}
+	return recv, nil
+}
+
+// openSendCommand sends the request and waits for its response like open.SendCommand,
+// the events and commands received while waiting are dropped.
+//
+// The read deadline set here is left on the file, it is cleared when the receiver is started.
+func (c *Client) openSendCommand(req *open.IPMI_REQ, timeout time.Duration) ([]byte, error) {
+	if timeout == 0 {
+		timeout = open.IPMI_FILE_READ_TIMEOUT
+	}
+
+	if err := c.openipmi.sendRequest(c.openipmi.file, req); err != nil {
+		return nil, err
+	}
+
+	if err := c.openipmi.file.SetReadDeadline(time.Now().Add(timeout)); err != nil {
+		return nil, fmt.Errorf("failed to set read deadline on file: %s", err)
+	}
+
+	for {
+		msg, err := c.openipmi.receiveMessage(c.openipmi.file)
+		if err != nil {
+			return nil, err
+		}
+
+		if msg.RecvType != open.IPMI_RESPONSE_RECV_TYPE || msg.MsgID != req.MsgID {
+			continue
+		}
+		// msg.Data[0] is completion code.
+		return msg.Data, nil
+	}
+}
+
+// openUnwrapBridged returns the completion code and the data of the bridged response,
+// which is embedded in the Send Message response returned by the transit controller.
+func (c *Client) openUnwrapBridged(recv []byte) ([]byte, error) {
+	if len(recv) < 1 || recv[0] != 0x00 {
+		// the completion code of Send Message is checked by the caller
+		return recv, nil
+	}
+	if len(recv) == 1 {
+		return nil, fmt.Errorf("no bridged response in the Send Message response")
+	}
+
+	ipmiRes := &IPMIResponse{}
+	if err := ipmiRes.Unpack(recv[1:]); err != nil {
+		return nil, fmt.Errorf("unpack bridged ipmiRes failed, err: %w", err)
+	}
+	c.Debug("<<<< Bridged IPMI Response", ipmiRes)
+
+	if !ipmiRes.ValidChecksum() {
+		return nil, fmt.Errorf("invalid checksum of bridged response")
+	}
+
+	return append([]byte{ipmiRes.CompletionCode}, ipmiRes.Data...), nil
 }
@@ -55,6 +55,7 @@ func (c *Client) exchangeTool(ctx context.Context, request Request, response Res
 				if err != nil {
 					return fmt.Errorf("CompletionCode parse failed, err: %w", err)
 				}
+				setReceived(ctx, uint8(code), nil)
 				return &ResponseError{
 					completionCode: CompletionCode(uint8(code)),
 					description:    fmt.Sprintf("Raw command failed, err: %s", string(submatches[6])),
@@ -69,6 +70,8 @@ func (c *Client) exchangeTool(ctx context.Context, request Request, response Res
 	if err != nil {
 		return fmt.Errorf("decode response failed, err: %w", err)
 	}
+	setReceived(ctx, uint8(CompletionCodeNormal), resp)
+
 	if err := response.Unpack(resp); err != nil {
 		return fmt.Errorf("unpack response failed, err: %w", err)
 	}
@@ -0,0 +1,68 @@
+package ipmitest
+
+import (
+	"github.com/bougou/go-ipmi"
+)
+
+type controllerKey struct {
+	channel uint8
+	addr    uint8
+}
+
+// AddController adds a management controller at the slave address addr on the channel behind the Server.
+// Requests bridged to it with the Send Message command are answered by the returned Server.
+//
+// The returned Server is never started, configure its Device and fixtures the same way as the Server,
+// and call AddController on it to add a controller reached by double bridging.
+func (s *Server) AddController(channel uint8, addr uint8) *Server {
+	ctrl := NewServer("", "")
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.controllers[controllerKey{channel: channel, addr: addr}] = ctrl
+	return ctrl
+}
+
+// see 22.7 Send Message Command
+func (s *Server) sendMessage(sess *session, req *Request) (ipmi.CompletionCode, []byte) {
+	if len(req.Data) < 8 {
+		return ipmi.CompletionCodeRequestDataLengthInvalid, nil
+	}
+
+	channel := req.Data[0] & 0x0f
+	if tracking := req.Data[0] >> 6; tracking != 0x01 {
+		// only Track Request is supported
+		return ipmi.CompletionCodeRequestDataFieldInvalid, nil
+	}
+
+	ipmiReq := &ipmi.IPMIRequest{}
+	if err := ipmiReq.Unpack(req.Data[1:]); err != nil || !ipmiReq.ValidChecksum() {
+		return ipmi.CompletionCodeRequestDataFieldInvalid, nil
+	}
+
+	ctrl := s.controllers[controllerKey{channel: channel, addr: ipmiReq.ResponderAddr}]
+	if ctrl == nil {
+		// no controller acknowledges the slave address
+		return 0x83, nil
+	}
+	embedded := ctrl.handleBridged(ipmiReq)
+
+	if s.BridgeSeparateResponse {
+		s.deferred = append(s.deferred, packResponse(req, ipmi.CompletionCodeNormal, embedded))
+		return ipmi.CompletionCodeNormal, nil
+	}
+	return ipmi.CompletionCodeNormal, embedded
+}
+
+// handleBridged answers the request bridged to the controller and returns the packed IPMI response message.
+func (s *Server) handleBridged(ipmiReq *ipmi.IPMIRequest) []byte {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	req := requestOf(ipmiReq)
+
+	// bridged requests are not bound to a session of the controller
+	cc, data := s.dispatch(&session{}, req)
+	return packResponse(req, cc, data)
+}
@@ -0,0 +1,192 @@
+package ipmitest
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/md5"
+	"crypto/rand"
+	"crypto/rc4"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"hash"
+
+	"github.com/bougou/go-ipmi"
+)
+
+// authHMAC computes the HMAC of the RAKP authentication algorithm,
+// it returns empty bytes for RAKP-none.
+func authHMAC(authAlg ipmi.AuthAlg, key []byte, data []byte) []byte {
+	var h func() hash.Hash
+	switch authAlg {
+	case ipmi.AuthAlgRAKP_HMAC_SHA1:
+		h = sha1.New
+	case ipmi.AuthAlgRAKP_HMAC_MD5:
+		h = md5.New
+	case ipmi.AuthAlgRAKP_HMAC_SHA256:
+		h = sha256.New
+	default:
+		return []byte{}
+	}
+
+	mac := hmac.New(h, key)
+	mac.Write(data)
+	return mac.Sum(nil)
+}
+
+// integrityAuthCode computes the AuthCode field of the session trailer.
+// see 13.28.4 Integrity Algorithms
+func (s *Server) integrityAuthCode(sess *session, input []byte) []byte {
+	var h func() hash.Hash
+	var size int
+	switch sess.integrityAlg {
+	case ipmi.IntegrityAlg_HMAC_SHA1_96:
+		h, size = sha1.New, 12
+	case ipmi.IntegrityAlg_HMAC_MD5_128:
+		h, size = md5.New, 16
+	case ipmi.IntegrityAlg_HMAC_SHA256_128:
+		h, size = sha256.New, 16
+	case ipmi.IntegrityAlg_MD5_128:
+		// uses the same password bytes as ipmi.Client
+		data := []byte(s.Password)
+		data = append(data, input...)
+		data = append(data, s.Password...)
+		sum := md5.Sum(data)
+		return sum[:]
+	default:
+		return []byte{}
+	}
+
+	mac := hmac.New(h, sess.k1)
+	mac.Write(input)
+	return mac.Sum(nil)[:size]
+}
+
+// encryptPayload returns the confidentiality header followed by the encrypted payload.
+// see 13.29 AES-CBC-128 Encrypted Payload Format, 13.30 xRC4-Encrypted Payload Format
+func encryptPayload(sess *session, payload []byte) ([]byte, error) {
+	switch sess.cryptAlg {
+	case ipmi.CryptAlg_None:
+		return payload, nil
+
+	case ipmi.CryptAlg_AES_CBC_128:
+		padLength := 0
+		if mod := (len(payload) + 1) % aes.BlockSize; mod > 0 {
+			padLength = aes.BlockSize - mod
+		}
+		data := append([]byte{}, payload...)
+		for i := 0; i < padLength; i++ {
+			data = append(data, uint8(i+1))
+		}
+		data = append(data, uint8(padLength))
+
+		block, err := aes.NewCipher(sess.k2[:16])
+		if err != nil {
+			return nil, fmt.Errorf("NewCipher failed, err: %w", err)
+		}
+		iv := randomBytes(aes.BlockSize)
+		out := make([]byte, len(iv)+len(data))
+		copy(out, iv)
+		cipher.NewCBCEncrypter(block, iv).CryptBlocks(out[len(iv):], data)
+		return out, nil
+
+	case ipmi.CryptAlg_xRC4_128, ipmi.CryptAlg_xRC4_40:
+		// ipmi.Client takes the IV from every received packet, so each packet
+		// is sent as if it were the first one, with data offset 0.
+		c, err := rc4.NewCipher(rc4Key(sess, sess.rc4EncryptIV[:]))
+		if err != nil {
+			return nil, fmt.Errorf("NewCipher failed, err: %w", err)
+		}
+		out := make([]byte, 20+len(payload))
+		copy(out[4:], sess.rc4EncryptIV[:])
+		c.XORKeyStream(out[20:], payload)
+		return out, nil
+	}
+
+	return nil, fmt.Errorf("not supported encryption algorithm %#02x", uint8(sess.cryptAlg))
+}
+
+// decryptPayload returns the clear payload with the confidentiality header and trailer removed.
+func decryptPayload(sess *session, data []byte) ([]byte, error) {
+	switch sess.cryptAlg {
+	case ipmi.CryptAlg_None:
+		return data, nil
+
+	case ipmi.CryptAlg_AES_CBC_128:
+		if len(data) < 2*aes.BlockSize || len(data)%aes.BlockSize != 0 {
+			return nil, fmt.Errorf("invalid encrypted payload length %d", len(data))
+		}
+		block, err := aes.NewCipher(sess.k2[:16])
+		if err != nil {
+			return nil, fmt.Errorf("NewCipher failed, err: %w", err)
+		}
+		d := make([]byte, len(data)-aes.BlockSize)
+		cipher.NewCBCDecrypter(block, data[:aes.BlockSize]).CryptBlocks(d, data[aes.BlockSize:])
+
+		padLength := int(d[len(d)-1])
+		if padLength >= len(d) {
+			return nil, fmt.Errorf("invalid confidentiality pad length %d", padLength)
+		}
+		for i := 0; i < padLength; i++ {
+			if d[len(d)-1-padLength+i] != uint8(i+1) {
+				return nil, fmt.Errorf("invalid confidentiality pad")
+			}
+		}
+		return d[:len(d)-1-padLength], nil
+
+	case ipmi.CryptAlg_xRC4_128, ipmi.CryptAlg_xRC4_40:
+		if len(data) < 4 {
+			return nil, fmt.Errorf("invalid encrypted payload length %d", len(data))
+		}
+		cipherText := data[4:]
+		if binary.BigEndian.Uint32(data[0:4]) == 0 {
+			// the first packet of the session carries the IV
+			if len(data) < 20 {
+				return nil, fmt.Errorf("invalid encrypted payload length %d", len(data))
+			}
+			copy(sess.rc4DecryptIV[:], data[4:20])
+			cipherText = data[20:]
+		}
+
+		c, err := rc4.NewCipher(rc4Key(sess, sess.rc4DecryptIV[:]))
+		if err != nil {
+			return nil, fmt.Errorf("NewCipher failed, err: %w", err)
+		}
+		out := make([]byte, len(cipherText))
+		c.XORKeyStream(out, cipherText)
+		return out, nil
+	}
+
+	return nil, fmt.Errorf("not supported encryption algorithm %#02x", uint8(sess.cryptAlg))
+}
+
+// rc4Key returns Krc, or the most significant forty bits of it for xRC4-40.
+func rc4Key(sess *session, iv []byte) []byte {
+	input := append([]byte{}, sess.k2...)
+	input = append(input, iv...)
+	keyRC := md5.Sum(input)
+	if sess.cryptAlg == ipmi.CryptAlg_xRC4_40 {
+		return keyRC[:5]
+	}
+	return keyRC[:]
+}
+
+func padBytes(s string, width int) []byte {
+	out := make([]byte, width)
+	copy(out, s)
+	return out
+}
+
+func randomBytes(n int) []byte {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+	return b
+}
+
+func randomUint32() uint32 {
+	return binary.LittleEndian.Uint32(randomBytes(4))
+}
@@ -0,0 +1,232 @@
+package ipmitest
+
+import (
+	"encoding/binary"
+	"time"
+)
+
+// DeviceInfo is returned by the Get Device ID command.
+type DeviceInfo struct {
+	DeviceID          uint8
+	DeviceRevision    uint8
+	FirmwareMajor     uint8
+	FirmwareMinor     uint8
+	ProvideDeviceSDRs bool
+
+	// AdditionalDeviceSupport is the bit mask of byte 6 of the Get Device ID response,
+	// e.g. bit 3 FRU Inventory Device, bit 2 SEL Device, bit 1 SDR Repository Device, bit 0 Sensor Device.
+	AdditionalDeviceSupport uint8
+
+	ManufacturerID uint32
+	ProductID      uint16
+}
+
+// DefaultDeviceInfo describes an IPMI 2.0 BMC supporting chassis, FRU inventory, SEL, SDR repository and sensors.
+var DefaultDeviceInfo = DeviceInfo{
+	DeviceID:                0x20,
+	DeviceRevision:          0x01,
+	FirmwareMajor:           1,
+	FirmwareMinor:           0,
+	AdditionalDeviceSupport: 0x8f,
+}
+
+// Pack returns the response data of the Get Device ID command.
+func (d DeviceInfo) Pack() []byte {
+	out := make([]byte, 11)
+	out[0] = d.DeviceID
+	out[1] = d.DeviceRevision & 0x0f
+	if d.ProvideDeviceSDRs {
+		out[1] |= 0x80
+	}
+	out[2] = d.FirmwareMajor & 0x7f
+	out[3] = (d.FirmwareMinor/10)<<4 | d.FirmwareMinor%10 // BCD encoded
+	out[4] = 0x02                                         // IPMI 2.0, BCD encoded
+	out[5] = d.AdditionalDeviceSupport
+	out[6] = uint8(d.ManufacturerID)
+	out[7] = uint8(d.ManufacturerID >> 8)
+	out[8] = uint8(d.ManufacturerID >> 16)
+	binary.LittleEndian.PutUint16(out[9:], d.ProductID)
+	return out
+}
+
+// Sensor holds the state returned by the sensor commands for one sensor number.
+type Sensor struct {
+	Reading               uint8
+	ReadingUnavailable    bool
+	ScanningDisabled      bool
+	EventMessagesDisabled bool
+
+	// States is the threshold comparison status for threshold based sensors,
+	// or the asserted states for discrete sensors, bit N is state N.
+	States uint16
+
+	// ReadableThresholds is the readable threshold mask,
+	// bit 5 UNR, bit 4 UCR, bit 3 UNC, bit 2 LNR, bit 1 LCR, bit 0 LNC.
+	ReadableThresholds uint8
+	LNC, LCR, LNR      uint8
+	UNC, UCR, UNR      uint8
+
+	PositiveHysteresis uint8
+	NegativeHysteresis uint8
+
+	AssertionEvents   uint16
+	DeassertionEvents uint16
+
+	// AssertionEnables and DeassertionEnables are the enabled assertion and deassertion events,
+	// bit N is the event offset N.
+	AssertionEnables   uint16
+	DeassertionEnables uint16
+}
+
+type record struct {
+	id   uint16
+	data []byte
+}
+
+// repo holds the records of the SDR Repository or the System Event Log.
+type repo struct {
+	records       []record
+	nextID        uint16
+	reservationID uint16
+	addTime       uint32
+	eraseTime     uint32
+
+	// partial is the record being added by Partial Add SDR
+	partial *record
+	// erasing is set after the erasure is initiated, until the erasure status is got
+	erasing bool
+
+	// clockDrift is how far the clock of the repository is ahead of the host clock
+	clockDrift time.Duration
+}
+
+// find returns the index of the record, 0x0000 means the first record and
+// 0xffff means the last record.
+func find(records []record, id uint16) int {
+	if len(records) == 0 {
+		return -1
+	}
+	switch id {
+	case 0x0000:
+		return 0
+	case 0xffff:
+		return len(records) - 1
+	}
+	for i, r := range records {
+		if r.id == id {
+			return i
+		}
+	}
+	return -1
+}
+
+// nextRecordID returns the record ID following the record at index i, 0xffff for the last one.
+func nextRecordID(records []record, i int) uint16 {
+	if i+1 < len(records) {
+		return records[i+1].id
+	}
+	return 0xffff
+}
+
+func (r *repo) allocRecordID() uint16 {
+	for {
+		r.nextID += 1
+		if r.nextID == 0x0000 || r.nextID == 0xffff {
+			r.nextID = 1
+		}
+
+		used := false
+		for _, rec := range r.records {
+			if rec.id == r.nextID {
+				used = true
+				break
+			}
+		}
+		if !used {
+			return r.nextID
+		}
+	}
+}
+
+func (r *repo) reserve() uint16 {
+	r.reservationID += 1
+	if r.reservationID == 0 {
+		r.reservationID = 1
+	}
+	return r.reservationID
+}
+
+// cancelReservation cancels the current reservation, as the records are changed.
+func (r *repo) cancelReservation() {
+	r.reserve()
+}
+
+func nowTimestamp() uint32 {
+	return uint32(time.Now().Unix())
+}
+
+// clock returns the present timestamp of the clock of the repository.
+func (r *repo) clock() uint32 {
+	return uint32(time.Now().Add(r.clockDrift).Unix())
+}
+
+// setClock sets the clock of the repository to the timestamp.
+func (r *repo) setClock(timestamp uint32) {
+	r.clockDrift = time.Unix(int64(timestamp), 0).Sub(time.Now().Truncate(time.Second))
+}
+
+// AddSDR appends the SDR record to the SDR Repository and returns its record ID.
+// The record holds the complete record bytes starting with the record header,
+// the record ID and record length fields of the header are filled by the Server.
+func (s *Server) AddSDR(data []byte) uint16 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.addSDR(data)
+}
+
+// AddSEL appends the 16 bytes SEL record to the System Event Log and returns its record ID.
+// The record ID field (the first 2 bytes) is filled by the Server.
+func (s *Server) AddSEL(data []byte) uint16 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.addSEL(data)
+}
+
+// ClearSEL erases all the records of the System Event Log, like the Clear SEL command.
+func (s *Server) ClearSEL() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.sel.records = nil
+	s.sel.eraseTime = nowTimestamp()
+	s.sel.cancelReservation()
+	s.selOverflow = false
+}
+
+// SetClockDrift sets how far the SEL clock and the SDR Repository clock are ahead of the host clock,
+// negative values make them behind.
+func (s *Server) SetClockDrift(sel time.Duration, sdr time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.sel.clockDrift = sel
+	s.sdr.clockDrift = sdr
+}
+
+// SetFRU sets the FRU inventory area data of the FRU device.
+func (s *Server) SetFRU(deviceID uint8, data []byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.fru[deviceID] = append([]byte{}, data...)
+}
+
+// SetSensor sets the state of the sensor.
+func (s *Server) SetSensor(sensorNumber uint8, sensor Sensor) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.sensors[sensorNumber] = &sensor
+}
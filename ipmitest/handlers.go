@@ -0,0 +1,715 @@
+package ipmitest
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"encoding/binary"
+
+	"github.com/bougou/go-ipmi"
+)
+
+func defaultBuiltins() map[handlerKey]builtinHandler {
+	return map[handlerKey]builtinHandler{
+		keyOf(ipmi.CommandGetDeviceID):                (*Server).getDeviceID,
+		keyOf(ipmi.CommandGetSystemGUID):              (*Server).getSystemGUID,
+		keyOf(ipmi.CommandGetDeviceGUID):              (*Server).getDeviceGUID,
+		keyOf(ipmi.CommandGetChannelAuthCapabilities): (*Server).getChannelAuthCapabilities,
+		keyOf(ipmi.CommandGetSessionChallenge):        (*Server).getSessionChallenge,
+		keyOf(ipmi.CommandActivateSession):            (*Server).activateSession,
+		keyOf(ipmi.CommandSetSessionPrivilegeLevel):   (*Server).setSessionPrivilegeLevel,
+		keyOf(ipmi.CommandCloseSession):               (*Server).closeSession,
+		keyOf(ipmi.CommandGetSessionInfo):             (*Server).getSessionInfo,
+		keyOf(ipmi.CommandGetChannelCipherSuites):     (*Server).getChannelCipherSuites,
+		keyOf(ipmi.CommandActivatePayload):            (*Server).activatePayload,
+		keyOf(ipmi.CommandDeactivatePayload):          (*Server).deactivatePayload,
+		keyOf(ipmi.CommandSendMessage):                (*Server).sendMessage,
+
+		keyOf(ipmi.CommandGetSDRRepoInfo): (*Server).getSDRRepoInfo,
+		keyOf(ipmi.CommandReserveSDRRepo): (*Server).reserveSDRRepo,
+		keyOf(ipmi.CommandGetSDR):         (*Server).getSDR,
+
+		keyOf(ipmi.CommandAddSDR):                 (*Server).addSDRCommand,
+		keyOf(ipmi.CommandPartialAddSDR):          (*Server).partialAddSDR,
+		keyOf(ipmi.CommandDeleteSDR):              (*Server).deleteSDR,
+		keyOf(ipmi.CommandClearSDRRepo):           (*Server).clearSDRRepo,
+		keyOf(ipmi.CommandEnterSDRRepoUpdateMode): (*Server).enterSDRRepoUpdateMode,
+		keyOf(ipmi.CommandExitSDRRepoUpdateMode):  (*Server).exitSDRRepoUpdateMode,
+		keyOf(ipmi.CommandRunInitializationAgent): (*Server).runInitializationAgent,
+		keyOf(ipmi.CommandGetSELInfo):             (*Server).getSELInfo,
+		keyOf(ipmi.CommandReserveSEL):             (*Server).reserveSEL,
+		keyOf(ipmi.CommandGetSELEntry):            (*Server).getSELEntry,
+		keyOf(ipmi.CommandGetSELTime):             (*Server).getSELTime,
+		keyOf(ipmi.CommandSetSELTime):             (*Server).setSELTime,
+		keyOf(ipmi.CommandGetSELTimeUTCOffset):    (*Server).getSELTimeUTCOffset,
+		keyOf(ipmi.CommandSetSELTimeUTCOffset):    (*Server).setSELTimeUTCOffset,
+		keyOf(ipmi.CommandGetSDRRepoTime):         (*Server).getSDRRepoTime,
+		keyOf(ipmi.CommandSetSDRRepoTime):         (*Server).setSDRRepoTime,
+
+		keyOf(ipmi.CommandAddSELEntry):             (*Server).addSELEntry,
+		keyOf(ipmi.CommandPartialAddSELEntry):      (*Server).partialAddSELEntry,
+		keyOf(ipmi.CommandDeleteSELEntry):          (*Server).deleteSELEntry,
+		keyOf(ipmi.CommandClearSEL):                (*Server).clearSEL,
+		keyOf(ipmi.CommandSetLastProcessedEventId): (*Server).setLastProcessedEventID,
+		keyOf(ipmi.CommandGetLastProcessedEventId): (*Server).getLastProcessedEventID,
+
+		keyOf(ipmi.CommandGetDeviceSDRInfo):     (*Server).getDeviceSDRInfo,
+		keyOf(ipmi.CommandReserveDeviceSDRRepo): (*Server).reserveSDRRepo,
+		keyOf(ipmi.CommandGetDeviceSDR):         (*Server).getSDR,
+
+		keyOf(ipmi.CommandGetFRUInventoryAreaInfo): (*Server).getFRUInventoryAreaInfo,
+		keyOf(ipmi.CommandReadFRUData):             (*Server).readFRUData,
+
+		keyOf(ipmi.CommandGetSensorReading):     (*Server).getSensorReading,
+		keyOf(ipmi.CommandGetSensorThresholds):  (*Server).getSensorThresholds,
+		keyOf(ipmi.CommandSetSensorThresholds):  (*Server).setSensorThresholds,
+		keyOf(ipmi.CommandGetSensorHysteresis):  (*Server).getSensorHysteresis,
+		keyOf(ipmi.CommandGetSensorEventStatus): (*Server).getSensorEventStatus,
+		keyOf(ipmi.CommandGetSensorEventEnable): (*Server).getSensorEventEnable,
+		keyOf(ipmi.CommandSetSensorEventEnable): (*Server).setSensorEventEnable,
+		keyOf(ipmi.CommandRearmSensorEvents):    (*Server).rearmSensorEvents,
+	}
+}
+
+func (s *Server) getDeviceID(sess *session, req *Request) (ipmi.CompletionCode, []byte) {
+	return ipmi.CompletionCodeNormal, s.Device.Pack()
+}
+
+func (s *Server) getSystemGUID(sess *session, req *Request) (ipmi.CompletionCode, []byte) {
+	return ipmi.CompletionCodeNormal, append([]byte{}, s.GUID[:]...)
+}
+
+// getDeviceGUID answers with the GUID too, the Server is the only controller of the system.
+func (s *Server) getDeviceGUID(sess *session, req *Request) (ipmi.CompletionCode, []byte) {
+	return ipmi.CompletionCodeNormal, append([]byte{}, s.GUID[:]...)
+}
+
+// see 22.13 Get Channel Authentication Capabilities Command
+func (s *Server) getChannelAuthCapabilities(sess *session, req *Request) (ipmi.CompletionCode, []byte) {
+	if len(req.Data) < 2 {
+		return ipmi.CompletionCodeRequestDataLengthInvalid, nil
+	}
+
+	extended := req.Data[0]&0x80 != 0
+	channel := req.Data[0] & 0x0f
+	if channel == ipmi.ChannelNumberSelf {
+		channel = 0x01
+	}
+
+	out := make([]byte, 8)
+	out[0] = channel
+
+	for _, authType := range s.AuthTypes {
+		switch authType {
+		case ipmi.AuthTypeNone:
+			out[1] |= 0x01
+		case ipmi.AuthTypeMD2:
+			out[1] |= 0x02
+		case ipmi.AuthTypeMD5:
+			out[1] |= 0x04
+		case ipmi.AuthTypePassword:
+			out[1] |= 0x10
+		case ipmi.AuthTypeOEM:
+			out[1] |= 0x20
+		}
+	}
+	if extended {
+		out[1] |= 0x80
+		// supports both IPMI v1.5 and IPMI v2.0 connections
+		out[3] = 0x03
+	}
+
+	if s.Username != "" {
+		out[2] |= 0x04 // non-null usernames enabled
+	} else {
+		out[2] |= 0x03 // null usernames and anonymous login enabled
+	}
+	if len(s.BMCKey) != 0 {
+		out[2] |= 0x20
+	}
+
+	return ipmi.CompletionCodeNormal, out
+}
+
+func (s *Server) supportAuthType(authType ipmi.AuthType) bool {
+	for _, t := range s.AuthTypes {
+		if t == authType {
+			return true
+		}
+	}
+	return false
+}
+
+// see 22.16 Get Session Challenge Command
+func (s *Server) getSessionChallenge(sess *session, req *Request) (ipmi.CompletionCode, []byte) {
+	if len(req.Data) < 17 {
+		return ipmi.CompletionCodeRequestDataLengthInvalid, nil
+	}
+
+	authType := ipmi.AuthType(req.Data[0] & 0x0f)
+	if !s.supportAuthType(authType) {
+		return ipmi.CompletionCodeRequestDataFieldInvalid, nil
+	}
+
+	username := string(bytes.TrimRight(req.Data[1:17], "\x00"))
+	if username != s.Username {
+		return 0x81, nil
+	}
+
+	if s.activeSessions() >= s.MaxSessions {
+		return ipmi.CompletionCodeOutOfSpace, nil
+	}
+
+	newSess := s.newSession()
+	newSess.username = username
+	newSess.authType = authType
+	copy(newSess.challenge[:], randomBytes(16))
+
+	out := make([]byte, 20)
+	binary.LittleEndian.PutUint32(out, newSess.id)
+	copy(out[4:], newSess.challenge[:])
+	return ipmi.CompletionCodeNormal, out
+}
+
+// see 22.17 Activate Session Command
+func (s *Server) activateSession(sess *session, req *Request) (ipmi.CompletionCode, []byte) {
+	if sess == nil || sess.v20 || sess.active {
+		return 0x85, nil
+	}
+	if len(req.Data) < 22 {
+		return ipmi.CompletionCodeRequestDataLengthInvalid, nil
+	}
+
+	authType := ipmi.AuthType(req.Data[0] & 0x0f)
+	if authType != sess.authType {
+		return ipmi.CompletionCodeRequestDataFieldInvalid, nil
+	}
+
+	maxPrivilege := ipmi.PrivilegeLevel(req.Data[1] & 0x0f)
+	if maxPrivilege > s.MaxPrivilegeLevel {
+		return 0x86, nil
+	}
+
+	if !hmac.Equal(req.Data[2:18], sess.challenge[:]) {
+		return ipmi.CompletionCodeRequestDataFieldInvalid, nil
+	}
+
+	// the BMC is allowed to change the Session ID at activation
+	delete(s.sessions, sess.id)
+	var id uint32
+	for id == 0 || s.sessions[id] != nil {
+		id = randomUint32()
+	}
+	sess.id = id
+	s.sessions[id] = sess
+
+	var inSeq uint32
+	for inSeq == 0 {
+		inSeq = randomUint32() >> 1
+	}
+
+	sess.active = true
+	sess.maxPrivilege = maxPrivilege
+	sess.privilege = minPrivilege(ipmi.PrivilegeLevelUser, maxPrivilege)
+	sess.inbound = seqWindow{highest: inSeq}
+	sess.outSeq = binary.LittleEndian.Uint32(req.Data[18:22])
+
+	out := make([]byte, 10)
+	out[0] = uint8(sess.authType)
+	binary.LittleEndian.PutUint32(out[1:], sess.id)
+	binary.LittleEndian.PutUint32(out[5:], inSeq)
+	out[9] = uint8(maxPrivilege)
+	return ipmi.CompletionCodeNormal, out
+}
+
+// see 22.18 Set Session Privilege Level Command
+func (s *Server) setSessionPrivilegeLevel(sess *session, req *Request) (ipmi.CompletionCode, []byte) {
+	if len(req.Data) < 1 {
+		return ipmi.CompletionCodeRequestDataLengthInvalid, nil
+	}
+
+	privilege := ipmi.PrivilegeLevel(req.Data[0] & 0x0f)
+	if privilege != ipmi.PrivilegeLevelUnspecified {
+		if privilege > sess.maxPrivilege {
+			return 0x81, nil
+		}
+		sess.privilege = privilege
+	}
+
+	return ipmi.CompletionCodeNormal, []byte{uint8(sess.privilege)}
+}
+
+// see 22.19 Close Session Command
+func (s *Server) closeSession(sess *session, req *Request) (ipmi.CompletionCode, []byte) {
+	if len(req.Data) < 4 {
+		return ipmi.CompletionCodeRequestDataLengthInvalid, nil
+	}
+
+	id := binary.LittleEndian.Uint32(req.Data)
+	if id != 0 {
+		target := s.sessions[id]
+		if target == nil {
+			return 0x87, nil
+		}
+		delete(s.sessions, target.id)
+		return ipmi.CompletionCodeNormal, nil
+	}
+
+	if len(req.Data) < 5 {
+		return ipmi.CompletionCodeRequestDataLengthInvalid, nil
+	}
+	for _, target := range s.sessions {
+		if target.handle == req.Data[4] {
+			delete(s.sessions, target.id)
+			return ipmi.CompletionCodeNormal, nil
+		}
+	}
+	return 0x88, nil
+}
+
+// see 22.20 Get Session Info Command
+func (s *Server) getSessionInfo(sess *session, req *Request) (ipmi.CompletionCode, []byte) {
+	if len(req.Data) < 1 {
+		return ipmi.CompletionCodeRequestDataLengthInvalid, nil
+	}
+
+	target := sess
+	switch index := req.Data[0]; index {
+	case 0x00:
+	case 0xfe:
+		if len(req.Data) < 2 {
+			return ipmi.CompletionCodeRequestDataLengthInvalid, nil
+		}
+		target = nil
+		for _, v := range s.sessions {
+			if v.active && v.handle == req.Data[1] {
+				target = v
+			}
+		}
+	case 0xff:
+		if len(req.Data) < 5 {
+			return ipmi.CompletionCodeRequestDataLengthInvalid, nil
+		}
+		target = s.sessions[binary.LittleEndian.Uint32(req.Data[1:5])]
+	default:
+		return ipmi.CompletionCodeParameterOutOfRange, nil
+	}
+
+	if target == nil || !target.active {
+		return ipmi.CompletionCodeRequestedDataNotPresent, nil
+	}
+
+	out := []byte{
+		target.handle,
+		uint8(s.MaxSessions) & 0x3f,
+		uint8(s.activeSessions()) & 0x3f,
+		0x02, // user id
+		uint8(target.privilege),
+		0x01, // channel number
+	}
+	return ipmi.CompletionCodeNormal, out
+}
+
+// see 22.15 Get Channel Cipher Suites Command
+func (s *Server) getChannelCipherSuites(sess *session, req *Request) (ipmi.CompletionCode, []byte) {
+	if len(req.Data) < 3 {
+		return ipmi.CompletionCodeRequestDataLengthInvalid, nil
+	}
+
+	channel := req.Data[0] & 0x0f
+	if channel == ipmi.ChannelNumberSelf {
+		channel = 0x01
+	}
+
+	var records []byte
+	for _, cipherSuiteID := range s.CipherSuites {
+		authAlg, integrityAlg, cryptAlg, err := cipherSuiteID.Algorithms()
+		if err != nil {
+			continue
+		}
+		records = append(records,
+			0xc0, uint8(cipherSuiteID),
+			uint8(authAlg),
+			0x40|uint8(integrityAlg),
+			0x80|uint8(cryptAlg),
+		)
+	}
+
+	index := int(req.Data[2] & 0x3f)
+	start := index * 16
+	if start > len(records) {
+		start = len(records)
+	}
+	end := start + 16
+	if end > len(records) {
+		end = len(records)
+	}
+
+	out := []byte{channel}
+	out = append(out, records[start:end]...)
+	return ipmi.CompletionCodeNormal, out
+}
+
+// see 33.9 Get SDR Repository Info Command
+func (s *Server) getSDRRepoInfo(sess *session, req *Request) (ipmi.CompletionCode, []byte) {
+	out := make([]byte, 14)
+	out[0] = 0x51
+	binary.LittleEndian.PutUint16(out[1:], uint16(len(s.sdr.records)))
+	binary.LittleEndian.PutUint16(out[3:], 0xfffe) // free space unspecified
+	binary.LittleEndian.PutUint32(out[5:], s.sdr.addTime)
+	binary.LittleEndian.PutUint32(out[9:], s.sdr.eraseTime)
+	if s.SDRModalUpdate {
+		out[13] = 0x4e // modal update, Delete SDR, Partial Add SDR, Reserve SDR Repository supported
+	} else {
+		out[13] = 0x2e // non-modal update, Delete SDR, Partial Add SDR, Reserve SDR Repository supported
+	}
+	return ipmi.CompletionCodeNormal, out
+}
+
+func (s *Server) reserveSDRRepo(sess *session, req *Request) (ipmi.CompletionCode, []byte) {
+	out := make([]byte, 2)
+	binary.LittleEndian.PutUint16(out, s.sdr.reserve())
+	return ipmi.CompletionCodeNormal, out
+}
+
+// see 33.12 Get SDR Command
+func (s *Server) getSDR(sess *session, req *Request) (ipmi.CompletionCode, []byte) {
+	return s.readRecord(&s.sdr, req, s.SDRMaxReadBytes)
+}
+
+// see 35.2 Get Device SDR Info Command, the SDR repository of the Server serves as its device SDRs.
+func (s *Server) getDeviceSDRInfo(sess *session, req *Request) (ipmi.CompletionCode, []byte) {
+	// static sensor population, LUN 0 has sensors
+	return ipmi.CompletionCodeNormal, []byte{uint8(len(s.sdr.records)), 0x01}
+}
+
+// see 31.2 Get SEL Info Command
+func (s *Server) getSELInfo(sess *session, req *Request) (ipmi.CompletionCode, []byte) {
+	out := make([]byte, 14)
+	out[0] = 0x51
+	binary.LittleEndian.PutUint16(out[1:], uint16(len(s.sel.records)))
+	binary.LittleEndian.PutUint16(out[3:], 0xffff)
+	binary.LittleEndian.PutUint32(out[5:], s.sel.addTime)
+	binary.LittleEndian.PutUint32(out[9:], s.sel.eraseTime)
+	out[13] = 0x02 // Reserve SEL supported
+	if s.selOverflow {
+		out[13] |= 0x80
+	}
+	return ipmi.CompletionCodeNormal, out
+}
+
+func (s *Server) reserveSEL(sess *session, req *Request) (ipmi.CompletionCode, []byte) {
+	out := make([]byte, 2)
+	binary.LittleEndian.PutUint16(out, s.sel.reserve())
+	return ipmi.CompletionCodeNormal, out
+}
+
+// see 31.10 Get SEL Time Command
+func (s *Server) getSELTime(sess *session, req *Request) (ipmi.CompletionCode, []byte) {
+	return getClock(&s.sel)
+}
+
+// see 31.11 Set SEL Time Command
+func (s *Server) setSELTime(sess *session, req *Request) (ipmi.CompletionCode, []byte) {
+	return setClock(&s.sel, req)
+}
+
+// see 31.11a Get SEL Time UTC Offset Command
+func (s *Server) getSELTimeUTCOffset(sess *session, req *Request) (ipmi.CompletionCode, []byte) {
+	out := make([]byte, 2)
+	binary.LittleEndian.PutUint16(out, uint16(s.selUTCOffset))
+	return ipmi.CompletionCodeNormal, out
+}
+
+// see 31.11b Set SEL Time UTC Offset Command
+func (s *Server) setSELTimeUTCOffset(sess *session, req *Request) (ipmi.CompletionCode, []byte) {
+	if len(req.Data) < 2 {
+		return ipmi.CompletionCodeRequestDataLengthInvalid, nil
+	}
+	offset := int16(binary.LittleEndian.Uint16(req.Data))
+	if offset != ipmi.SELTimeUTCOffsetUnspecified && (offset < -1440 || offset > 1440) {
+		return ipmi.CompletionCodeParameterOutOfRange, nil
+	}
+	s.selUTCOffset = offset
+	return ipmi.CompletionCodeNormal, nil
+}
+
+// see 33.11 Get SDR Repository Time Command
+func (s *Server) getSDRRepoTime(sess *session, req *Request) (ipmi.CompletionCode, []byte) {
+	return getClock(&s.sdr)
+}
+
+// see 33.12 Set SDR Repository Time Command
+func (s *Server) setSDRRepoTime(sess *session, req *Request) (ipmi.CompletionCode, []byte) {
+	return setClock(&s.sdr, req)
+}
+
+func getClock(r *repo) (ipmi.CompletionCode, []byte) {
+	out := make([]byte, 4)
+	binary.LittleEndian.PutUint32(out, r.clock())
+	return ipmi.CompletionCodeNormal, out
+}
+
+func setClock(r *repo, req *Request) (ipmi.CompletionCode, []byte) {
+	if len(req.Data) < 4 {
+		return ipmi.CompletionCodeRequestDataLengthInvalid, nil
+	}
+	r.setClock(binary.LittleEndian.Uint32(req.Data))
+	return ipmi.CompletionCodeNormal, nil
+}
+
+// see 31.5 Get SEL Entry Command
+func (s *Server) getSELEntry(sess *session, req *Request) (ipmi.CompletionCode, []byte) {
+	return s.readRecord(&s.sel, req, 0)
+}
+
+// readRecord answers the Get SDR and Get SEL Entry commands which share the same request format,
+// reservation ID (2 bytes), record ID (2 bytes), offset into record, bytes to read.
+func (s *Server) readRecord(r *repo, req *Request, maxReadBytes int) (ipmi.CompletionCode, []byte) {
+	if len(req.Data) < 6 {
+		return ipmi.CompletionCodeRequestDataLengthInvalid, nil
+	}
+
+	reservationID := binary.LittleEndian.Uint16(req.Data[0:2])
+	recordID := binary.LittleEndian.Uint16(req.Data[2:4])
+	offset := int(req.Data[4])
+	count := int(req.Data[5])
+
+	// a reservation is only required for partial reads
+	if offset != 0 && reservationID != r.reservationID {
+		return ipmi.CompletionCodeReservationCanceled, nil
+	}
+
+	i := find(r.records, recordID)
+	if i < 0 {
+		return ipmi.CompletionCodeRequestedDataNotPresent, nil
+	}
+	data := r.records[i].data
+
+	if offset > len(data) {
+		return ipmi.CompletionCodeParameterOutOfRange, nil
+	}
+	if count == 0xff || offset+count > len(data) {
+		count = len(data) - offset
+	}
+	if maxReadBytes > 0 && count > maxReadBytes {
+		return ipmi.CompletionCodeCannotReturnRequestedDataBytes, nil
+	}
+
+	out := make([]byte, 2, 2+count)
+	binary.LittleEndian.PutUint16(out, nextRecordID(r.records, i))
+	out = append(out, data[offset:offset+count]...)
+	return ipmi.CompletionCodeNormal, out
+}
+
+// see 34.1 Get FRU Inventory Area Info Command
+func (s *Server) getFRUInventoryAreaInfo(sess *session, req *Request) (ipmi.CompletionCode, []byte) {
+	if len(req.Data) < 1 {
+		return ipmi.CompletionCodeRequestDataLengthInvalid, nil
+	}
+
+	data, ok := s.fru[req.Data[0]]
+	if !ok {
+		return ipmi.CompletionCodeRequestedDataNotPresent, nil
+	}
+
+	out := make([]byte, 3)
+	binary.LittleEndian.PutUint16(out, uint16(len(data)))
+	return ipmi.CompletionCodeNormal, out
+}
+
+// see 34.2 Read FRU Data Command
+func (s *Server) readFRUData(sess *session, req *Request) (ipmi.CompletionCode, []byte) {
+	if len(req.Data) < 4 {
+		return ipmi.CompletionCodeRequestDataLengthInvalid, nil
+	}
+
+	data, ok := s.fru[req.Data[0]]
+	if !ok {
+		return ipmi.CompletionCodeRequestedDataNotPresent, nil
+	}
+
+	offset := int(binary.LittleEndian.Uint16(req.Data[1:3]))
+	count := int(req.Data[3])
+	if offset > len(data) {
+		return ipmi.CompletionCodeParameterOutOfRange, nil
+	}
+	if offset+count > len(data) {
+		count = len(data) - offset
+	}
+
+	out := []byte{uint8(count)}
+	out = append(out, data[offset:offset+count]...)
+	return ipmi.CompletionCodeNormal, out
+}
+
+func (s *Server) sensor(req *Request) *Sensor {
+	if len(req.Data) < 1 {
+		return nil
+	}
+	return s.sensors[req.Data[0]]
+}
+
+func (sensor *Sensor) flags() uint8 {
+	var b uint8
+	if !sensor.EventMessagesDisabled {
+		b |= 0x80
+	}
+	if !sensor.ScanningDisabled {
+		b |= 0x40
+	}
+	if sensor.ReadingUnavailable {
+		b |= 0x20
+	}
+	return b
+}
+
+// see 35.14 Get Sensor Reading Command
+func (s *Server) getSensorReading(sess *session, req *Request) (ipmi.CompletionCode, []byte) {
+	sensor := s.sensor(req)
+	if sensor == nil {
+		return ipmi.CompletionCodeRequestedDataNotPresent, nil
+	}
+
+	out := []byte{
+		sensor.Reading,
+		sensor.flags(),
+		uint8(sensor.States),
+		uint8(sensor.States>>8) | 0x80,
+	}
+	return ipmi.CompletionCodeNormal, out
+}
+
+// see 35.9 Get Sensor Thresholds Command
+func (s *Server) getSensorThresholds(sess *session, req *Request) (ipmi.CompletionCode, []byte) {
+	sensor := s.sensor(req)
+	if sensor == nil {
+		return ipmi.CompletionCodeRequestedDataNotPresent, nil
+	}
+
+	out := []byte{
+		sensor.ReadableThresholds & 0x3f,
+		sensor.LNC, sensor.LCR, sensor.LNR,
+		sensor.UNC, sensor.UCR, sensor.UNR,
+	}
+	return ipmi.CompletionCodeNormal, out
+}
+
+// see 35.8 Set Sensor Thresholds Command
+func (s *Server) setSensorThresholds(sess *session, req *Request) (ipmi.CompletionCode, []byte) {
+	if len(req.Data) < 8 {
+		return ipmi.CompletionCodeRequestDataLengthInvalid, nil
+	}
+	sensor := s.sensor(req)
+	if sensor == nil {
+		return ipmi.CompletionCodeRequestedDataNotPresent, nil
+	}
+
+	// bit N of the mask is the Nth threshold value
+	mask := req.Data[1]
+	thresholds := []*uint8{&sensor.LNC, &sensor.LCR, &sensor.LNR, &sensor.UNC, &sensor.UCR, &sensor.UNR}
+	for i, threshold := range thresholds {
+		if mask&(1<<i) != 0 {
+			*threshold = req.Data[2+i]
+		}
+	}
+	return ipmi.CompletionCodeNormal, nil
+}
+
+// see 35.7 Get Sensor Hysteresis Command
+func (s *Server) getSensorHysteresis(sess *session, req *Request) (ipmi.CompletionCode, []byte) {
+	sensor := s.sensor(req)
+	if sensor == nil {
+		return ipmi.CompletionCodeRequestedDataNotPresent, nil
+	}
+
+	return ipmi.CompletionCodeNormal, []byte{sensor.PositiveHysteresis, sensor.NegativeHysteresis}
+}
+
+// see 35.13 Get Sensor Event Status Command
+func (s *Server) getSensorEventStatus(sess *session, req *Request) (ipmi.CompletionCode, []byte) {
+	sensor := s.sensor(req)
+	if sensor == nil {
+		return ipmi.CompletionCodeRequestedDataNotPresent, nil
+	}
+
+	out := []byte{
+		sensor.flags(),
+		uint8(sensor.AssertionEvents),
+		uint8(sensor.AssertionEvents >> 8),
+		uint8(sensor.DeassertionEvents),
+		uint8(sensor.DeassertionEvents >> 8),
+	}
+	return ipmi.CompletionCodeNormal, out
+}
+
+// see 35.11 Get Sensor Event Enable Command
+func (s *Server) getSensorEventEnable(sess *session, req *Request) (ipmi.CompletionCode, []byte) {
+	sensor := s.sensor(req)
+	if sensor == nil {
+		return ipmi.CompletionCodeRequestedDataNotPresent, nil
+	}
+
+	out := []byte{
+		sensor.flags() & 0xc0,
+		uint8(sensor.AssertionEnables),
+		uint8(sensor.AssertionEnables >> 8),
+		uint8(sensor.DeassertionEnables),
+		uint8(sensor.DeassertionEnables >> 8),
+	}
+	return ipmi.CompletionCodeNormal, out
+}
+
+// see 35.10 Set Sensor Event Enable Command
+func (s *Server) setSensorEventEnable(sess *session, req *Request) (ipmi.CompletionCode, []byte) {
+	if len(req.Data) < 2 {
+		return ipmi.CompletionCodeRequestDataLengthInvalid, nil
+	}
+	sensor := s.sensor(req)
+	if sensor == nil {
+		return ipmi.CompletionCodeRequestedDataNotPresent, nil
+	}
+
+	sensor.EventMessagesDisabled = req.Data[1]&0x80 == 0
+	sensor.ScanningDisabled = req.Data[1]&0x40 == 0
+
+	masks := make([]byte, 4)
+	copy(masks, req.Data[2:])
+	assertion := binary.LittleEndian.Uint16(masks[0:2])
+	deassertion := binary.LittleEndian.Uint16(masks[2:4])
+
+	switch (req.Data[1] >> 4) & 0x03 {
+	case 0x01:
+		sensor.AssertionEnables |= assertion
+		sensor.DeassertionEnables |= deassertion
+	case 0x02:
+		sensor.AssertionEnables &^= assertion
+		sensor.DeassertionEnables &^= deassertion
+	case 0x03:
+		return ipmi.CompletionCodeRequestDataFieldInvalid, nil
+	}
+	return ipmi.CompletionCodeNormal, nil
+}
+
+// see 35.12 Re-arm Sensor Events Command
+func (s *Server) rearmSensorEvents(sess *session, req *Request) (ipmi.CompletionCode, []byte) {
+	if len(req.Data) < 2 {
+		return ipmi.CompletionCodeRequestDataLengthInvalid, nil
+	}
+	sensor := s.sensor(req)
+	if sensor == nil {
+		return ipmi.CompletionCodeRequestedDataNotPresent, nil
+	}
+
+	// [7] - 0b = re-arm all event status from this sensor
+	if req.Data[1]&0x80 == 0 {
+		sensor.AssertionEvents = 0
+		sensor.DeassertionEvents = 0
+		return ipmi.CompletionCodeNormal, nil
+	}
+
+	masks := make([]byte, 4)
+	copy(masks, req.Data[2:])
+	sensor.AssertionEvents &^= binary.LittleEndian.Uint16(masks[0:2])
+	sensor.DeassertionEvents &^= binary.LittleEndian.Uint16(masks[2:4])
+	return ipmi.CompletionCodeNormal, nil
+}
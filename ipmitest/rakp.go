@@ -0,0 +1,238 @@
+package ipmitest
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"encoding/binary"
+	"time"
+
+	"github.com/bougou/go-ipmi"
+)
+
+// openSession answers the RMCP+ Open Session Request.
+// see 13.17 RMCP+ Open Session Request, 13.18 RMCP+ Open Session Response
+func (s *Server) openSession(msg []byte) []byte {
+	if len(msg) < ipmi.RmcpOpenSessionRequestSize {
+		return nil
+	}
+
+	tag := msg[0]
+	requestedPrivilege := ipmi.PrivilegeLevel(msg[1] & 0x0f)
+	consoleID := binary.LittleEndian.Uint32(msg[4:8])
+	authAlg := ipmi.AuthAlg(msg[12] & 0x3f)
+	integrityAlg := ipmi.IntegrityAlg(msg[20] & 0x3f)
+	cryptAlg := ipmi.CryptAlg(msg[28] & 0x3f)
+
+	var failed = func(status ipmi.RmcpStatusCode) []byte {
+		out := make([]byte, 8)
+		out[0] = tag
+		out[1] = uint8(status)
+		binary.LittleEndian.PutUint32(out[4:], consoleID)
+		return out
+	}
+
+	if !s.supportCipherSuite(authAlg, integrityAlg, cryptAlg) {
+		return failed(ipmi.RmcpStatusCodeNoCipherSuiteMatch)
+	}
+
+	maxPrivilege := requestedPrivilege
+	if maxPrivilege == ipmi.PrivilegeLevelUnspecified {
+		// the highest level matching the proposed algorithms
+		maxPrivilege = s.MaxPrivilegeLevel
+	}
+	if maxPrivilege > s.MaxPrivilegeLevel {
+		return failed(ipmi.RmcpStatusCodeUnauthorizedRoleOfPriLevel)
+	}
+
+	if s.activeSessions() >= s.MaxSessions {
+		return failed(ipmi.RmcpStatusCodeNoResToCreateSess)
+	}
+
+	sess := s.newSession()
+	sess.v20 = true
+	sess.consoleID = consoleID
+	sess.authAlg = authAlg
+	sess.integrityAlg = integrityAlg
+	sess.cryptAlg = cryptAlg
+	sess.maxPrivilege = maxPrivilege
+
+	out := make([]byte, ipmi.RmcpOpenSessionResponseSize)
+	out[0] = tag
+	out[1] = uint8(ipmi.RmcpStatusCodeNoErrors)
+	out[2] = uint8(maxPrivilege)
+	binary.LittleEndian.PutUint32(out[4:], consoleID)
+	binary.LittleEndian.PutUint32(out[8:], sess.id)
+	copy(out[12:], algorithmPayload(0x00, uint8(authAlg)))
+	copy(out[20:], algorithmPayload(0x01, uint8(integrityAlg)))
+	copy(out[28:], algorithmPayload(0x02, uint8(cryptAlg)))
+	return out
+}
+
+func algorithmPayload(payloadType uint8, alg uint8) []byte {
+	return []byte{payloadType, 0x00, 0x00, 0x08, alg, 0x00, 0x00, 0x00}
+}
+
+func (s *Server) supportCipherSuite(authAlg ipmi.AuthAlg, integrityAlg ipmi.IntegrityAlg, cryptAlg ipmi.CryptAlg) bool {
+	for _, cipherSuiteID := range s.CipherSuites {
+		a, i, c, err := cipherSuiteID.Algorithms()
+		if err != nil {
+			continue
+		}
+		if a == authAlg && i == integrityAlg && c == cryptAlg {
+			return true
+		}
+	}
+	return false
+}
+
+// rakp1 answers the RAKP Message 1 with RAKP Message 2.
+// see 13.20 RAKP Message 1, 13.21 RAKP Message 2
+func (s *Server) rakp1(msg []byte) []byte {
+	if len(msg) < 28 {
+		return nil
+	}
+
+	tag := msg[0]
+	bmcID := binary.LittleEndian.Uint32(msg[4:8])
+
+	var failed = func(status ipmi.RmcpStatusCode, consoleID uint32) []byte {
+		out := make([]byte, 8)
+		out[0] = tag
+		out[1] = uint8(status)
+		binary.LittleEndian.PutUint32(out[4:], consoleID)
+		return out
+	}
+
+	sess := s.sessions[bmcID]
+	if sess == nil || !sess.v20 || sess.active {
+		return failed(ipmi.RmcpStatusCodeInvalidSessionID, 0)
+	}
+	sess.lastActive = time.Now()
+
+	role := msg[24]
+	usernameLength := int(msg[27])
+	if usernameLength > ipmi.IPMI_MAX_USER_NAME_LENGTH || len(msg) < 28+usernameLength {
+		return failed(ipmi.RmcpStatusCodeInvalidNameLength, sess.consoleID)
+	}
+	username := string(msg[28 : 28+usernameLength])
+	if username != s.Username {
+		return failed(ipmi.RmcpStatusCodeUnauthorizedName, sess.consoleID)
+	}
+
+	privilege := ipmi.PrivilegeLevel(role & 0x0f)
+	if privilege > sess.maxPrivilege {
+		return failed(ipmi.RmcpStatusCodeUnauthorizedRoleOfPriLevel, sess.consoleID)
+	}
+
+	copy(sess.consoleRand[:], msg[8:24])
+	copy(sess.bmcRand[:], randomBytes(16))
+	sess.role = role
+	sess.username = username
+	sess.maxPrivilege = privilege
+	sess.rakp1Done = true
+
+	// see 13.31 RMCP+ Authenticated Key-Exchange Protocol (RAKP)
+	var input []byte
+	input = binary.LittleEndian.AppendUint32(input, sess.consoleID)
+	input = binary.LittleEndian.AppendUint32(input, sess.id)
+	input = append(input, sess.consoleRand[:]...)
+	input = append(input, sess.bmcRand[:]...)
+	input = append(input, s.GUID[:]...)
+	input = append(input, role, uint8(len(username)))
+	input = append(input, username...)
+	authCode := authHMAC(sess.authAlg, padBytes(s.Password, 20), input)
+
+	out := make([]byte, 40, 40+len(authCode))
+	out[0] = tag
+	out[1] = uint8(ipmi.RmcpStatusCodeNoErrors)
+	binary.LittleEndian.PutUint32(out[4:], sess.consoleID)
+	copy(out[8:], sess.bmcRand[:])
+	copy(out[24:], s.GUID[:])
+	return append(out, authCode...)
+}
+
+// rakp3 validates the RAKP Message 3, activates the session and answers with RAKP Message 4.
+// see 13.22 RAKP Message 3, 13.23 RAKP Message 4
+func (s *Server) rakp3(msg []byte) []byte {
+	if len(msg) < 8 {
+		return nil
+	}
+
+	tag := msg[0]
+	status := ipmi.RmcpStatusCode(msg[1])
+	bmcID := binary.LittleEndian.Uint32(msg[4:8])
+
+	var failed = func(status ipmi.RmcpStatusCode, consoleID uint32) []byte {
+		out := make([]byte, 8)
+		out[0] = tag
+		out[1] = uint8(status)
+		binary.LittleEndian.PutUint32(out[4:], consoleID)
+		return out
+	}
+
+	sess := s.sessions[bmcID]
+	if sess == nil || !sess.v20 || sess.active || !sess.rakp1Done {
+		return failed(ipmi.RmcpStatusCodeInvalidSessionID, 0)
+	}
+
+	if status != ipmi.RmcpStatusCodeNoErrors {
+		// the remote console aborts the session establishment
+		delete(s.sessions, sess.id)
+		return failed(status, sess.consoleID)
+	}
+
+	var input []byte
+	input = append(input, sess.bmcRand[:]...)
+	input = binary.LittleEndian.AppendUint32(input, sess.consoleID)
+	input = append(input, sess.role, uint8(len(sess.username)))
+	input = append(input, sess.username...)
+	expected := authHMAC(sess.authAlg, padBytes(s.Password, 20), input)
+	if !hmac.Equal(msg[8:], expected) {
+		delete(s.sessions, sess.id)
+		return failed(ipmi.RmcpStatusCodeInvalidIntegrityCheckValue, sess.consoleID)
+	}
+
+	// see 13.31 for Session Integrity Key, 13.32 for K1 and K2
+	var sikInput []byte
+	sikInput = append(sikInput, sess.consoleRand[:]...)
+	sikInput = append(sikInput, sess.bmcRand[:]...)
+	sikInput = append(sikInput, sess.role, uint8(len(sess.username)))
+	sikInput = append(sikInput, sess.username...)
+	sikKey := s.BMCKey
+	if len(sikKey) == 0 {
+		sikKey = padBytes(s.Password, 20)
+	}
+	sess.sik = authHMAC(sess.authAlg, sikKey, sikInput)
+	sess.k1 = authHMAC(sess.authAlg, sess.sik, bytes.Repeat([]byte{0x01}, 20))
+	sess.k2 = authHMAC(sess.authAlg, sess.sik, bytes.Repeat([]byte{0x02}, 20))
+
+	var icvInput []byte
+	icvInput = append(icvInput, sess.consoleRand[:]...)
+	icvInput = binary.LittleEndian.AppendUint32(icvInput, sess.id)
+	icvInput = append(icvInput, s.GUID[:]...)
+	icv := authHMAC(sess.authAlg, sess.sik, icvInput)
+	switch sess.authAlg {
+	case ipmi.AuthAlgRAKP_HMAC_SHA1:
+		icv = icv[:12]
+	case ipmi.AuthAlgRAKP_HMAC_MD5, ipmi.AuthAlgRAKP_HMAC_SHA256:
+		icv = icv[:16]
+	}
+
+	sess.active = true
+	sess.privilege = minPrivilege(ipmi.PrivilegeLevelUser, sess.maxPrivilege)
+	copy(sess.rc4EncryptIV[:], randomBytes(16))
+	sess.lastActive = time.Now()
+
+	out := make([]byte, 8, 8+len(icv))
+	out[0] = tag
+	out[1] = uint8(ipmi.RmcpStatusCodeNoErrors)
+	binary.LittleEndian.PutUint32(out[4:], sess.consoleID)
+	return append(out, icv...)
+}
+
+func minPrivilege(a ipmi.PrivilegeLevel, b ipmi.PrivilegeLevel) ipmi.PrivilegeLevel {
+	if a < b {
+		return a
+	}
+	return b
+}
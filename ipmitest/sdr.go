@@ -0,0 +1,187 @@
+package ipmitest
+
+import (
+	"encoding/binary"
+
+	"github.com/bougou/go-ipmi"
+)
+
+// addSDR appends the record to the SDR Repository, the record ID and record length fields
+// of the header are filled by the Server. The caller must hold the lock.
+func (s *Server) addSDR(data []byte) uint16 {
+	r := append([]byte{}, data...)
+	id := s.sdr.allocRecordID()
+	if len(r) >= 5 {
+		binary.LittleEndian.PutUint16(r, id)
+		r[4] = uint8(len(r) - 5)
+	}
+	s.sdr.records = append(s.sdr.records, record{id: id, data: r})
+	s.sdr.addTime = nowTimestamp()
+	return id
+}
+
+// sdrWritable returns the completion code for the commands writing the SDR Repository,
+// which is only writable in the update mode if it is modal.
+func (s *Server) sdrWritable() ipmi.CompletionCode {
+	if s.SDRModalUpdate && !s.sdrUpdateMode {
+		return ipmi.CompletionCodeCannotExecuteCommandNotSupported
+	}
+	return ipmi.CompletionCodeNormal
+}
+
+// see 33.13 Add SDR Command
+func (s *Server) addSDRCommand(sess *session, req *Request) (ipmi.CompletionCode, []byte) {
+	if len(req.Data) < 5 || int(req.Data[4])+5 != len(req.Data) {
+		return ipmi.CompletionCodeRequestDataLengthInvalid, nil
+	}
+	if cc := s.sdrWritable(); cc != ipmi.CompletionCodeNormal {
+		return cc, nil
+	}
+
+	id := s.addSDR(req.Data)
+	s.sdr.cancelReservation()
+
+	out := make([]byte, 2)
+	binary.LittleEndian.PutUint16(out, id)
+	return ipmi.CompletionCodeNormal, out
+}
+
+// see 33.14 Partial Add SDR Command
+func (s *Server) partialAddSDR(sess *session, req *Request) (ipmi.CompletionCode, []byte) {
+	if len(req.Data) < 6 {
+		return ipmi.CompletionCodeRequestDataLengthInvalid, nil
+	}
+	if cc := s.sdrWritable(); cc != ipmi.CompletionCodeNormal {
+		return cc, nil
+	}
+
+	reservationID := binary.LittleEndian.Uint16(req.Data[0:2])
+	recordID := binary.LittleEndian.Uint16(req.Data[2:4])
+	offset := int(req.Data[4])
+	lastPart := req.Data[5]&0x0f == 0x01
+	data := req.Data[6:]
+
+	if reservationID != s.sdr.reservationID {
+		s.sdr.partial = nil
+		return ipmi.CompletionCodeReservationCanceled, nil
+	}
+
+	if recordID == 0x0000 && offset == 0 {
+		s.sdr.partial = &record{id: s.sdr.allocRecordID()}
+	}
+	partial := s.sdr.partial
+	if partial == nil || (recordID != 0x0000 && recordID != partial.id) || offset != len(partial.data) {
+		return ipmi.CompletionCodeRequestDataFieldInvalid, nil
+	}
+	partial.data = append(partial.data, data...)
+
+	out := make([]byte, 2)
+	binary.LittleEndian.PutUint16(out, partial.id)
+
+	if !lastPart {
+		return ipmi.CompletionCodeNormal, out
+	}
+
+	s.sdr.partial = nil
+	if len(partial.data) < 5 || int(partial.data[4])+5 != len(partial.data) {
+		// record rejected due to length mismatch
+		return 0x80, nil
+	}
+	binary.LittleEndian.PutUint16(partial.data, partial.id)
+	s.sdr.records = append(s.sdr.records, *partial)
+	s.sdr.addTime = nowTimestamp()
+	s.sdr.cancelReservation()
+	return ipmi.CompletionCodeNormal, out
+}
+
+// see 33.15 Delete SDR Command
+func (s *Server) deleteSDR(sess *session, req *Request) (ipmi.CompletionCode, []byte) {
+	if len(req.Data) < 4 {
+		return ipmi.CompletionCodeRequestDataLengthInvalid, nil
+	}
+	if cc := s.sdrWritable(); cc != ipmi.CompletionCodeNormal {
+		return cc, nil
+	}
+
+	reservationID := binary.LittleEndian.Uint16(req.Data[0:2])
+	recordID := binary.LittleEndian.Uint16(req.Data[2:4])
+	if reservationID != s.sdr.reservationID {
+		return ipmi.CompletionCodeReservationCanceled, nil
+	}
+
+	i := find(s.sdr.records, recordID)
+	if i < 0 {
+		return ipmi.CompletionCodeRequestedDataNotPresent, nil
+	}
+	id := s.sdr.records[i].id
+	s.sdr.records = append(s.sdr.records[:i], s.sdr.records[i+1:]...)
+	s.sdr.eraseTime = nowTimestamp()
+	s.sdr.cancelReservation()
+
+	out := make([]byte, 2)
+	binary.LittleEndian.PutUint16(out, id)
+	return ipmi.CompletionCodeNormal, out
+}
+
+// see 33.16 Clear SDR Repository Command
+//
+// The records are erased once the erasure is initiated, but the erasure is reported in progress
+// until the status is got once, so the clients have to poll for the completion.
+func (s *Server) clearSDRRepo(sess *session, req *Request) (ipmi.CompletionCode, []byte) {
+	return s.clearRepo(&s.sdr, req, s.sdrWritable())
+}
+
+// clearRepo answers the Clear SDR Repository and Clear SEL commands which share the same request format,
+// reservation ID (2 bytes), 'C', 'L', 'R', AAh to initiate erase or 00h to get erasure status.
+func (s *Server) clearRepo(r *repo, req *Request, writable ipmi.CompletionCode) (ipmi.CompletionCode, []byte) {
+	if len(req.Data) < 6 {
+		return ipmi.CompletionCodeRequestDataLengthInvalid, nil
+	}
+	if string(req.Data[2:5]) != "CLR" {
+		return ipmi.CompletionCodeRequestDataFieldInvalid, nil
+	}
+	if writable != ipmi.CompletionCodeNormal {
+		return writable, nil
+	}
+
+	reservationID := binary.LittleEndian.Uint16(req.Data[0:2])
+	if reservationID != r.reservationID {
+		return ipmi.CompletionCodeReservationCanceled, nil
+	}
+
+	switch req.Data[5] {
+	case 0xaa:
+		r.records = nil
+		r.partial = nil
+		r.eraseTime = nowTimestamp()
+		r.erasing = true
+		return ipmi.CompletionCodeNormal, []byte{0x00} // erasure in progress
+	case 0x00:
+		if r.erasing {
+			r.erasing = false
+			return ipmi.CompletionCodeNormal, []byte{0x00} // erasure in progress
+		}
+		return ipmi.CompletionCodeNormal, []byte{0x01} // erasure completed
+	}
+	return ipmi.CompletionCodeRequestDataFieldInvalid, nil
+}
+
+// see 33.19 Enter SDR Repository Update Mode Command
+func (s *Server) enterSDRRepoUpdateMode(sess *session, req *Request) (ipmi.CompletionCode, []byte) {
+	s.sdrUpdateMode = true
+	return ipmi.CompletionCodeNormal, nil
+}
+
+// see 33.20 Exit SDR Repository Update Mode Command
+func (s *Server) exitSDRRepoUpdateMode(sess *session, req *Request) (ipmi.CompletionCode, []byte) {
+	s.sdrUpdateMode = false
+	return ipmi.CompletionCodeNormal, nil
+}
+
+// see 33.21 Run Initialization Agent Command, the Server has nothing to initialize.
+func (s *Server) runInitializationAgent(sess *session, req *Request) (ipmi.CompletionCode, []byte) {
+	if len(req.Data) < 1 {
+		return ipmi.CompletionCodeRequestDataLengthInvalid, nil
+	}
+	return ipmi.CompletionCodeNormal, []byte{0x01} // completed
+}
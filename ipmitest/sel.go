@@ -0,0 +1,173 @@
+package ipmitest
+
+import (
+	"encoding/binary"
+
+	"github.com/bougou/go-ipmi"
+)
+
+// addSEL appends the 16 bytes record to the SEL, the oldest records are overwritten if the SEL is full,
+// see SELMaxEntries. The record ID field is filled by the Server. The caller must hold the lock.
+func (s *Server) addSEL(data []byte) uint16 {
+	return s.appendSEL(s.sel.allocRecordID(), data)
+}
+
+// appendSEL appends the record with the allocated record ID, see addSEL.
+func (s *Server) appendSEL(id uint16, data []byte) uint16 {
+	r := make([]byte, 16)
+	copy(r, data)
+	binary.LittleEndian.PutUint16(r, id)
+	s.sel.records = append(s.sel.records, record{id: id, data: r})
+	s.sel.addTime = nowTimestamp()
+
+	if s.SELMaxEntries > 0 && len(s.sel.records) > s.SELMaxEntries {
+		s.sel.records = s.sel.records[len(s.sel.records)-s.SELMaxEntries:]
+		s.selOverflow = true
+	}
+	return id
+}
+
+// see 31.6 Add SEL Entry Command
+func (s *Server) addSELEntry(sess *session, req *Request) (ipmi.CompletionCode, []byte) {
+	if len(req.Data) != 16 {
+		return ipmi.CompletionCodeRequestDataLengthInvalid, nil
+	}
+	if s.sel.erasing {
+		// cannot execute command, SEL erase in progress
+		return 0x81, nil
+	}
+
+	id := s.addSEL(s.stampSEL(req.Data))
+
+	out := make([]byte, 2)
+	binary.LittleEndian.PutUint16(out, id)
+	return ipmi.CompletionCodeNormal, out
+}
+
+// stampSEL returns a copy of the record added by the client, the standard and the timestamped OEM records
+// are timestamped by the SEL Device.
+func (s *Server) stampSEL(data []byte) []byte {
+	r := append([]byte{}, data...)
+	if r[2] < 0xe0 {
+		binary.LittleEndian.PutUint32(r[3:], s.sel.clock())
+	}
+	return r
+}
+
+// see 31.7 Partial Add SEL Entry Command
+func (s *Server) partialAddSELEntry(sess *session, req *Request) (ipmi.CompletionCode, []byte) {
+	if len(req.Data) < 6 {
+		return ipmi.CompletionCodeRequestDataLengthInvalid, nil
+	}
+	if s.sel.erasing {
+		// cannot execute command, SEL erase in progress
+		return 0x81, nil
+	}
+
+	reservationID := binary.LittleEndian.Uint16(req.Data[0:2])
+	recordID := binary.LittleEndian.Uint16(req.Data[2:4])
+	offset := int(req.Data[4])
+	lastPart := req.Data[5]&0x0f == 0x01
+	data := req.Data[6:]
+
+	if reservationID != s.sel.reservationID {
+		s.sel.partial = nil
+		return ipmi.CompletionCodeReservationCanceled, nil
+	}
+
+	if recordID == 0x0000 && offset == 0 {
+		s.sel.partial = &record{id: s.sel.allocRecordID()}
+	}
+	partial := s.sel.partial
+	if partial == nil || (recordID != 0x0000 && recordID != partial.id) || offset != len(partial.data) {
+		return ipmi.CompletionCodeRequestDataFieldInvalid, nil
+	}
+	partial.data = append(partial.data, data...)
+
+	out := make([]byte, 2)
+	binary.LittleEndian.PutUint16(out, partial.id)
+
+	if !lastPart {
+		return ipmi.CompletionCodeNormal, out
+	}
+
+	s.sel.partial = nil
+	if len(partial.data) != 16 {
+		// record rejected due to length mismatch
+		return 0x80, nil
+	}
+	s.appendSEL(partial.id, s.stampSEL(partial.data))
+	return ipmi.CompletionCodeNormal, out
+}
+
+// see 31.8 Delete SEL Entry Command
+func (s *Server) deleteSELEntry(sess *session, req *Request) (ipmi.CompletionCode, []byte) {
+	if len(req.Data) < 4 {
+		return ipmi.CompletionCodeRequestDataLengthInvalid, nil
+	}
+	if s.sel.erasing {
+		// cannot execute command, SEL erase in progress
+		return 0x81, nil
+	}
+
+	reservationID := binary.LittleEndian.Uint16(req.Data[0:2])
+	recordID := binary.LittleEndian.Uint16(req.Data[2:4])
+	if reservationID != s.sel.reservationID {
+		return ipmi.CompletionCodeReservationCanceled, nil
+	}
+
+	i := find(s.sel.records, recordID)
+	if i < 0 {
+		return ipmi.CompletionCodeRequestedDataNotPresent, nil
+	}
+	id := s.sel.records[i].id
+	s.sel.records = append(s.sel.records[:i], s.sel.records[i+1:]...)
+	s.sel.eraseTime = nowTimestamp()
+	s.sel.cancelReservation()
+
+	out := make([]byte, 2)
+	binary.LittleEndian.PutUint16(out, id)
+	return ipmi.CompletionCodeNormal, out
+}
+
+// see 31.9 Clear SEL Command
+//
+// The records are erased once the erasure is initiated, but the erasure is reported in progress
+// until the status is got once, so the clients have to poll for the completion.
+func (s *Server) clearSEL(sess *session, req *Request) (ipmi.CompletionCode, []byte) {
+	cc, out := s.clearRepo(&s.sel, req, ipmi.CompletionCodeNormal)
+	if len(s.sel.records) == 0 {
+		s.selOverflow = false
+	}
+	return cc, out
+}
+
+// see 30.5 Set Last Processed Event ID Command
+func (s *Server) setLastProcessedEventID(sess *session, req *Request) (ipmi.CompletionCode, []byte) {
+	if len(req.Data) < 3 {
+		return ipmi.CompletionCodeRequestDataLengthInvalid, nil
+	}
+
+	recordID := binary.LittleEndian.Uint16(req.Data[1:3])
+	if req.Data[0]&0x01 == 0x01 {
+		s.selBMCProcessedID = recordID
+	} else {
+		s.selSoftwareProcessedID = recordID
+	}
+	return ipmi.CompletionCodeNormal, nil
+}
+
+// see 30.6 Get Last Processed Event ID Command
+func (s *Server) getLastProcessedEventID(sess *session, req *Request) (ipmi.CompletionCode, []byte) {
+	lastRecordID := uint16(0xffff)
+	if n := len(s.sel.records); n > 0 {
+		lastRecordID = s.sel.records[n-1].id
+	}
+
+	out := make([]byte, 10)
+	binary.LittleEndian.PutUint32(out[0:], s.sel.addTime)
+	binary.LittleEndian.PutUint16(out[4:], lastRecordID)
+	binary.LittleEndian.PutUint16(out[6:], s.selSoftwareProcessedID)
+	binary.LittleEndian.PutUint16(out[8:], s.selBMCProcessedID)
+	return ipmi.CompletionCodeNormal, out
+}
@@ -0,0 +1,413 @@
+// Package ipmitest provides an in-process BMC for testing code built on ipmi.Client.
+//
+// The Server listens on a local UDP port and answers the BMC side of the
+// IPMI v1.5 (lan) and IPMI v2.0/RMCP+ (lanplus) protocols, so a client created by
+//
+//	ipmi.NewClient(server.Host(), server.Port(), username, password)
+//
+// can Connect and exchange commands without real hardware.
+// SDR, SEL, FRU and sensor data are served from in-memory fixtures.
+package ipmitest
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/bougou/go-ipmi"
+)
+
+const (
+	DefaultSessionTimeout = 60 * time.Second
+	DefaultMaxSessions    = 8
+)
+
+// Request is the IPMI request message received by the Server.
+type Request struct {
+	NetFn             ipmi.NetFn
+	Command           uint8
+	Data              []byte
+	ResponderAddr     uint8
+	ResponderLUN      uint8
+	RequesterAddr     uint8
+	RequesterLUN      uint8
+	RequesterSequence uint8
+}
+
+// HandlerFunc answers an IPMI request with a completion code and the response data.
+type HandlerFunc func(req *Request) (ipmi.CompletionCode, []byte)
+
+type handlerKey struct {
+	netFn ipmi.NetFn
+	cmd   uint8
+}
+
+func keyOf(cmd ipmi.Command) handlerKey {
+	return handlerKey{netFn: cmd.NetFn, cmd: cmd.ID}
+}
+
+// builtinHandler is the signature of the commands implemented by the Server itself.
+// The sess is nil for session-less requests.
+type builtinHandler func(s *Server, sess *session, req *Request) (ipmi.CompletionCode, []byte)
+
+// Server is a fake BMC serving RMCP/RMCP+ over UDP.
+// Configure the exported fields before calling Start.
+type Server struct {
+	Username string
+	Password string
+
+	// BMCKey is the Kg key, empty means "one-key" logins, that is the user password is used instead.
+	BMCKey []byte
+
+	MaxPrivilegeLevel ipmi.PrivilegeLevel
+
+	// CipherSuites lists the cipher suites accepted for RMCP+ sessions, in the order
+	// they are reported by Get Channel Cipher Suites.
+	CipherSuites []ipmi.CipherSuiteID
+
+	// AuthTypes lists the authentication types accepted for IPMI v1.5 sessions.
+	AuthTypes []ipmi.AuthType
+
+	GUID   [16]byte
+	Device DeviceInfo
+
+	// SessionTimeout is the inactivity timeout after which a session is discarded.
+	SessionTimeout time.Duration
+	MaxSessions    int
+
+	// SDRMaxReadBytes limits the bytes returned by a single Get SDR command,
+	// larger reads are rejected with completion code 0xCA. 0 means no limit.
+	SDRMaxReadBytes int
+
+	// MaxRequestDataSize limits the request data accepted by the Server, like the BMCs taking less than
+	// the max request size of the interface. The longer requests are rejected with completion code 0xC8.
+	// 0 means no limit.
+	MaxRequestDataSize int
+
+	// SDRModalUpdate makes the SDR Repository modal, it is only writable in the SDR Repository update mode.
+	SDRModalUpdate bool
+
+	// SELMaxEntries makes the SEL circular, the oldest records are overwritten by the records added
+	// once the SEL holds SELMaxEntries records, and the overflow flag is set. 0 means no limit.
+	SELMaxEntries int
+
+	// SOLHandler is called with the characters received from the remote console over SOL,
+	// the returned characters are sent back as the output of the serial controller.
+	// It is called while holding the Server lock, so it must not call the methods of the Server.
+	SOLHandler func(data []byte) []byte
+
+	// SOLAcceptLimit limits the characters accepted from one SOL packet,
+	// the remote console has to resend the rest. 0 means no limit.
+	SOLAcceptLimit int
+
+	// BridgeSeparateResponse makes the Server answer a Send Message request without the bridged response,
+	// which is sent in a separate message afterwards. By default the bridged response is embedded in
+	// the data of the Send Message response. It only takes effect for the Server which is started.
+	BridgeSeparateResponse bool
+
+	// UnknownSessionCompletionCode makes the Server answer the requests of unknown sessions with the
+	// completion code outside of a session, like the BMCs reporting the invalid session ID instead of
+	// silently dropping the packets. The encrypted requests can't be read and are still dropped. 0 means dropping all of them.
+	UnknownSessionCompletionCode ipmi.CompletionCode
+
+	// Latency delays the packets sent back, like a BMC on a slow link.
+	// The packets received meanwhile are still handled.
+	Latency time.Duration
+
+	// mu protects all the fields below
+	mu       sync.Mutex
+	conn     *net.UDPConn
+	addr     *net.UDPAddr
+	wg       sync.WaitGroup
+	handlers map[handlerKey]HandlerFunc
+	builtins map[handlerKey]builtinHandler
+	sessions map[uint32]*session
+	handle   uint8
+
+	// controllers are the management controllers behind the Server, see AddController
+	controllers map[controllerKey]*Server
+
+	// deferred holds the IPMI response messages to send after the response of the current request,
+	// they are sealed into outbox by the session handlers.
+	deferred [][]byte
+	outbox   [][]byte
+
+	sdr           repo
+	sdrUpdateMode bool
+	sel           repo
+	selUTCOffset  int16
+	selOverflow   bool
+	fru           map[uint8][]byte
+
+	// the Record IDs set by Set Last Processed Event ID
+	selSoftwareProcessedID uint16
+	selBMCProcessedID      uint16
+
+	sensors map[uint8]*Sensor
+}
+
+// NewServer creates a Server accepting the given credentials with administrator privilege.
+// The Server is not started.
+func NewServer(username string, password string) *Server {
+	s := &Server{
+		Username:          username,
+		Password:          password,
+		MaxPrivilegeLevel: ipmi.PrivilegeLevelAdministrator,
+		CipherSuites: []ipmi.CipherSuiteID{
+			ipmi.CipherSuiteID3,
+			ipmi.CipherSuiteID17,
+		},
+		AuthTypes: []ipmi.AuthType{
+			ipmi.AuthTypeMD5,
+		},
+		GUID:           [16]byte{0x44, 0x45, 0x4c, 0x4c, 0x30, 0x00, 0x10, 0x59, 0x80, 0x4e, 0xb8, 0xc0, 0x4f, 0x4e, 0x44, 0x32},
+		Device:         DefaultDeviceInfo,
+		SessionTimeout: DefaultSessionTimeout,
+		MaxSessions:    DefaultMaxSessions,
+
+		handlers:    make(map[handlerKey]HandlerFunc),
+		sessions:    make(map[uint32]*session),
+		controllers: make(map[controllerKey]*Server),
+		fru:         make(map[uint8][]byte),
+		sensors:     make(map[uint8]*Sensor),
+
+		selUTCOffset: ipmi.SELTimeUTCOffsetUnspecified,
+	}
+	s.builtins = defaultBuiltins()
+	return s
+}
+
+// Start listens on a random port of the loopback address and serves requests
+// in a background goroutine until Close is called.
+//
+// Start after Close listens on the same port again like a BMC after a reset, the sessions are discarded.
+// The packets sent to the port meanwhile are refused.
+func (s *Server) Start() error {
+	s.mu.Lock()
+	addr := s.addr
+	s.mu.Unlock()
+	if addr == nil {
+		addr = &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)}
+	}
+
+	conn, err := net.ListenUDP("udp", addr)
+	if err != nil {
+		return fmt.Errorf("listen udp failed, err: %w", err)
+	}
+
+	s.mu.Lock()
+	s.conn = conn
+	s.addr = conn.LocalAddr().(*net.UDPAddr)
+	for id := range s.sessions {
+		delete(s.sessions, id)
+	}
+	s.mu.Unlock()
+
+	s.wg.Add(1)
+	go s.serve(conn)
+	return nil
+}
+
+// Close stops the Server and waits for the serving goroutine to exit.
+func (s *Server) Close() error {
+	s.mu.Lock()
+	conn := s.conn
+	s.conn = nil
+	s.mu.Unlock()
+
+	if conn == nil {
+		return nil
+	}
+	err := conn.Close()
+	s.wg.Wait()
+	return err
+}
+
+// Host returns the IP address the Server listens on.
+func (s *Server) Host() string {
+	host, _, _ := net.SplitHostPort(s.Addr())
+	return host
+}
+
+// Port returns the UDP port the Server listens on.
+func (s *Server) Port() int {
+	_, port, _ := net.SplitHostPort(s.Addr())
+	p, _ := strconv.Atoi(port)
+	return p
+}
+
+// Addr returns the "host:port" address the Server listens on.
+func (s *Server) Addr() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.conn == nil {
+		return ""
+	}
+	return s.conn.LocalAddr().String()
+}
+
+// Handle registers the handler for the command, it takes precedence over the
+// command implemented by the Server itself. Pass a nil handler to remove it.
+//
+// The handler is called without holding the Server lock, so it can use the
+// fixture methods of the Server, but it must not call Handle.
+func (s *Server) Handle(cmd ipmi.Command, handler HandlerFunc) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if handler == nil {
+		delete(s.handlers, keyOf(cmd))
+		return
+	}
+	s.handlers[keyOf(cmd)] = handler
+}
+
+func (s *Server) serve(conn *net.UDPConn) {
+	defer s.wg.Done()
+
+	buf := make([]byte, 4096)
+	for {
+		n, addr, err := conn.ReadFromUDP(buf)
+		if err != nil {
+			return
+		}
+
+		msg := make([]byte, n)
+		copy(msg, buf[:n])
+
+		for _, out := range s.handlePacket(msg) {
+			if s.Latency > 0 {
+				out := out
+				time.AfterFunc(s.Latency, func() {
+					_, _ = conn.WriteToUDP(out, addr)
+				})
+				continue
+			}
+			if _, err := conn.WriteToUDP(out, addr); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// handlePacket returns the packets to send back for the received packet,
+// nil means the packet is silently discarded.
+func (s *Server) handlePacket(msg []byte) [][]byte {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.expireSessions()
+
+	rmcp := &ipmi.Rmcp{}
+	if err := rmcp.Unpack(msg); err != nil {
+		return nil
+	}
+	if rmcp.RmcpHeader.MessageClass != ipmi.MessageClassIPMI {
+		return nil
+	}
+
+	var out []byte
+	switch {
+	case rmcp.Session20 != nil:
+		out = s.handleSession20(rmcp.Session20)
+	case rmcp.Session15 != nil:
+		out = s.handleSession15(rmcp.Session15)
+	}
+
+	packets := s.outbox
+	s.deferred = nil
+	s.outbox = nil
+	if out == nil {
+		return nil
+	}
+	return append([][]byte{out}, packets...)
+}
+
+// handleIPMI decodes the IPMI request message, dispatches it and returns the packed IPMI response message.
+func (s *Server) handleIPMI(sess *session, msg []byte) []byte {
+	ipmiReq := &ipmi.IPMIRequest{}
+	if err := ipmiReq.Unpack(msg); err != nil {
+		return nil
+	}
+	if !ipmiReq.ValidChecksum() {
+		return nil
+	}
+
+	req := requestOf(ipmiReq)
+	cc, data := s.dispatch(sess, req)
+	return packResponse(req, cc, data)
+}
+
+func requestOf(ipmiReq *ipmi.IPMIRequest) *Request {
+	return &Request{
+		NetFn:             ipmiReq.NetFn,
+		Command:           ipmiReq.Command,
+		Data:              ipmiReq.CommandData,
+		ResponderAddr:     ipmiReq.ResponderAddr,
+		ResponderLUN:      ipmiReq.ResponderLUN,
+		RequesterAddr:     ipmiReq.RequesterAddr,
+		RequesterLUN:      ipmiReq.RequesterLUN,
+		RequesterSequence: ipmiReq.RequesterSequence,
+	}
+}
+
+// packResponse returns the packed IPMI response message for the request.
+func packResponse(req *Request, cc ipmi.CompletionCode, data []byte) []byte {
+	if cc != ipmi.CompletionCodeNormal {
+		data = nil
+	}
+
+	ipmiRes := &ipmi.IPMIResponse{
+		RequesterAddr:     req.RequesterAddr,
+		NetFn:             req.NetFn + 1,
+		RequestLUN:        req.RequesterLUN,
+		ResponderAddr:     req.ResponderAddr,
+		RequesterSequence: req.RequesterSequence,
+		ResponderLUN:      req.ResponderLUN,
+		Command:           req.Command,
+		CompletionCode:    uint8(cc),
+		Data:              data,
+	}
+	ipmiRes.ComputeChecksum()
+	return ipmiRes.Pack()
+}
+
+func (s *Server) dispatch(sess *session, req *Request) (ipmi.CompletionCode, []byte) {
+	key := handlerKey{netFn: req.NetFn, cmd: req.Command}
+
+	if sess == nil && !isSessionless(key) {
+		return ipmi.CompletionCodeCannotExecuteCommandSecurityRestrict, nil
+	}
+
+	if s.MaxRequestDataSize > 0 && len(req.Data) > s.MaxRequestDataSize {
+		return ipmi.CompletionCodeRequestDataLengthLimitExceeded, nil
+	}
+
+	if handler, ok := s.handlers[key]; ok {
+		s.mu.Unlock()
+		defer s.mu.Lock()
+		return handler(req)
+	}
+
+	if handler, ok := s.builtins[key]; ok {
+		return handler(s, sess, req)
+	}
+
+	return ipmi.CompletionCodeInvalidCommand, nil
+}
+
+// isSessionless reports whether the command is allowed outside of a session.
+func isSessionless(key handlerKey) bool {
+	switch key {
+	case
+		keyOf(ipmi.CommandGetChannelAuthCapabilities),
+		keyOf(ipmi.CommandGetChannelCipherSuites),
+		keyOf(ipmi.CommandGetSessionChallenge),
+		keyOf(ipmi.CommandGetSystemGUID):
+		return true
+	}
+	return false
+}
@@ -0,0 +1,220 @@
+package ipmitest
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/bougou/go-ipmi"
+)
+
+const (
+	testUsername = "admin"
+	testPassword = "secret"
+)
+
+// fullSensorSDR returns a Full Sensor Record of a linear temperature sensor (M = 1),
+// with readable thresholds and hysteresis.
+func fullSensorSDR(sensorNumber uint8, name string) []byte {
+	r := make([]byte, 48, 48+len(name))
+	r[2] = 0x51         // SDR version
+	r[3] = 0x01         // Full Sensor Record
+	r[5] = 0x20         // generator ID, BMC
+	r[7] = sensorNumber // sensor number
+	r[8] = 0x03         // entity ID, processor
+	r[9] = 0x01         // entity instance
+	r[10] = 0x7f        // sensor initialization
+	r[11] = 0x54        // hysteresis and thresholds readable
+	r[12] = uint8(ipmi.SensorTypeTemperature)
+	r[13] = uint8(ipmi.EventReadingTypeThreshold)
+	r[21] = uint8(ipmi.SensorUnitType_DegreesC)
+	r[24] = 0x01 // M
+	r[47] = 0xc0 | uint8(len(name))
+	return append(r, name...)
+}
+
+// newTestServer starts a Server with fixtures for one sensor, two SEL entries and the builtin FRU,
+// the configure funcs are applied before the Server is started.
+func newTestServer(t *testing.T, configure ...func(s *Server)) *Server {
+	t.Helper()
+
+	s := NewServer(testUsername, testPassword)
+	s.CipherSuites = []ipmi.CipherSuiteID{
+		ipmi.CipherSuiteID2, ipmi.CipherSuiteID3, ipmi.CipherSuiteID4, ipmi.CipherSuiteID5,
+		ipmi.CipherSuiteID7, ipmi.CipherSuiteID8, ipmi.CipherSuiteID9, ipmi.CipherSuiteID10,
+		ipmi.CipherSuiteID16, ipmi.CipherSuiteID17, ipmi.CipherSuiteID18, ipmi.CipherSuiteID19,
+	}
+	s.AuthTypes = []ipmi.AuthType{
+		ipmi.AuthTypeNone, ipmi.AuthTypeMD2, ipmi.AuthTypeMD5, ipmi.AuthTypePassword,
+	}
+
+	s.AddSDR(fullSensorSDR(0x01, "CPU Temp"))
+	s.SetSensor(0x01, Sensor{
+		Reading:            40,
+		ReadableThresholds: 0x3f,
+		UNC:                80, UCR: 90, UNR: 100,
+		LNC: 10, LCR: 5, LNR: 0,
+		PositiveHysteresis: 2,
+		NegativeHysteresis: 2,
+	})
+	s.AddSEL([]byte{0, 0, 0x02, 0x6d, 0x8e, 0x91, 0x5f, 0x20, 0x00, 0x04, 0x01, 0x01, 0x01, 0x57, 0x00, 0x00})
+	s.AddSEL([]byte{0, 0, 0x02, 0x6e, 0x8e, 0x91, 0x5f, 0x20, 0x00, 0x04, 0x01, 0x01, 0x01, 0x59, 0x00, 0x00})
+
+	// FRU Common Header only, no areas
+	s.SetFRU(0, []byte{0x01, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0xff})
+
+	for _, f := range configure {
+		f(s)
+	}
+
+	if err := s.Start(); err != nil {
+		t.Fatalf("Start failed, err: %s", err)
+	}
+	t.Cleanup(func() { s.Close() })
+	return s
+}
+
+func newTestClient(t *testing.T, s *Server, intf ipmi.Interface, password string) *ipmi.Client {
+	t.Helper()
+
+	c, err := ipmi.NewClient(s.Host(), s.Port(), testUsername, password)
+	if err != nil {
+		t.Fatalf("NewClient failed, err: %s", err)
+	}
+	return c.WithInterface(intf).WithTimeout(time.Second)
+}
+
+func exerciseClient(t *testing.T, ctx context.Context, c *ipmi.Client) {
+	t.Helper()
+
+	if _, err := c.GetDeviceID(ctx); err != nil {
+		t.Fatalf("GetDeviceID failed, err: %s", err)
+	}
+
+	sensors, err := c.GetSensors(ctx)
+	if err != nil {
+		t.Fatalf("GetSensors failed, err: %s", err)
+	}
+	if len(sensors) != 1 {
+		t.Fatalf("GetSensors returned %d sensors, want 1", len(sensors))
+	}
+	if sensors[0].Name != "CPU Temp" || sensors[0].Value != 40 {
+		t.Errorf("GetSensors returned sensor %q value %v, want \"CPU Temp\" value 40", sensors[0].Name, sensors[0].Value)
+	}
+
+	sels, err := c.GetSELEntries(ctx, 0)
+	if err != nil {
+		t.Fatalf("GetSELEntries failed, err: %s", err)
+	}
+	if len(sels) != 2 {
+		t.Errorf("GetSELEntries returned %d entries, want 2", len(sels))
+	}
+
+	if _, err := c.GetFRU(ctx, 0, "Builtin FRU"); err != nil {
+		t.Fatalf("GetFRU failed, err: %s", err)
+	}
+}
+
+func TestServer_Lanplus(t *testing.T) {
+	s := newTestServer(t)
+
+	for _, cipherSuiteID := range s.CipherSuites {
+		cipherSuiteID := cipherSuiteID
+		t.Run(fmt.Sprintf("cipher suite %d", cipherSuiteID), func(t *testing.T) {
+			ctx := context.Background()
+
+			c := newTestClient(t, s, ipmi.InterfaceLanplus, testPassword).WithCipherSuiteID(cipherSuiteID)
+			if err := c.Connect(ctx); err != nil {
+				t.Fatalf("Connect failed, err: %s", err)
+			}
+			defer c.Close(ctx)
+
+			exerciseClient(t, ctx, c)
+		})
+	}
+}
+
+func TestServer_Lan(t *testing.T) {
+	authTypes := []ipmi.AuthType{
+		ipmi.AuthTypeNone, ipmi.AuthTypeMD2, ipmi.AuthTypeMD5, ipmi.AuthTypePassword,
+	}
+
+	for _, authType := range authTypes {
+		authType := authType
+		t.Run(fmt.Sprintf("auth type %d", authType), func(t *testing.T) {
+			ctx := context.Background()
+
+			s := newTestServer(t, func(s *Server) {
+				s.AuthTypes = []ipmi.AuthType{authType}
+			})
+
+			c := newTestClient(t, s, ipmi.InterfaceLan, testPassword)
+			if err := c.Connect(ctx); err != nil {
+				t.Fatalf("Connect failed, err: %s", err)
+			}
+			defer c.Close(ctx)
+
+			exerciseClient(t, ctx, c)
+		})
+	}
+}
+
+func TestServer_WrongPassword(t *testing.T) {
+	s := newTestServer(t)
+
+	tests := []ipmi.Interface{ipmi.InterfaceLanplus, ipmi.InterfaceLan}
+	for _, intf := range tests {
+		t.Run(string(intf), func(t *testing.T) {
+			c := newTestClient(t, s, intf, "wrong")
+			if err := c.Connect(context.Background()); err == nil {
+				t.Errorf("Connect succeeded with a wrong password")
+			}
+		})
+	}
+}
+
+func TestServer_PartialSDRRead(t *testing.T) {
+	s := newTestServer(t, func(s *Server) {
+		s.SDRMaxReadBytes = 16
+	})
+
+	ctx := context.Background()
+	c := newTestClient(t, s, ipmi.InterfaceLanplus, testPassword)
+	if err := c.Connect(ctx); err != nil {
+		t.Fatalf("Connect failed, err: %s", err)
+	}
+	defer c.Close(ctx)
+
+	sdrs, err := c.GetSDRs(ctx)
+	if err != nil {
+		t.Fatalf("GetSDRs failed, err: %s", err)
+	}
+	if len(sdrs) != 1 || sdrs[0].SensorName() != "CPU Temp" {
+		t.Errorf("GetSDRs returned unexpected records %v", sdrs)
+	}
+}
+
+func TestServer_Handle(t *testing.T) {
+	s := newTestServer(t)
+	s.Handle(ipmi.CommandGetDeviceID, func(req *Request) (ipmi.CompletionCode, []byte) {
+		return ipmi.CompletionCodeNodeBusy, nil
+	})
+
+	ctx := context.Background()
+	c := newTestClient(t, s, ipmi.InterfaceLanplus, testPassword)
+	if err := c.Connect(ctx); err != nil {
+		t.Fatalf("Connect failed, err: %s", err)
+	}
+	defer c.Close(ctx)
+
+	_, err := c.GetDeviceID(ctx)
+	if err == nil {
+		t.Fatalf("GetDeviceID succeeded, want completion code %#02x", uint8(ipmi.CompletionCodeNodeBusy))
+	}
+
+	s.Handle(ipmi.CommandGetDeviceID, nil)
+	if _, err := c.GetDeviceID(ctx); err != nil {
+		t.Errorf("GetDeviceID failed after removing the handler, err: %s", err)
+	}
+}
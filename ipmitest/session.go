@@ -0,0 +1,451 @@
+package ipmitest
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"time"
+
+	"github.com/bougou/go-ipmi"
+)
+
+// session holds the state of an IPMI v1.5 or v2.0 session on the managed system side.
+type session struct {
+	// the managed system session id
+	id     uint32
+	handle uint8
+	v20    bool
+	active bool
+
+	username     string
+	privilege    ipmi.PrivilegeLevel
+	maxPrivilege ipmi.PrivilegeLevel
+
+	inbound    seqWindow
+	outSeq     uint32
+	lastActive time.Time
+
+	// IPMI v1.5
+	authType  ipmi.AuthType
+	challenge [16]byte
+
+	// IPMI v2.0, RMCP+
+	consoleID    uint32
+	authAlg      ipmi.AuthAlg
+	integrityAlg ipmi.IntegrityAlg
+	cryptAlg     ipmi.CryptAlg
+	rakp1Done    bool
+	role         uint8
+	consoleRand  [16]byte
+	bmcRand      [16]byte
+	sik          []byte
+	k1           []byte
+	k2           []byte
+	rc4EncryptIV [16]byte
+	rc4DecryptIV [16]byte
+
+	// SOL payload
+	solActive       bool
+	solInstance     uint8
+	solSeq          uint8
+	solLastSeq      uint8
+	solLastAccepted uint8
+}
+
+// seqWindowSize is the number of sequence numbers accepted below and above
+// the highest received one.
+// see 6.12.13 Session Sequence Number Tracking and Handling
+const seqWindowSize = 16
+
+// seqWindow tracks the inbound session sequence numbers, it rejects
+// zero, out-of-window and duplicated sequence numbers.
+type seqWindow struct {
+	highest uint32
+	// bit N is set if the sequence number (highest - N) has been received
+	seen uint32
+}
+
+func (w *seqWindow) accept(seq uint32) bool {
+	if seq == 0 {
+		return false
+	}
+
+	if seq > w.highest {
+		delta := seq - w.highest
+		if delta > seqWindowSize {
+			return false
+		}
+		w.seen = w.seen<<delta | 1
+		w.highest = seq
+		return true
+	}
+
+	delta := w.highest - seq
+	if delta >= seqWindowSize {
+		return false
+	}
+	if w.seen&(1<<delta) != 0 {
+		return false
+	}
+	w.seen |= 1 << delta
+	return true
+}
+
+func (s *Server) newSession() *session {
+	var id uint32
+	for id == 0 || s.sessions[id] != nil {
+		id = randomUint32()
+	}
+
+	s.handle += 1
+	if s.handle == 0 {
+		s.handle = 1
+	}
+
+	sess := &session{
+		id:           id,
+		handle:       s.handle,
+		privilege:    ipmi.PrivilegeLevelUser,
+		maxPrivilege: s.MaxPrivilegeLevel,
+		lastActive:   time.Now(),
+	}
+	s.sessions[id] = sess
+	return sess
+}
+
+func (s *Server) activeSessions() int {
+	count := 0
+	for _, sess := range s.sessions {
+		if sess.active {
+			count++
+		}
+	}
+	return count
+}
+
+// CloseSessions discards all the sessions, like they are closed by another administrator or lost in a BMC reset.
+// The packets of the discarded sessions are silently dropped, unless UnknownSessionCompletionCode is set.
+func (s *Server) CloseSessions() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for id := range s.sessions {
+		delete(s.sessions, id)
+	}
+}
+
+func (s *Server) expireSessions() {
+	now := time.Now()
+	for id, sess := range s.sessions {
+		if now.Sub(sess.lastActive) > s.SessionTimeout {
+			delete(s.sessions, id)
+		}
+	}
+}
+
+func (s *Server) handleSession15(s15 *ipmi.Session15) []byte {
+	hdr := s15.SessionHeader15
+
+	if hdr.SessionID == 0 {
+		if hdr.AuthType != ipmi.AuthTypeNone {
+			return nil
+		}
+		ipmiRes := s.handleIPMI(nil, s15.Payload)
+		if ipmiRes == nil {
+			return nil
+		}
+		return s.seal15(nil, ipmiRes)
+	}
+
+	sess := s.sessions[hdr.SessionID]
+	if sess == nil {
+		ipmiRes := s.answerUnknownSession(s15.Payload)
+		if ipmiRes == nil {
+			return nil
+		}
+		return s.seal15(nil, ipmiRes)
+	}
+	if sess.v20 {
+		return nil
+	}
+	if hdr.AuthType != sess.authType {
+		return nil
+	}
+
+	if hdr.AuthType != ipmi.AuthTypeNone {
+		input := &ipmi.AuthCodeMultiSessionInput{
+			Password:   s.Password,
+			SessionID:  hdr.SessionID,
+			SessionSeq: hdr.Sequence,
+			IPMIData:   s15.Payload,
+		}
+		if !hmac.Equal(input.AuthCode(hdr.AuthType), hdr.AuthCode) {
+			return nil
+		}
+	}
+
+	if !sess.active {
+		// only Activate Session is accepted in the pre-session stage
+		ipmiReq := &ipmi.IPMIRequest{}
+		if err := ipmiReq.Unpack(s15.Payload); err != nil {
+			return nil
+		}
+		if hdr.Sequence != 0 || ipmiReq.NetFn != ipmi.CommandActivateSession.NetFn || ipmiReq.Command != ipmi.CommandActivateSession.ID {
+			return nil
+		}
+	} else if !sess.inbound.accept(hdr.Sequence) {
+		return nil
+	}
+	sess.lastActive = time.Now()
+
+	ipmiRes := s.handleIPMI(sess, s15.Payload)
+	if ipmiRes == nil {
+		return nil
+	}
+
+	out := s.seal15(sess, ipmiRes)
+	for _, msg := range s.deferred {
+		s.outbox = append(s.outbox, s.seal15(sess, msg))
+	}
+	return out
+}
+
+// seal15 wraps the IPMI response message into an IPMI v1.5 session packet.
+func (s *Server) seal15(sess *session, payload []byte) []byte {
+	hdr := &ipmi.SessionHeader15{
+		AuthType:      ipmi.AuthTypeNone,
+		PayloadLength: uint8(len(payload)),
+	}
+
+	if sess != nil {
+		hdr.AuthType = sess.authType
+		hdr.SessionID = sess.id
+		if sess.active {
+			hdr.Sequence = sess.outSeq
+			sess.outSeq += 1
+		}
+	}
+
+	if hdr.AuthType != ipmi.AuthTypeNone {
+		input := &ipmi.AuthCodeMultiSessionInput{
+			Password:   s.Password,
+			SessionID:  hdr.SessionID,
+			SessionSeq: hdr.Sequence,
+			IPMIData:   payload,
+		}
+		hdr.AuthCode = input.AuthCode(hdr.AuthType)
+	}
+
+	rmcp := &ipmi.Rmcp{
+		RmcpHeader: ipmi.NewRmcpHeader(),
+		Session15: &ipmi.Session15{
+			SessionHeader15: hdr,
+			Payload:         payload,
+		},
+	}
+	return rmcp.Pack()
+}
+
+func (s *Server) handleSession20(s20 *ipmi.Session20) []byte {
+	hdr := s20.SessionHeader20
+
+	switch hdr.PayloadType {
+	case ipmi.PayloadTypeRmcpOpenSessionRequest:
+		return s.sealSetup(ipmi.PayloadTypeRmcpOpenSessionResponse, s.openSession(s20.SessionPayload))
+
+	case ipmi.PayloadTypeRAKPMessage1:
+		return s.sealSetup(ipmi.PayloadTypeRAKPMessage2, s.rakp1(s20.SessionPayload))
+
+	case ipmi.PayloadTypeRAKPMessage3:
+		return s.sealSetup(ipmi.PayloadTypeRAKPMessage4, s.rakp3(s20.SessionPayload))
+
+	case ipmi.PayloadTypeIPMI:
+		if hdr.SessionID == 0 {
+			ipmiRes := s.handleIPMI(nil, s20.SessionPayload)
+			if ipmiRes == nil {
+				return nil
+			}
+			return s.sealSetup(ipmi.PayloadTypeIPMI, ipmiRes)
+		}
+
+		if s.sessions[hdr.SessionID] == nil {
+			// the clients mark the payload encrypted even if the confidentiality algorithm is none,
+			// the requests which can't be read are dropped by answerUnknownSession
+			ipmiRes := s.answerUnknownSession(s20.SessionPayload)
+			if ipmiRes == nil {
+				return nil
+			}
+			return s.sealSetup(ipmi.PayloadTypeIPMI, ipmiRes)
+		}
+
+		sess, payload := s.open20(s20)
+		if sess == nil {
+			return nil
+		}
+
+		ipmiRes := s.handleIPMI(sess, payload)
+		if ipmiRes == nil {
+			return nil
+		}
+
+		out := s.seal20(sess, ipmi.PayloadTypeIPMI, ipmiRes)
+		for _, msg := range s.deferred {
+			s.outbox = append(s.outbox, s.seal20(sess, ipmi.PayloadTypeIPMI, msg))
+		}
+		return out
+
+	case ipmi.PayloadTypeSOL:
+		sess, payload := s.open20(s20)
+		if sess == nil {
+			return nil
+		}
+
+		solRes := s.handleSOL(sess, payload)
+		if solRes == nil {
+			return nil
+		}
+		return s.seal20(sess, ipmi.PayloadTypeSOL, solRes)
+	}
+
+	return nil
+}
+
+// answerUnknownSession returns the IPMI response message with UnknownSessionCompletionCode
+// for the request of an unknown session, or nil if the request should be dropped.
+// The request can only be read if it is not encrypted.
+func (s *Server) answerUnknownSession(msg []byte) []byte {
+	if s.UnknownSessionCompletionCode == ipmi.CompletionCodeNormal {
+		return nil
+	}
+
+	ipmiReq := &ipmi.IPMIRequest{}
+	if err := ipmiReq.Unpack(msg); err != nil || !ipmiReq.ValidChecksum() {
+		return nil
+	}
+	return packResponse(requestOf(ipmiReq), s.UnknownSessionCompletionCode, nil)
+}
+
+// open20 authenticates and decrypts the session packet, it returns the session and
+// the clear payload, or a nil session if the packet should be discarded.
+func (s *Server) open20(s20 *ipmi.Session20) (*session, []byte) {
+	hdr := s20.SessionHeader20
+
+	sess := s.sessions[hdr.SessionID]
+	if sess == nil || !sess.v20 || !sess.active {
+		return nil, nil
+	}
+
+	if sess.integrityAlg != ipmi.IntegrityAlg_None {
+		if !hdr.PayloadAuthenticated || s20.SessionTrailer == nil {
+			return nil, nil
+		}
+		trailer := s20.SessionTrailer
+		input := hdr.Pack()
+		input = append(input, s20.SessionPayload...)
+		input = append(input, trailer.IntegrityPAD...)
+		input = append(input, trailer.PadLength, trailer.NextHeader)
+		if !hmac.Equal(s.integrityAuthCode(sess, input), trailer.AuthCode) {
+			return nil, nil
+		}
+	}
+
+	if !sess.inbound.accept(hdr.Sequence) {
+		return nil, nil
+	}
+
+	payload := s20.SessionPayload
+	if hdr.PayloadEncrypted {
+		d, err := decryptPayload(sess, payload)
+		if err != nil {
+			return nil, nil
+		}
+		payload = d
+	} else if sess.cryptAlg != ipmi.CryptAlg_None {
+		return nil, nil
+	}
+
+	sess.lastActive = time.Now()
+	return sess, payload
+}
+
+// seal20 wraps the payload into an authenticated and encrypted RMCP+ session packet
+// according to the algorithms negotiated for the session.
+func (s *Server) seal20(sess *session, payloadType ipmi.PayloadType, payload []byte) []byte {
+	sess.outSeq += 1
+
+	hdr := &ipmi.SessionHeader20{
+		AuthType:             ipmi.AuthTypeRMCPPlus,
+		PayloadType:          payloadType,
+		PayloadEncrypted:     sess.cryptAlg != ipmi.CryptAlg_None,
+		PayloadAuthenticated: sess.integrityAlg != ipmi.IntegrityAlg_None,
+		SessionID:            sess.consoleID,
+		Sequence:             sess.outSeq,
+	}
+
+	sessionPayload := payload
+	if hdr.PayloadEncrypted {
+		e, err := encryptPayload(sess, payload)
+		if err != nil {
+			return nil
+		}
+		sessionPayload = e
+	}
+	hdr.PayloadLength = uint16(len(sessionPayload))
+
+	s20 := &ipmi.Session20{
+		SessionHeader20: hdr,
+		SessionPayload:  sessionPayload,
+	}
+
+	if hdr.PayloadAuthenticated {
+		hdrBytes := hdr.Pack()
+		padSize := trailerPadLength(hdrBytes, sessionPayload)
+		trailer := &ipmi.SessionTrailer{
+			IntegrityPAD: bytes.Repeat([]byte{0xff}, padSize),
+			PadLength:    uint8(padSize),
+			NextHeader:   0x07,
+		}
+		input := hdrBytes
+		input = append(input, sessionPayload...)
+		input = append(input, trailer.IntegrityPAD...)
+		input = append(input, trailer.PadLength, trailer.NextHeader)
+		trailer.AuthCode = s.integrityAuthCode(sess, input)
+		s20.SessionTrailer = trailer
+	}
+
+	rmcp := &ipmi.Rmcp{
+		RmcpHeader: ipmi.NewRmcpHeader(),
+		Session20:  s20,
+	}
+	return rmcp.Pack()
+}
+
+// sealSetup wraps the payload into an unauthenticated RMCP+ packet outside of a session.
+func (s *Server) sealSetup(payloadType ipmi.PayloadType, payload []byte) []byte {
+	if payload == nil {
+		return nil
+	}
+
+	rmcp := &ipmi.Rmcp{
+		RmcpHeader: ipmi.NewRmcpHeader(),
+		Session20: &ipmi.Session20{
+			SessionHeader20: &ipmi.SessionHeader20{
+				AuthType:      ipmi.AuthTypeRMCPPlus,
+				PayloadType:   payloadType,
+				PayloadLength: uint16(len(payload)),
+			},
+			SessionPayload: payload,
+		},
+	}
+	return rmcp.Pack()
+}
+
+// trailerPadLength returns the count of integrity pad bytes which makes the
+// data covered by the AuthCode a multiple of 4 bytes.
+func trailerPadLength(sessionHeader []byte, sessionPayload []byte) int {
+	// pad length field and next header field
+	length := len(sessionHeader) + len(sessionPayload) + 2
+	if length%4 == 0 {
+		return 0
+	}
+	return 4 - length%4
+}
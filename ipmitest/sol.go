@@ -0,0 +1,120 @@
+package ipmitest
+
+import (
+	"encoding/binary"
+	"net"
+
+	"github.com/bougou/go-ipmi"
+)
+
+// solPayloadSize is the max SOL payload size, including the SOL payload header,
+// reported for both directions by Activate Payload.
+const solPayloadSize = 200
+
+// see 24.1 Activate Payload Command
+func (s *Server) activatePayload(sess *session, req *Request) (ipmi.CompletionCode, []byte) {
+	if len(req.Data) < 6 {
+		return ipmi.CompletionCodeRequestDataLengthInvalid, nil
+	}
+	if !sess.v20 {
+		return ipmi.CompletionCodeCannotExecuteCommandNotSupported, nil
+	}
+
+	payloadType := ipmi.PayloadType(req.Data[0] & 0x3f)
+	instance := req.Data[1] & 0x0f
+	if payloadType != ipmi.PayloadTypeSOL {
+		return 0x81, nil
+	}
+	if instance != 1 {
+		return ipmi.CompletionCodeParameterOutOfRange, nil
+	}
+
+	for _, v := range s.sessions {
+		if v.solActive && v.solInstance == instance {
+			return 0x80, nil
+		}
+	}
+
+	sess.solActive = true
+	sess.solInstance = instance
+	sess.solSeq = 0
+	sess.solLastSeq = 0
+
+	out := make([]byte, 12)
+	binary.LittleEndian.PutUint16(out[4:], solPayloadSize)
+	binary.LittleEndian.PutUint16(out[6:], solPayloadSize)
+	binary.LittleEndian.PutUint16(out[8:], uint16(s.conn.LocalAddr().(*net.UDPAddr).Port))
+	binary.LittleEndian.PutUint16(out[10:], 0xffff)
+	return ipmi.CompletionCodeNormal, out
+}
+
+// see 24.2 Deactivate Payload Command
+func (s *Server) deactivatePayload(sess *session, req *Request) (ipmi.CompletionCode, []byte) {
+	if len(req.Data) < 6 {
+		return ipmi.CompletionCodeRequestDataLengthInvalid, nil
+	}
+
+	payloadType := ipmi.PayloadType(req.Data[0] & 0x3f)
+	instance := req.Data[1] & 0x0f
+	if payloadType != ipmi.PayloadTypeSOL {
+		return 0x81, nil
+	}
+
+	for _, v := range s.sessions {
+		if v.solActive && v.solInstance == instance {
+			v.solActive = false
+			return ipmi.CompletionCodeNormal, nil
+		}
+	}
+	return 0x80, nil
+}
+
+// handleSOL accepts the characters of the SOL packet and returns the ACK packet,
+// the output of SOLHandler is carried in the same packet.
+// see 15.9 SOL Payload Data Format
+func (s *Server) handleSOL(sess *session, payload []byte) []byte {
+	if !sess.solActive {
+		return nil
+	}
+
+	packet := &ipmi.SOLPacket{}
+	if err := packet.Unpack(payload); err != nil {
+		return nil
+	}
+	if packet.Sequence == 0 {
+		// ACK-only packet for the output sent to the remote console
+		return nil
+	}
+
+	ack := &ipmi.SOLPacket{
+		AckSequence: packet.Sequence,
+	}
+
+	if packet.Sequence == sess.solLastSeq {
+		// the remote console retried the packet as the ACK was lost
+		ack.AcceptedCharacterCount = sess.solLastAccepted
+		return ack.Pack()
+	}
+
+	accepted := len(packet.Data)
+	if s.SOLAcceptLimit > 0 && accepted > s.SOLAcceptLimit {
+		accepted = s.SOLAcceptLimit
+	}
+	sess.solLastSeq = packet.Sequence
+	sess.solLastAccepted = uint8(accepted)
+	ack.AcceptedCharacterCount = uint8(accepted)
+
+	if s.SOLHandler != nil && accepted > 0 {
+		output := s.SOLHandler(packet.Data[:accepted])
+		if max := solPayloadSize - ipmi.SOLPacketHeaderSize; len(output) > max {
+			output = output[:max]
+		}
+		if len(output) > 0 {
+			sess.solSeq = sess.solSeq%15 + 1
+			ack.Sequence = sess.solSeq
+			ack.Data = output
+		}
+	}
+
+	return ack.Pack()
+}
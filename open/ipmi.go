@@ -81,6 +81,48 @@ type IPMI_ADDR struct {
 	Data     [IPMI_MAX_ADDR_SIZE]byte // Addr Data
 }
 
+// addrLen returns the size of the address struct of the address type.
+func addrLen(addrType int32) int {
+	switch addrType {
+	case IPMI_SYSTEM_INTERFACE_ADDR_TYPE:
+		return int(unsafe.Sizeof(IPMI_SYSTEM_INTERFACE_ADDR{}))
+	case IPMI_IPMB_ADDR_TYPE, IPMI_IPMB_BROADCAST_ADDR_TYPE:
+		return int(unsafe.Sizeof(IPMI_IPMB_ADDR{}))
+	case IPMI_IPMB_DIRECT_ADDR_TYPE:
+		return int(unsafe.Sizeof(IPMI_IPMB_DIRECT_ADDR{}))
+	case IPMI_LAN_ADDR_TYPE:
+		return int(unsafe.Sizeof(IPMI_LAN_ADDR{}))
+	}
+	return int(unsafe.Sizeof(IPMI_ADDR{}))
+}
+
+// Len returns the length of the address passed to the driver.
+func (addr *IPMI_ADDR) Len() int {
+	return addrLen(addr.AddrType)
+}
+
+// NewSystemInterfaceAddr returns the address of the BMC on the system interface.
+func NewSystemInterfaceAddr(lun uint8) *IPMI_ADDR {
+	addr := &IPMI_ADDR{
+		AddrType: IPMI_SYSTEM_INTERFACE_ADDR_TYPE,
+		Channel:  IPMI_BMC_CHANNEL,
+	}
+	addr.Data[0] = lun & 0x03
+	return addr
+}
+
+// NewIPMBAddr returns the address of the controller at slaveAddr on the IPMB channel,
+// the driver bridges the request through the BMC with the Send Message command.
+func NewIPMBAddr(channel uint8, slaveAddr uint8, lun uint8) *IPMI_ADDR {
+	addr := &IPMI_ADDR{
+		AddrType: IPMI_IPMB_ADDR_TYPE,
+		Channel:  uint16(channel),
+	}
+	addr.Data[0] = slaveAddr
+	addr.Data[1] = lun & 0x03
+	return addr
+}
+
 const IPMI_SYSTEM_INTERFACE_ADDR_TYPE = 0x0c
 
 // IPMI_SYSTEM_INTERFACE_ADDR holds addr data of addr type IPMI_SYSTEM_INTERFACE_ADDR_TYPE.
@@ -157,7 +199,7 @@ func (msg *IPMI_MSG) MsgData() ([]byte, error) {
 
 // unsafe.Sizeof of IPMI_REQ is 8+8(4+4)+8+16 = 40.
 type IPMI_REQ struct {
-	Addr    *IPMI_SYSTEM_INTERFACE_ADDR
+	Addr    *IPMI_ADDR
 	AddrLen int
 
 	// The sequence number for the message.  This
@@ -172,7 +214,7 @@ type IPMI_REQ struct {
 // unsafe.Sizeof of IPMI_RECV is 8(4+4)+8+8(4+4)+8+16 = 48.
 type IPMI_RECV struct {
 	RecvType int
-	Addr     *IPMI_SYSTEM_INTERFACE_ADDR
+	Addr     *IPMI_ADDR
 	AddrLen  int
 	MsgID    int64
 	Msg      IPMI_MSG
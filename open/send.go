@@ -21,11 +21,29 @@ func GetRecv(fd uintptr, op uintptr, recv *IPMI_RECV) error {
 	return err
 }
 
-func SendCommand(file *os.File, req *IPMI_REQ, timeout time.Duration) ([]byte, error) {
-	if timeout == 0 {
-		timeout = IPMI_FILE_READ_TIMEOUT
-	}
+// Message is a message received from the driver, which is
+// a response to a request sent before, an asynchronous event or a command sent by other entities.
+type Message struct {
+	// RecvType is one of IPMI_RESPONSE_RECV_TYPE, IPMI_ASYNC_EVENT_RECV_TYPE,
+	// IPMI_CMD_RECV_TYPE, IPMI_RESPONSE_RESPONSE_TYPE or IPMI_OEM_RECV_TYPE.
+	RecvType int
+
+	// Addr is the address of the sender.
+	Addr IPMI_ADDR
+
+	// MsgID is the msg id of the request for responses,
+	// or the sequence which must be used for the response to the received command.
+	MsgID int64
+
+	NetFn uint8
+	Cmd   uint8
 
+	// Data starts with the completion code for responses.
+	Data []byte
+}
+
+// SendRequest passes the request to the driver, it does not wait for the response.
+func SendRequest(file *os.File, req *IPMI_REQ) error {
 	fd := file.Fd()
 
 	for {
@@ -33,22 +51,28 @@ func SendCommand(file *os.File, req *IPMI_REQ, timeout time.Duration) ([]byte, e
 		case err == syscall.EINTR:
 			continue
 		case err != nil:
-			return nil, fmt.Errorf("SetReq failed, err: %w", err)
+			return fmt.Errorf("SetReq failed, err: %w", err)
 		}
 		break
 	}
+	return nil
+}
 
+// ReceiveMessage waits for the next message queued by the driver.
+// The wait is bounded by the read deadline of the file, no deadline means waiting forever.
+func ReceiveMessage(file *os.File) (*Message, error) {
 	recvBuf := make([]byte, IPMI_BUF_SIZE)
+	// the driver fills the address of the sender
+	recvAddr := &IPMI_ADDR{}
 	recv := &IPMI_RECV{
-		Addr:    req.Addr,
-		AddrLen: req.AddrLen,
+		Addr:    recvAddr,
+		AddrLen: int(unsafe.Sizeof(*recvAddr)),
 		Msg: IPMI_MSG{
 			Data:    &recvBuf[0],
 			DataLen: IPMI_BUF_SIZE,
 		},
 	}
 
-	var result []byte
 	var rerr error
 
 	readMsgFunc := func(fd uintptr) bool {
@@ -57,16 +81,9 @@ func SendCommand(file *os.File, req *IPMI_REQ, timeout time.Duration) ([]byte, e
 			return false
 		}
 
-		if recv.MsgID != req.MsgID {
-			rerr = fmt.Errorf("received msg id not match")
-			return false
-		}
-
 		if recv.Msg.DataLen >= IPMI_BUF_SIZE {
 			rerr = fmt.Errorf("received data length longer than buf size: %d > %d", recv.Msg.DataLen, IPMI_BUF_SIZE)
 		} else {
-			// recvBuf[0] is completion code.
-			result = recvBuf[:recv.Msg.DataLen:recv.Msg.DataLen]
 			rerr = nil
 		}
 		return true
@@ -76,12 +93,101 @@ func SendCommand(file *os.File, req *IPMI_REQ, timeout time.Duration) ([]byte, e
 	if err != nil {
 		return nil, fmt.Errorf("failed to get syscall conn from file: %s", err)
 	}
+	if err := conn.Read(readMsgFunc); err != nil {
+		return nil, fmt.Errorf("failed to read from syscall conn: %w", err)
+	}
+	if rerr != nil {
+		return nil, rerr
+	}
+
+	return &Message{
+		RecvType: recv.RecvType,
+		Addr:     *recvAddr,
+		MsgID:    recv.MsgID,
+		NetFn:    recv.Msg.NetFn,
+		Cmd:      recv.Msg.Cmd,
+		Data:     recvBuf[:recv.Msg.DataLen:recv.Msg.DataLen],
+	}, nil
+}
+
+// SendCommand sends the request and waits for its response.
+// The events and commands received while waiting are dropped.
+func SendCommand(file *os.File, req *IPMI_REQ, timeout time.Duration) ([]byte, error) {
+	if timeout == 0 {
+		timeout = IPMI_FILE_READ_TIMEOUT
+	}
+
+	if err := SendRequest(file, req); err != nil {
+		return nil, err
+	}
+
 	if err := file.SetReadDeadline(time.Now().Add(timeout)); err != nil {
 		return nil, fmt.Errorf("failed to set read deadline on file: %s", err)
 	}
-	if err := conn.Read(readMsgFunc); err != nil {
-		return nil, fmt.Errorf("failed to read from syscall conn: %s", err)
+
+	for {
+		msg, err := ReceiveMessage(file)
+		if err != nil {
+			return nil, err
+		}
+
+		if msg.RecvType != IPMI_RESPONSE_RECV_TYPE || msg.MsgID != req.MsgID {
+			continue
+		}
+		// msg.Data[0] is completion code.
+		return msg.Data, nil
+	}
+}
+
+// SendResponse sends the response to the command received in msg,
+// data starts with the completion code.
+func SendResponse(file *os.File, msg *Message, data []byte) error {
+	var dataPtr *byte
+	if len(data) > 0 {
+		dataPtr = &data[0]
+	}
+
+	addr := msg.Addr
+	req := &IPMI_REQ{
+		Addr:    &addr,
+		AddrLen: addr.Len(),
+		MsgID:   msg.MsgID,
+		Msg: IPMI_MSG{
+			NetFn:   msg.NetFn | 0x01, // response NetFn
+			Cmd:     msg.Cmd,
+			Data:    dataPtr,
+			DataLen: uint16(len(data)),
+		},
+	}
+	return SendRequest(file, req)
+}
+
+// RegisterForCommand asks the driver to deliver the commands of netFn and cmd received by the BMC
+// to the file, they are read by ReceiveMessage with RecvType IPMI_CMD_RECV_TYPE.
+// Only one user can register for a command.
+func RegisterForCommand(file *os.File, netFn uint8, cmd uint8) error {
+	cmdSpec := &IPMI_CMDSPEC{
+		NetFn: netFn,
+		Cmd:   cmd,
 	}
+	err := IOCTL(file.Fd(), IPMICTL_REGISTER_FOR_CMD, uintptr(unsafe.Pointer(cmdSpec)))
+	runtime.KeepAlive(cmdSpec)
+	if err != nil {
+		return fmt.Errorf("register for command failed, err: %w", err)
+	}
+	return nil
+}
 
-	return result, rerr
+// UnregisterForCommand cancels the registration of RegisterForCommand.
+func UnregisterForCommand(file *os.File, netFn uint8, cmd uint8) error {
+	cmdSpec := &IPMI_CMDSPEC{
+		NetFn: netFn,
+		Cmd:   cmd,
+	}
+	err := IOCTL(file.Fd(), IPMICTL_UNREGISTER_FOR_CMD, uintptr(unsafe.Pointer(cmdSpec)))
+	runtime.KeepAlive(cmdSpec)
+	if err != nil {
+		return fmt.Errorf("unregister for command failed, err: %w", err)
+	}
+	return nil
 }
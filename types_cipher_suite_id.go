@@ -180,3 +180,9 @@ var preferredCiphers = []CipherSuiteID{
 	// CipherSuiteID13,
 	// CipherSuiteID14,
 }
+
+// Algorithms returns the authentication, integrity and confidentiality algorithms
+// of the standard cipher suite.
+func (cipherSuiteID CipherSuiteID) Algorithms() (AuthAlg, IntegrityAlg, CryptAlg, error) {
+	return getCipherSuiteAlgorithms(cipherSuiteID)
+}
@@ -0,0 +1,358 @@
+package ipmi
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// EntityKey identifies an entity of the platform.
+//
+// 39.1 System- and Device-relative Entity Instance Values
+// A device-relative entity instance is only unique behind the management controller,
+// so DeviceAddress and Channel of the management controller are set for device-relative instances,
+// they are 0 for system-relative instances.
+type EntityKey struct {
+	EntityID       EntityID
+	EntityInstance EntityInstance
+
+	DeviceAddress uint8 // 7-bit I2C slave address in bits [7:1]
+	Channel       uint8
+}
+
+// newEntityKey returns the key of the entity, deviceAddress and channel are discarded for system-relative instances.
+func newEntityKey(id EntityID, instance EntityInstance, deviceAddress uint8, channel uint8) EntityKey {
+	key := EntityKey{
+		EntityID:       id,
+		EntityInstance: instance & 0x7f,
+	}
+	if isEntityInstanceDeviceRelative(key.EntityInstance) {
+		key.DeviceAddress = deviceAddress & 0xfe
+		key.Channel = channel & 0x0f
+	}
+	return key
+}
+
+func (key EntityKey) String() string {
+	if isEntityInstanceDeviceRelative(key.EntityInstance) {
+		return fmt.Sprintf("%d.%d@%#02x/%d", uint8(key.EntityID), uint8(key.EntityInstance), key.DeviceAddress, key.Channel)
+	}
+	return fmt.Sprintf("%d.%d", uint8(key.EntityID), uint8(key.EntityInstance))
+}
+
+func (key EntityKey) less(other EntityKey) bool {
+	if key.EntityID != other.EntityID {
+		return key.EntityID < other.EntityID
+	}
+	if key.EntityInstance != other.EntityInstance {
+		return key.EntityInstance < other.EntityInstance
+	}
+	if key.DeviceAddress != other.DeviceAddress {
+		return key.DeviceAddress < other.DeviceAddress
+	}
+	return key.Channel < other.Channel
+}
+
+// EntityNode is an entity of the EntityTree.
+type EntityNode struct {
+	EntityKey
+
+	// Children are the entities contained by this entity according to the entity association records.
+	Children []*EntityNode
+
+	// Sensors holds the Full, Compact and Event-Only records of the sensors associated with the entity.
+	Sensors []*SDR
+
+	// Locators holds the Generic, FRU and Management Controller Device Locator records of the entity.
+	Locators []*SDR
+
+	// contained is set if the entity is contained by any other entity, so it is not a root of the tree
+	contained bool
+}
+
+// Walk calls fn for the node and all its descendants, depth-first. Every node is visited once,
+// even if it is contained by more than one entity.
+func (node *EntityNode) Walk(fn func(node *EntityNode, depth int)) {
+	node.walk(fn, 0, make(map[*EntityNode]bool))
+}
+
+func (node *EntityNode) walk(fn func(node *EntityNode, depth int), depth int, visited map[*EntityNode]bool) {
+	if visited[node] {
+		return
+	}
+	visited[node] = true
+
+	fn(node, depth)
+	for _, child := range node.Children {
+		child.walk(fn, depth+1, visited)
+	}
+}
+
+// AllSensors returns the sensor records of the entity and all its descendants.
+func (node *EntityNode) AllSensors() []*SDR {
+	out := make([]*SDR, 0)
+	node.Walk(func(node *EntityNode, depth int) {
+		out = append(out, node.Sensors...)
+	})
+	return out
+}
+
+// contains reports whether other is the node itself or one of its descendants.
+func (node *EntityNode) contains(other *EntityNode) bool {
+	found := false
+	node.Walk(func(node *EntityNode, depth int) {
+		if node == other {
+			found = true
+		}
+	})
+	return found
+}
+
+// EntityTree is the platform entity hierarchy (e.g. system board, processors, DIMMs)
+// built from the Entity Association and Device-relative Entity Association records,
+// with the sensors and the device locators attached to their entities.
+//
+// see 39. Using Entity IDs, 43.4 and 43.5
+type EntityTree struct {
+	// Roots are the entities not contained by any other entity.
+	Roots []*EntityNode
+
+	nodes map[EntityKey]*EntityNode
+}
+
+// NewEntityTree builds the EntityTree from the SDR records.
+// Linked association records of the same container entity are merged, the association records which
+// would make an entity contain itself are ignored.
+func NewEntityTree(sdrs []*SDR) *EntityTree {
+	tree := &EntityTree{
+		nodes: make(map[EntityKey]*EntityNode),
+	}
+
+	for _, sdr := range sdrs {
+		if sdr == nil || sdr.RecordHeader == nil {
+			continue
+		}
+
+		switch sdr.RecordHeader.RecordType {
+		case SDRRecordTypeEntityAssociation:
+			tree.addEntityAssociation(sdr.EntityAssociation)
+		case SDRRecordTypeDeviceRelativeEntityAssociation:
+			tree.addDeviceRelativeEntityAssociation(sdr.DeviceRelative)
+		}
+	}
+
+	for _, sdr := range sdrs {
+		if sdr == nil || sdr.RecordHeader == nil {
+			continue
+		}
+
+		switch sdr.RecordHeader.RecordType {
+		case SDRRecordTypeFullSensor:
+			s := sdr.Full
+			node := tree.node(newEntityKey(s.SensorEntityID, s.SensorEntityInstance, s.GeneratorID.OwnerID(), s.GeneratorID.ChannelNumber()))
+			node.Sensors = append(node.Sensors, sdr)
+		case SDRRecordTypeCompactSensor:
+			s := sdr.Compact
+			node := tree.node(newEntityKey(s.SensorEntityID, s.SensorEntityInstance, s.GeneratorID.OwnerID(), s.GeneratorID.ChannelNumber()))
+			node.Sensors = append(node.Sensors, sdr)
+		case SDRRecordTypeEventOnly:
+			s := sdr.EventOnly
+			node := tree.node(newEntityKey(s.SensorEntityID, s.SensorEntityInstance, s.GeneratorID.OwnerID(), s.GeneratorID.ChannelNumber()))
+			node.Sensors = append(node.Sensors, sdr)
+		case SDRRecordTypeGenericLocator:
+			s := sdr.GenericDeviceLocator
+			node := tree.node(newEntityKey(EntityID(s.EntityID), EntityInstance(s.EntityInstance), s.DeviceAccessAddress, s.ChannelNumber))
+			node.Locators = append(node.Locators, sdr)
+		case SDRRecordTypeFRUDeviceLocator:
+			s := sdr.FRUDeviceLocator
+			node := tree.node(newEntityKey(EntityID(s.FRUEntityID), EntityInstance(s.FRUEntityInstance), s.DeviceAccessAddress, s.ChannelNumber))
+			node.Locators = append(node.Locators, sdr)
+		case SDRRecordTypeManagementControllerDeviceLocator:
+			s := sdr.MgmtControllerDeviceLocator
+			node := tree.node(newEntityKey(EntityID(s.EntityID), EntityInstance(s.EntityInstance), s.DeviceSlaveAddress, s.ChannelNumber))
+			node.Locators = append(node.Locators, sdr)
+		}
+	}
+
+	for _, node := range tree.nodes {
+		sort.SliceStable(node.Children, func(i, j int) bool {
+			return node.Children[i].less(node.Children[j].EntityKey)
+		})
+		if !node.contained {
+			tree.Roots = append(tree.Roots, node)
+		}
+	}
+	sort.Slice(tree.Roots, func(i, j int) bool {
+		return tree.Roots[i].less(tree.Roots[j].EntityKey)
+	})
+
+	return tree
+}
+
+// node returns the node of the entity, it is created if not exists.
+func (tree *EntityTree) node(key EntityKey) *EntityNode {
+	node, ok := tree.nodes[key]
+	if !ok {
+		node = &EntityNode{EntityKey: key}
+		tree.nodes[key] = node
+	}
+	return node
+}
+
+// contain makes the container entity contain the entity.
+func (tree *EntityTree) contain(container *EntityNode, key EntityKey) {
+	if key.EntityID == 0 {
+		// unused contained entity field
+		return
+	}
+
+	child := tree.node(key)
+	if child.contains(container) {
+		return
+	}
+	for _, c := range container.Children {
+		if c == child {
+			return
+		}
+	}
+	container.Children = append(container.Children, child)
+	child.contained = true
+}
+
+// containRange makes the container entity contain the entities of the range from start to end.
+func (tree *EntityTree) containRange(container *EntityNode, start EntityKey, end EntityKey) {
+	if start.EntityID == 0 {
+		return
+	}
+	if end.EntityID != start.EntityID || end.EntityInstance < start.EntityInstance {
+		// malformed range, take the start entity only
+		tree.contain(container, start)
+		return
+	}
+	for instance := start.EntityInstance; instance <= end.EntityInstance; instance++ {
+		tree.contain(container, newEntityKey(start.EntityID, instance, start.DeviceAddress, start.Channel))
+	}
+}
+
+func (tree *EntityTree) addEntityAssociation(s *SDREntityAssociation) {
+	if s == nil {
+		return
+	}
+
+	// the device-relative instances of entity association records are relative to the BMC
+	const bmc = uint8(GeneratorBMC)
+
+	container := tree.node(newEntityKey(EntityID(s.ContainerEntityID), EntityInstance(s.ContainerEntityInstance), bmc, 0))
+	contained := []EntityKey{
+		newEntityKey(EntityID(s.ContainedEntity1ID), EntityInstance(s.ContainedEntity1Instance), bmc, 0),
+		newEntityKey(EntityID(s.ContainedEntity2ID), EntityInstance(s.ContainedEntity2Instance), bmc, 0),
+		newEntityKey(EntityID(s.ContainedEntity3ID), EntityInstance(s.ContainedEntity3Instance), bmc, 0),
+		newEntityKey(EntityID(s.ContainedEntity4ID), EntityInstance(s.ContainedEntity4Instance), bmc, 0),
+	}
+	tree.addContained(container, s.ContainedEntitiesAsRange, contained)
+}
+
+func (tree *EntityTree) addDeviceRelativeEntityAssociation(s *SDRDeviceRelative) {
+	if s == nil {
+		return
+	}
+
+	container := tree.node(newEntityKey(EntityID(s.ContainerEntityID), EntityInstance(s.ContainerEntityInstance),
+		s.ContainerEntityDeviceAddress, s.ContainerEntityDeviceChannel>>4))
+	contained := []EntityKey{
+		newEntityKey(EntityID(s.ContainedEntity1ID), EntityInstance(s.ContainedEntity1Instance),
+			s.ContainedEntity1DeviceAddress, s.ContainedEntity1DeviceChannel>>4),
+		newEntityKey(EntityID(s.ContainedEntity2ID), EntityInstance(s.ContainedEntity2Instance),
+			s.ContainedEntity2DeviceAddress, s.ContainedEntity2DeviceChannel>>4),
+		newEntityKey(EntityID(s.ContainedEntity3ID), EntityInstance(s.ContainedEntity3Instance),
+			s.ContainedEntity3DeviceAddress, s.ContainedEntity3DeviceChannel>>4),
+		newEntityKey(EntityID(s.ContainedEntity4ID), EntityInstance(s.ContainedEntity4Instance),
+			s.ContainedEntity4DeviceAddress, s.ContainedEntity4DeviceChannel>>4),
+	}
+	tree.addContained(container, s.ContainedEntitiesAsRange, contained)
+}
+
+// addContained adds the four contained entities of an association record,
+// which are two ranges (entity 1 to 2, entity 3 to 4) if asRange is set.
+func (tree *EntityTree) addContained(container *EntityNode, asRange bool, contained []EntityKey) {
+	if asRange {
+		tree.containRange(container, contained[0], contained[1])
+		tree.containRange(container, contained[2], contained[3])
+		return
+	}
+	for _, key := range contained {
+		tree.contain(container, key)
+	}
+}
+
+// Node returns the node of the entity, nil if the entity is not found.
+func (tree *EntityTree) Node(key EntityKey) *EntityNode {
+	return tree.nodes[key]
+}
+
+// Find returns the nodes of the entities with the entity ID, sorted by the entity keys.
+// If instance is not nil, only the entities of the instance are returned.
+func (tree *EntityTree) Find(id EntityID, instance *EntityInstance) []*EntityNode {
+	out := make([]*EntityNode, 0)
+	for key, node := range tree.nodes {
+		if key.EntityID != id {
+			continue
+		}
+		if instance != nil && key.EntityInstance != *instance {
+			continue
+		}
+		out = append(out, node)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		return out[i].less(out[j].EntityKey)
+	})
+	return out
+}
+
+// Format returns the indented tree of the entities, with the sensors and device locators of each entity.
+func (tree *EntityTree) Format() string {
+	var b strings.Builder
+	for _, root := range tree.Roots {
+		b.WriteString(root.Format())
+	}
+	return b.String()
+}
+
+// Format returns the indented subtree of the entity, with the sensors and device locators of each entity.
+func (node *EntityNode) Format() string {
+	var b strings.Builder
+	node.Walk(func(node *EntityNode, depth int) {
+		indent := strings.Repeat("    ", depth)
+		b.WriteString(fmt.Sprintf("%s%s (%s)\n", indent, node.EntityID.String(), node.EntityKey))
+
+		for _, sdr := range node.Locators {
+			b.WriteString(fmt.Sprintf("%s  - %s: %s\n", indent, sdr.RecordHeader.RecordType, entityLocatorName(sdr)))
+		}
+		for _, sdr := range node.Sensors {
+			b.WriteString(fmt.Sprintf("%s  - Sensor: %s (%#02x)\n", indent, sdr.SensorName(), uint8(sdr.SensorNumber())))
+		}
+	})
+	return b.String()
+}
+
+func entityLocatorName(sdr *SDR) string {
+	switch sdr.RecordHeader.RecordType {
+	case SDRRecordTypeGenericLocator:
+		return string(sdr.GenericDeviceLocator.DeviceIDString)
+	case SDRRecordTypeFRUDeviceLocator:
+		return string(sdr.FRUDeviceLocator.DeviceIDBytes)
+	case SDRRecordTypeManagementControllerDeviceLocator:
+		return string(sdr.MgmtControllerDeviceLocator.DeviceIDBytes)
+	}
+	return ""
+}
+
+// GetEntityTree reads all the SDR records and builds the platform entity tree, see EntityTree.
+func (c *Client) GetEntityTree(ctx context.Context) (*EntityTree, error) {
+	sdrs, err := c.GetSDRs(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("GetSDRs failed, err: %w", err)
+	}
+	return NewEntityTree(sdrs), nil
+}
@@ -0,0 +1,106 @@
+package ipmi
+
+import (
+	"testing"
+)
+
+func TestNewEntityTree(t *testing.T) {
+	t.Parallel()
+
+	records := [][]byte{
+		// system board contains 2 processors and the power unit, the memory devices are in the linked record
+		(&SDREntityAssociation{
+			ContainerEntityID: 0x07, ContainerEntityInstance: 0x01,
+			LinkedEntityAssociationExist: true,
+			ContainedEntity1ID:           0x03, ContainedEntity1Instance: 0x01,
+			ContainedEntity2ID: 0x03, ContainedEntity2Instance: 0x02,
+			ContainedEntity3ID: 0x13, ContainedEntity3Instance: 0x01,
+		}).Pack(),
+		(&SDREntityAssociation{
+			ContainerEntityID: 0x07, ContainerEntityInstance: 0x01,
+			ContainedEntitiesAsRange: true,
+			ContainedEntity1ID:       0x20, ContainedEntity1Instance: 0x01,
+			ContainedEntity2ID: 0x20, ContainedEntity2Instance: 0x03,
+		}).Pack(),
+		(&SDREntityAssociation{
+			ContainerEntityID: 0x13, ContainerEntityInstance: 0x01,
+			ContainedEntity1ID: 0x0a, ContainedEntity1Instance: 0x01,
+			ContainedEntity2ID: 0x0a, ContainedEntity2Instance: 0x02,
+		}).Pack(),
+		// would make the system board contain itself
+		(&SDREntityAssociation{
+			ContainerEntityID: 0x0a, ContainerEntityInstance: 0x01,
+			ContainedEntity1ID: 0x07, ContainedEntity1Instance: 0x01,
+		}).Pack(),
+		// the add-in card is relative to the controller at 82h
+		(&SDRDeviceRelative{
+			ContainerEntityID: 0x07, ContainerEntityInstance: 0x01,
+			ContainedEntity1DeviceAddress: 0x82, ContainedEntity1ID: 0x0b, ContainedEntity1Instance: 0x61,
+		}).Pack(),
+		(&SDRFull{
+			GeneratorID: GeneratorBMC, SensorNumber: 0x01, SensorEntityID: 0x03, SensorEntityInstance: 0x01,
+			IDStringBytes: []byte("CPU1 Temp"),
+		}).Pack(),
+		(&SDRCompact{
+			GeneratorID: GeneratorBMC, SensorNumber: 0x10, SensorEntityID: 0x0a, SensorEntityInstance: 0x01,
+			IDStringBytes: []byte("PS1 Status"),
+		}).Pack(),
+		(&SDREventOnly{
+			GeneratorID: GeneratorBMC, SensorNumber: 0x20, SensorEntityID: 0x22, SensorEntityInstance: 0x01,
+			IDStringBytes: []byte("POST Error"),
+		}).Pack(),
+		(&SDRFull{
+			GeneratorID: 0x0082, SensorNumber: 0x01, SensorEntityID: 0x0b, SensorEntityInstance: 0x61,
+			IDStringBytes: []byte("Card Temp"),
+		}).Pack(),
+		(&SDRFRUDeviceLocator{
+			FRUDeviceID_SlaveAddress: 0x01, IsLogicalFRUDevice: true, FRUEntityID: 0x0a, FRUEntityInstance: 0x01,
+			DeviceIDBytes: []byte("PS1"),
+		}).Pack(),
+	}
+
+	sdrs := make([]*SDR, 0)
+	for _, data := range records {
+		sdr, err := ParseSDR(data, 0xffff)
+		if err != nil {
+			t.Fatalf("ParseSDR failed, err: %s", err)
+		}
+		sdrs = append(sdrs, sdr)
+	}
+
+	tree := NewEntityTree(sdrs)
+
+	want := "" +
+		"system board (7.1)\n" +
+		"    processor (3.1)\n" +
+		"      - Sensor: CPU1 Temp (0x01)\n" +
+		"    processor (3.2)\n" +
+		"    add-in card (11.97@0x82/0)\n" +
+		"      - Sensor: Card Temp (0x01)\n" +
+		"    power unit / power domain (19.1)\n" +
+		"        power supply (10.1)\n" +
+		"          - FRU Device Loc: PS1\n" +
+		"          - Sensor: PS1 Status (0x10)\n" +
+		"        power supply (10.2)\n" +
+		"    memory device (32.1)\n" +
+		"    memory device (32.2)\n" +
+		"    memory device (32.3)\n" +
+		"System Firmware (34.1)\n" +
+		"  - Sensor: POST Error (0x20)\n"
+	if got := tree.Format(); got != want {
+		t.Errorf("Format() =\n%s\nwant\n%s", got, want)
+	}
+
+	if got := len(tree.Find(0x20, nil)); got != 3 {
+		t.Errorf("Find(0x20) returned %d nodes, want 3", got)
+	}
+
+	instance := EntityInstance(0x01)
+	nodes := tree.Find(0x07, &instance)
+	if len(nodes) != 1 {
+		t.Fatalf("Find(0x07, 1) returned %d nodes, want 1", len(nodes))
+	}
+	if got := len(nodes[0].AllSensors()); got != 3 {
+		t.Errorf("AllSensors() returned %d sensors, want 3", got)
+	}
+}
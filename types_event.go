@@ -82,6 +82,18 @@ func (ed *EventData) EventReadingOffset() uint8 {
 	return ed.EventData1 & 0x0f
 }
 
+// EventData2Code returns Event Data 2 if it holds an OEM code or a sensor-specific event extension code,
+// that is Event Data 1 [7:6] is 10b or 11b.
+func (ed *EventData) EventData2Code() (uint8, bool) {
+	return ed.EventData2, ed.EventData1&0x80 == 0x80
+}
+
+// EventData3Code returns Event Data 3 if it holds an OEM code or a sensor-specific event extension code,
+// that is Event Data 1 [5:4] is 10b or 11b.
+func (ed *EventData) EventData3Code() (uint8, bool) {
+	return ed.EventData3, ed.EventData1&0x20 == 0x20
+}
+
 func (ed *EventData) String() string {
 	return fmt.Sprintf("%02x%02x%02x", ed.EventData1, ed.EventData2, ed.EventData3)
 }
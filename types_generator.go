@@ -119,3 +119,22 @@ type SensorNumber uint8
 const SensorNumberReserved = 0xff
 
 type SDRMapBySensorNumber map[GeneratorID]map[SensorNumber]*SDR
+
+// NewSDRMapBySensorNumber returns the map of the Full and Compact Sensor SDRs,
+// e.g. to resolve the sensor names of the SEL records offline from the SDRs read by LoadSDRs.
+func NewSDRMapBySensorNumber(sdrs []*SDR) SDRMapBySensorNumber {
+	out := make(SDRMapBySensorNumber)
+	for _, sdr := range sdrs {
+		recordType := sdr.RecordHeader.RecordType
+		if recordType != SDRRecordTypeFullSensor && recordType != SDRRecordTypeCompactSensor {
+			continue
+		}
+
+		generatorID := sdr.GeneratorID()
+		if _, ok := out[generatorID]; !ok {
+			out[generatorID] = make(map[SensorNumber]*SDR)
+		}
+		out[generatorID][sdr.SensorNumber()] = sdr
+	}
+	return out
+}
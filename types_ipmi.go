@@ -94,18 +94,18 @@ func (req *IPMIRequest) Pack() []byte {
 	return msg
 }
 
-func (req *IPMIRequest) ComputeChecksum() {
-	// 8-bit checksum algorithm: Initialize checksum to 0. For each byte, checksum = (checksum + byte) modulo 256. Then checksum = - checksum. When the checksum and the bytes are added together, modulo 256, the result should be 0.
-	//
-	// the position end is not included
-	var checksumFn = func(msg []byte, start int, end int) uint8 {
-		c := 0
-		for i := start; i < end; i++ {
-			c = (c + int(msg[i])) % 256
-		}
-		return -uint8(c)
+// 8-bit checksum algorithm: Initialize checksum to 0. For each byte, checksum = (checksum + byte) modulo 256. Then checksum = - checksum. When the checksum and the bytes are added together, modulo 256, the result should be 0.
+//
+// the position end is not included
+func checksumFn(msg []byte, start int, end int) uint8 {
+	c := 0
+	for i := start; i < end; i++ {
+		c = (c + int(msg[i])) % 256
 	}
+	return -uint8(c)
+}
 
+func (req *IPMIRequest) ComputeChecksum() {
 	tempData := req.Pack()
 
 	cs1Start, cs1End := 0, 2
@@ -115,6 +115,87 @@ func (req *IPMIRequest) ComputeChecksum() {
 	req.Checksum2 = checksumFn(tempData, cs2Start, cs2End)
 }
 
+// Unpack decodes an IPMI request message, it is the reverse of Pack.
+// It is used by the receiving side of an IPMI message, like an emulated BMC.
+func (req *IPMIRequest) Unpack(msg []byte) error {
+	if len(msg) < 7 {
+		return ErrUnpackedDataTooShortWith(len(msg), 7)
+	}
+
+	req.ResponderAddr, _, _ = unpackUint8(msg, 0)
+
+	b, _, _ := unpackUint8(msg, 1)
+	req.NetFn = NetFn(b >> 2)
+	req.ResponderLUN = b & 0x03
+
+	req.Checksum1, _, _ = unpackUint8(msg, 2)
+	req.RequesterAddr, _, _ = unpackUint8(msg, 3)
+
+	b4, _, _ := unpackUint8(msg, 4)
+	req.RequesterSequence = b4 >> 2
+	req.RequesterLUN = b4 & 0x03
+
+	req.Command, _, _ = unpackUint8(msg, 5)
+
+	dataLen := len(msg) - 6 - 1
+	req.CommandData, _, _ = unpackBytes(msg, 6, dataLen)
+	req.Checksum2, _, _ = unpackUint8(msg, len(msg)-1)
+
+	return nil
+}
+
+// ValidChecksum reports whether both checksums of the packed message are right.
+func (req *IPMIRequest) ValidChecksum() bool {
+	msg := req.Pack()
+	return checksumFn(msg, 0, 2) == req.Checksum1 &&
+		checksumFn(msg, 3, len(msg)-1) == req.Checksum2
+}
+
+func (res *IPMIResponse) Pack() []byte {
+	msgLen := 7 + len(res.Data) + 1
+	msg := make([]byte, msgLen)
+
+	packUint8(res.RequesterAddr, msg, 0)
+
+	netFn := uint8(res.NetFn) << 2
+	reqLun := res.RequestLUN & 0x03
+	packUint8(netFn|reqLun, msg, 1)
+
+	packUint8(res.Checksum1, msg, 2)
+	packUint8(res.ResponderAddr, msg, 3)
+
+	var seq uint8 = res.RequesterSequence << 2
+	resLun := res.ResponderLUN & 0x03
+	packUint8(seq|resLun, msg, 4)
+
+	packUint8(res.Command, msg, 5)
+	packUint8(res.CompletionCode, msg, 6)
+
+	if res.Data != nil {
+		packBytes(res.Data, msg, 7)
+	}
+
+	packUint8(res.Checksum2, msg, msgLen-1)
+	return msg
+}
+
+func (res *IPMIResponse) ComputeChecksum() {
+	tempData := res.Pack()
+
+	cs1Start, cs1End := 0, 2
+	res.Checksum1 = checksumFn(tempData, cs1Start, cs1End)
+
+	cs2Start, cs2End := 3, len(tempData)-1
+	res.Checksum2 = checksumFn(tempData, cs2Start, cs2End)
+}
+
+// ValidChecksum reports whether both checksums of the packed message are right.
+func (res *IPMIResponse) ValidChecksum() bool {
+	msg := res.Pack()
+	return checksumFn(msg, 0, 2) == res.Checksum1 &&
+		checksumFn(msg, 3, len(msg)-1) == res.Checksum2
+}
+
 func (res *IPMIResponse) Unpack(msg []byte) error {
 	if len(msg) < 7 {
 		return ErrUnpackedDataTooShortWith(len(msg), 7)
@@ -150,6 +231,9 @@ func (res *IPMIResponse) Unpack(msg []byte) error {
 
 // BuildIPMIRequest creates IPMIRequest for a Command Request.
 // It also fills the Checksum1 and Checksum2 fields of IPMIRequest.
+//
+// For a request bridged to a controller behind the BMC, the returned IPMIRequest
+// is the outermost Send Message request which wraps it.
 func (c *Client) BuildIPMIRequest(ctx context.Context, reqCmd Request) (*IPMIRequest, error) {
 	c.lock()
 	defer c.unlock()
@@ -162,13 +246,23 @@ func (c *Client) BuildIPMIRequest(ctx context.Context, reqCmd Request) (*IPMIReq
 
 		RequesterAddr: c.requesterAddr,
 
-		RequesterSequence: c.session.ipmiSeq,
-		RequesterLUN:      c.requesterLUN,
+		RequesterLUN: c.requesterLUN,
 
 		Command:     reqCmd.Command().ID,
 		CommandData: reqCmd.Pack(),
 	}
 
+	// the bridged request is sent on IPMB by the BMC (or the transit controller)
+	bridge := getIPMBBridge(ctx)
+	if bridge != nil {
+		ipmiReq.ResponderAddr = bridge.targetAddr
+		ipmiReq.RequesterAddr = BMC_SA
+		ipmiReq.RequesterLUN = 0x00
+		if bridge.double() {
+			ipmiReq.RequesterAddr = bridge.transitAddr
+		}
+	}
+
 	commandContext := GetCommandContext(ctx)
 	if commandContext != nil {
 		c.Debug("Got CommandContext:", commandContext)
@@ -187,14 +281,34 @@ func (c *Client) BuildIPMIRequest(ctx context.Context, reqCmd Request) (*IPMIReq
 		}
 	}
 
+	ipmiReq.RequesterSequence = c.nextIPMISeq()
+	ipmiReq.ComputeChecksum()
+
+	if bridge != nil {
+		c.Debug(">>>> Bridged IPMI Request", ipmiReq)
+
+		if bridge.double() {
+			ipmiReq = c.wrapSendMessage(ipmiReq, bridge.targetChannel, bridge.transitAddr, BMC_SA, 0x00)
+			ipmiReq = c.wrapSendMessage(ipmiReq, bridge.transitChannel, c.responderAddr, c.requesterAddr, c.requesterLUN)
+		} else {
+			ipmiReq = c.wrapSendMessage(ipmiReq, bridge.targetChannel, c.responderAddr, c.requesterAddr, c.requesterLUN)
+		}
+	}
+
+	return ipmiReq, nil
+}
+
+// nextIPMISeq returns the requester sequence number for a new IPMI request message.
+// The caller must hold the lock.
+func (c *Client) nextIPMISeq() uint8 {
+	seq := c.session.ipmiSeq
+
 	c.session.ipmiSeq += 1
 	if c.session.ipmiSeq > IPMIRequesterSequenceMax {
 		c.session.ipmiSeq = 1
 	}
 
-	ipmiReq.ComputeChecksum()
-
-	return ipmiReq, nil
+	return seq
 }
 
 // AllCC returns all possible completion codes for the specified response.
@@ -41,6 +41,7 @@ const (
 	OEM_RARITAN                      = 13742
 	OEM_KONTRON                      = 15000
 	OEM_PPS                          = 16394
+	OEM_LENOVO                       = 19046 /* 19046 for [Lenovo Enterprise Business Group] */
 	OEM_IBM_20301                    = 20301 /* 20301 for [IBM eServer X] */
 	OEM_AMI                          = 20974
 	OEM_FOXCONN                      = 22238
@@ -90,6 +91,7 @@ func (oem OEM) String() string {
 		13742: "Raritan", // 力登
 		15000: "Kontron", // 控创
 		16394: "PPS",
+		19046: "Lenovo",
 		20301: "IBM",
 		20974: "AMI",
 		22238: "Foxconn",
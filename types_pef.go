@@ -304,7 +304,7 @@ func (filterType PEFEventFilterType) String() string {
 	if ok {
 		return s
 	}
-	return fmt.Sprintf("%#02x", filterType)
+	return fmt.Sprintf("%#02x", uint8(filterType))
 }
 
 type PEFEventSeverity uint8
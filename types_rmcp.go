@@ -355,29 +355,13 @@ func (c *Client) ParseRmcpResponse(ctx context.Context, msg []byte, response Res
 	}
 
 	if rmcp.Session15 != nil {
-		ipmiPayload := rmcp.Session15.Payload
-
-		ipmiRes := IPMIResponse{}
-		if err := ipmiRes.Unpack(ipmiPayload); err != nil {
-			return fmt.Errorf("unpack ipmiRes failed, err: %w", err)
-		}
-		c.Debug("<<<< IPMI Response", ipmiRes)
-
-		ccode := ipmiRes.CompletionCode
-		if ccode != 0x00 {
-			return &ResponseError{
-				completionCode: CompletionCode(ccode),
-				description:    fmt.Sprintf("ipmiRes CompletionCode (%#02x) is not normal: %s", ccode, StrCC(response, ccode)),
-			}
+		// the BMC answers outside of the session if it does not know the session
+		if sessionID := rmcp.Session15.SessionHeader15.SessionID; c.session.v15.active && sessionID != c.session.v15.sessionID {
+			return fmt.Errorf("%w, response session id: %#08x, want: %#08x", ErrSessionInvalid, sessionID, c.session.v15.sessionID)
 		}
 
-		// now ccode is 0x00, we can continue to deserialize response
-		if err := response.Unpack(ipmiRes.Data); err != nil {
-			return &ResponseError{
-				completionCode: 0x00,
-				description:    fmt.Sprintf("unpack response failed, err: %s", err),
-			}
-		}
+		ipmiPayload := rmcp.Session15.Payload
+		return c.parseIPMIResponse(ctx, ipmiPayload, response)
 	}
 
 	if rmcp.Session20 != nil {
@@ -397,6 +381,12 @@ func (c *Client) ParseRmcpResponse(ctx context.Context, msg []byte, response Res
 
 		case PayloadTypeIPMI:
 			// Standard Payload Types
+
+			// the BMC answers outside of the session if it does not know the session
+			if sessionID := sessionHdr.SessionID; c.session.v20.state == SessionStateActive && sessionID != c.session.v20.consoleSessionID {
+				return fmt.Errorf("%w, response session id: %#08x, want: %#08x", ErrSessionInvalid, sessionID, c.session.v20.consoleSessionID)
+			}
+
 			ipmiPayload := rmcp.Session20.SessionPayload
 			if sessionHdr.PayloadEncrypted {
 				c.DebugBytes("decrypting", ipmiPayload, 16)
@@ -408,27 +398,45 @@ func (c *Client) ParseRmcpResponse(ctx context.Context, msg []byte, response Res
 				c.DebugBytes("decrypted", ipmiPayload, 16)
 			}
 
-			ipmiRes := IPMIResponse{}
-			if err := ipmiRes.Unpack(ipmiPayload); err != nil {
-				return fmt.Errorf("unpack ipmiRes failed, err: %w", err)
-			}
-			c.Debug("<<<< IPMI Response", ipmiRes)
+			return c.parseIPMIResponse(ctx, ipmiPayload, response)
+		}
+	}
 
-			ccode := ipmiRes.CompletionCode
-			if ccode != 0x00 {
-				return &ResponseError{
-					completionCode: CompletionCode(ccode),
-					description:    fmt.Sprintf("ipmiRes CompletionCode (%#02x) is not normal: %s", ccode, StrCC(response, ccode)),
-				}
-			}
+	return nil
+}
 
-			// now ccode is 0x00, we can continue to deserialize response
-			if err := response.Unpack(ipmiRes.Data); err != nil {
-				return &ResponseError{
-					completionCode: 0x00,
-					description:    fmt.Sprintf("unpack response failed, err: %s", err),
-				}
-			}
+// parseIPMIResponse unpacks the IPMI response message into response.
+// The response of a bridged request is unwrapped from the Send Message responses first.
+func (c *Client) parseIPMIResponse(ctx context.Context, ipmiPayload []byte, response Response) error {
+	ipmiRes := &IPMIResponse{}
+	if err := ipmiRes.Unpack(ipmiPayload); err != nil {
+		return fmt.Errorf("unpack ipmiRes failed, err: %w", err)
+	}
+	c.Debug("<<<< IPMI Response", ipmiRes)
+
+	if bridge := getIPMBBridge(ctx); bridge != nil {
+		res, err := c.unwrapBridgedResponse(ipmiRes, bridge)
+		if err != nil {
+			return err
+		}
+		ipmiRes = res
+	}
+
+	setReceived(ctx, ipmiRes.CompletionCode, ipmiRes.Data)
+
+	ccode := ipmiRes.CompletionCode
+	if ccode != 0x00 {
+		return &ResponseError{
+			completionCode: CompletionCode(ccode),
+			description:    fmt.Sprintf("ipmiRes CompletionCode (%#02x) is not normal: %s", ccode, StrCC(response, ccode)),
+		}
+	}
+
+	// now ccode is 0x00, we can continue to deserialize response
+	if err := response.Unpack(ipmiRes.Data); err != nil {
+		return &ResponseError{
+			completionCode: 0x00,
+			description:    fmt.Sprintf("unpack response failed, err: %s", err),
 		}
 	}
 
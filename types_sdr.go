@@ -7,6 +7,12 @@ import (
 
 const SDRRecordHeaderSize int = 5
 
+// SDRVersion is the version of the SDR specification the records are packed with, 51h for IPMI v1.5 and v2.0.
+const SDRVersion uint8 = 0x51
+
+// The maximum length of the ID String Bytes of the SDR records.
+const SDRIDStringMaxSize int = 16
+
 // 43. Sensor Data Record Formats
 // SDRRecordType is a number representing the type of the record.
 type SDRRecordType uint8
@@ -138,6 +144,113 @@ func (sdr *SDR) SensorName() string {
 	return ""
 }
 
+func (sdr *SDR) GeneratorID() GeneratorID {
+	recordType := sdr.RecordHeader.RecordType
+	switch recordType {
+	case SDRRecordTypeFullSensor:
+		return sdr.Full.GeneratorID
+	case SDRRecordTypeCompactSensor:
+		return sdr.Compact.GeneratorID
+	case SDRRecordTypeEventOnly:
+		return sdr.EventOnly.GeneratorID
+	}
+	return 0
+}
+
+func (sdr *SDR) SensorType() SensorType {
+	recordType := sdr.RecordHeader.RecordType
+	switch recordType {
+	case SDRRecordTypeFullSensor:
+		return sdr.Full.SensorType
+	case SDRRecordTypeCompactSensor:
+		return sdr.Compact.SensorType
+	case SDRRecordTypeEventOnly:
+		return sdr.EventOnly.SensorType
+	}
+	return SensorTypeReserved
+}
+
+func (sdr *SDR) EventReadingType() EventReadingType {
+	recordType := sdr.RecordHeader.RecordType
+	switch recordType {
+	case SDRRecordTypeFullSensor:
+		return sdr.Full.SensorEventReadingType
+	case SDRRecordTypeCompactSensor:
+		return sdr.Compact.SensorEventReadingType
+	case SDRRecordTypeEventOnly:
+		return sdr.EventOnly.SensorEventReadingType
+	}
+	return EventReadingTypeUnspecified
+}
+
+// Pack encodes the SDR to the raw record data, which can be used by AddSDR or AddSDRRecord.
+// The record type and record length of the header are determined by the record, the record ID and
+// SDR version are taken from RecordHeader if present.
+func (sdr *SDR) Pack() []byte {
+	var data []byte
+
+	switch {
+	case sdr.Full != nil:
+		data = sdr.Full.Pack()
+	case sdr.Compact != nil:
+		data = sdr.Compact.Pack()
+	case sdr.EventOnly != nil:
+		data = sdr.EventOnly.Pack()
+	case sdr.EntityAssociation != nil:
+		data = sdr.EntityAssociation.Pack()
+	case sdr.DeviceRelative != nil:
+		data = sdr.DeviceRelative.Pack()
+	case sdr.GenericDeviceLocator != nil:
+		data = sdr.GenericDeviceLocator.Pack()
+	case sdr.FRUDeviceLocator != nil:
+		data = sdr.FRUDeviceLocator.Pack()
+	case sdr.MgmtControllerDeviceLocator != nil:
+		data = sdr.MgmtControllerDeviceLocator.Pack()
+	case sdr.MgmtControllerConfirmation != nil:
+		data = sdr.MgmtControllerConfirmation.Pack()
+	case sdr.BMCChannelInfo != nil:
+		data = sdr.BMCChannelInfo.Pack()
+	case sdr.OEM != nil:
+		data = sdr.OEM.Pack()
+	default:
+		// reserved records carry no decoded body
+		data = make([]byte, SDRRecordHeaderSize)
+		if sdr.RecordHeader != nil {
+			packSDRRecordHeader(data, sdr.RecordHeader.RecordType)
+		}
+	}
+
+	if sdr.RecordHeader != nil {
+		packUint16L(sdr.RecordHeader.RecordID, data, 0)
+		if sdr.RecordHeader.SDRVersion != 0 {
+			packUint8(sdr.RecordHeader.SDRVersion, data, 2)
+		}
+	}
+	return data
+}
+
+// packSDRRecordHeader fills the record header of the raw record data, the record ID is left as 0000h,
+// the Add SDR command assigns it.
+func packSDRRecordHeader(data []byte, recordType SDRRecordType) {
+	packUint16L(0, data, 0)
+	packUint8(SDRVersion, data, 2)
+	packUint8(uint8(recordType), data, 3)
+	packUint8(uint8(len(data)-SDRRecordHeaderSize), data, 4)
+}
+
+// packSDRIDString returns the Type/Length byte and the ID String Bytes of the record.
+// The type code is kept from tl (8-bit ASCII if tl is not set), the length is set to the length of the bytes,
+// which are truncated to SDRIDStringMaxSize.
+func packSDRIDString(tl TypeLength, idBytes []byte) (uint8, []byte) {
+	if len(idBytes) > SDRIDStringMaxSize {
+		idBytes = idBytes[:SDRIDStringMaxSize]
+	}
+	if tl == 0 && len(idBytes) > 0 {
+		tl = 0xc0
+	}
+	return uint8(tl)&0xc0 | uint8(len(idBytes)), idBytes
+}
+
 // Determine if sensor has an analog reading
 func (sdr *SDR) HasAnalogReading() bool {
 
@@ -472,6 +585,27 @@ func (mask Mask_DiscreteEvent) TrueEvents() []uint8 {
 	return events
 }
 
+// pack returns the two bytes of the discrete mask in the order of the Mask parse functions,
+// lsb holds the states 14:8, msb holds the states 7:0.
+func (mask Mask_DiscreteEvent) pack() (lsb uint8, msb uint8) {
+	lsb = setOrClearBit6(lsb, mask.State_14)
+	lsb = setOrClearBit5(lsb, mask.State_13)
+	lsb = setOrClearBit4(lsb, mask.State_12)
+	lsb = setOrClearBit3(lsb, mask.State_11)
+	lsb = setOrClearBit2(lsb, mask.State_10)
+	lsb = setOrClearBit1(lsb, mask.State_9)
+	lsb = setOrClearBit0(lsb, mask.State_8)
+	msb = setOrClearBit7(msb, mask.State_7)
+	msb = setOrClearBit6(msb, mask.State_6)
+	msb = setOrClearBit5(msb, mask.State_5)
+	msb = setOrClearBit4(msb, mask.State_4)
+	msb = setOrClearBit3(msb, mask.State_3)
+	msb = setOrClearBit2(msb, mask.State_2)
+	msb = setOrClearBit1(msb, mask.State_1)
+	msb = setOrClearBit0(msb, mask.State_0)
+	return
+}
+
 type Mask_Discrete struct {
 	// Assertion Event Mask for non-threshold based sensors, true means assertion event can be generated for this state
 	Assert Mask_DiscreteEvent
@@ -628,6 +762,90 @@ func (mask *Mask) ParseReading(b uint16) {
 	mask.Threshold.LNC.Readable = isBit0Set(msb)
 }
 
+// PackAssertLower is the reverse of ParseAssertLower, threshold reports whether
+// the threshold masks (for threshold-based sensors) or the discrete masks are packed.
+func (mask *Mask) PackAssertLower(threshold bool) uint16 {
+	var lsb, msb uint8
+
+	if threshold {
+		lsb = setOrClearBit6(lsb, mask.Threshold.LNR.StatusReturned)
+		lsb = setOrClearBit5(lsb, mask.Threshold.LCR.StatusReturned)
+		lsb = setOrClearBit4(lsb, mask.Threshold.LNC.StatusReturned)
+
+		lsb = setOrClearBit3(lsb, mask.Threshold.UNR.High_Assert)
+		lsb = setOrClearBit2(lsb, mask.Threshold.UNR.Low_Assert)
+		lsb = setOrClearBit1(lsb, mask.Threshold.UCR.High_Assert)
+		lsb = setOrClearBit0(lsb, mask.Threshold.UCR.Low_Assert)
+		msb = setOrClearBit7(msb, mask.Threshold.UNC.High_Assert)
+		msb = setOrClearBit6(msb, mask.Threshold.UNC.Low_Assert)
+		msb = setOrClearBit5(msb, mask.Threshold.LNR.High_Assert)
+		msb = setOrClearBit4(msb, mask.Threshold.LNR.Low_Assert)
+		msb = setOrClearBit3(msb, mask.Threshold.LCR.High_Assert)
+		msb = setOrClearBit2(msb, mask.Threshold.LCR.Low_Assert)
+		msb = setOrClearBit1(msb, mask.Threshold.LNC.High_Assert)
+		msb = setOrClearBit0(msb, mask.Threshold.LNC.Low_Assert)
+	} else {
+		lsb, msb = mask.Discrete.Assert.pack()
+	}
+
+	return uint16(msb)<<8 | uint16(lsb)
+}
+
+// PackDeassertUpper is the reverse of ParseDeassertUpper, threshold reports whether
+// the threshold masks (for threshold-based sensors) or the discrete masks are packed.
+func (mask *Mask) PackDeassertUpper(threshold bool) uint16 {
+	var lsb, msb uint8
+
+	if threshold {
+		lsb = setOrClearBit6(lsb, mask.Threshold.UNR.StatusReturned)
+		lsb = setOrClearBit5(lsb, mask.Threshold.UCR.StatusReturned)
+		lsb = setOrClearBit4(lsb, mask.Threshold.UNC.StatusReturned)
+
+		lsb = setOrClearBit3(lsb, mask.Threshold.UNR.High_Deassert)
+		lsb = setOrClearBit2(lsb, mask.Threshold.UNR.Low_Deassert)
+		lsb = setOrClearBit1(lsb, mask.Threshold.UCR.High_Deassert)
+		lsb = setOrClearBit0(lsb, mask.Threshold.UCR.Low_Deassert)
+		msb = setOrClearBit7(msb, mask.Threshold.UNC.High_Deassert)
+		msb = setOrClearBit6(msb, mask.Threshold.UNC.Low_Deassert)
+		msb = setOrClearBit5(msb, mask.Threshold.LNR.High_Deassert)
+		msb = setOrClearBit4(msb, mask.Threshold.LNR.Low_Deassert)
+		msb = setOrClearBit3(msb, mask.Threshold.LCR.High_Deassert)
+		msb = setOrClearBit2(msb, mask.Threshold.LCR.Low_Deassert)
+		msb = setOrClearBit1(msb, mask.Threshold.LNC.High_Deassert)
+		msb = setOrClearBit0(msb, mask.Threshold.LNC.Low_Deassert)
+	} else {
+		lsb, msb = mask.Discrete.Deassert.pack()
+	}
+
+	return uint16(msb)<<8 | uint16(lsb)
+}
+
+// PackReading is the reverse of ParseReading, threshold reports whether
+// the threshold masks (for threshold-based sensors) or the discrete masks are packed.
+func (mask *Mask) PackReading(threshold bool) uint16 {
+	var lsb, msb uint8
+
+	if threshold {
+		lsb = setOrClearBit5(lsb, mask.Threshold.UNR.Settable)
+		lsb = setOrClearBit4(lsb, mask.Threshold.UCR.Settable)
+		lsb = setOrClearBit3(lsb, mask.Threshold.UNC.Settable)
+		lsb = setOrClearBit2(lsb, mask.Threshold.LNR.Settable)
+		lsb = setOrClearBit1(lsb, mask.Threshold.LCR.Settable)
+		lsb = setOrClearBit0(lsb, mask.Threshold.LNC.Settable)
+
+		msb = setOrClearBit5(msb, mask.Threshold.UNR.Readable)
+		msb = setOrClearBit4(msb, mask.Threshold.UCR.Readable)
+		msb = setOrClearBit3(msb, mask.Threshold.UNC.Readable)
+		msb = setOrClearBit2(msb, mask.Threshold.LNR.Readable)
+		msb = setOrClearBit1(msb, mask.Threshold.LCR.Readable)
+		msb = setOrClearBit0(msb, mask.Threshold.LNC.Readable)
+	} else {
+		lsb, msb = mask.Discrete.Reading.pack()
+	}
+
+	return uint16(msb)<<8 | uint16(lsb)
+}
+
 // StatusReturnedThresholds returns all supported thresholds comparison status
 // via the Get Sensor Reading command.
 func (mask *Mask) StatusReturnedThresholds() SensorThresholdTypes {
@@ -700,6 +918,23 @@ func (mask *Mask) SettableThresholds() SensorThresholdTypes {
 	return out
 }
 
+// EventOffsets returns the masks of the event offsets for which the sensor can generate assertion and deassertion events,
+// bit N of the masks is the event offset N. threshold reports whether the sensor is threshold-based.
+func (mask *Mask) EventOffsets(threshold bool) (assertMask uint16, deassertMask uint16) {
+	// the event masks hold the offsets 7:0 in the first byte and 14:8 in the second byte,
+	// the reading masks share the remaining bits of the second byte
+	offsetsMask := uint16(0x7fff)
+	if threshold {
+		offsetsMask = 0x0fff
+	}
+
+	assert := mask.PackAssertLower(threshold)
+	deassert := mask.PackDeassertUpper(threshold)
+	assertMask = (assert>>8 | assert<<8) & offsetsMask
+	deassertMask = (deassert>>8 | deassert<<8) & offsetsMask
+	return
+}
+
 func (mask *Mask) SupportedThresholdEvents() SensorEvents {
 	out := make([]SensorEvent, 0)
 
@@ -812,6 +1047,16 @@ type SensorCapabilities struct {
 	EventMessageControl SensorEventMessageControl
 }
 
+func (capabilities SensorCapabilities) pack() uint8 {
+	var b uint8
+	b = setOrClearBit7(b, capabilities.IgnoreSensorIfNoEntity)
+	b = setOrClearBit6(b, capabilities.AutoRearm)
+	b |= (uint8(capabilities.HysteresisAccess) & 0x03) << 4
+	b |= (uint8(capabilities.ThresholdAccess) & 0x03) << 2
+	b |= uint8(capabilities.EventMessageControl) & 0x03
+	return b
+}
+
 // SDRs of Full/Compact record type has this field.
 type SensorInitialization struct {
 	// 1b = Sensor is settable (Support the Set Sensor Reading And Event Status command)
@@ -856,6 +1101,19 @@ type SensorInitialization struct {
 	SensorScanningEnabled bool
 }
 
+func (initialization SensorInitialization) pack() uint8 {
+	var b uint8
+	b = setOrClearBit7(b, initialization.Settable)
+	b = setOrClearBit6(b, initialization.InitScanning)
+	b = setOrClearBit5(b, initialization.InitEvents)
+	b = setOrClearBit4(b, initialization.InitThresholds)
+	b = setOrClearBit3(b, initialization.InitHysteresis)
+	b = setOrClearBit2(b, initialization.InitSensorType)
+	b = setOrClearBit1(b, initialization.EventGenerationEnabled)
+	b = setOrClearBit0(b, initialization.SensorScanningEnabled)
+	return b
+}
+
 // enhanceSDR will fill extra data for SDR
 func (c *Client) enhanceSDR(ctx context.Context, sdr *SDR) error {
 	if sdr == nil {
@@ -57,8 +57,21 @@ type SDRCompact struct {
 	// 11b = reserved
 	SensorDirection uint8
 
+	// ID String Instance Modifier Type
+	// 00b = numeric
+	// 01b = alpha
+	IDStringInstanceModifierType uint8
+
+	// Share count (number of sensors sharing this record), see SDREventOnly.ShareCount
+	ShareCount uint8
+
+	// 0b = Entity Instance same for all shared records
+	// 1b = Entity Instance increments for each shared record
 	EntityInstanceSharing uint8
 
+	// ID String Instance Modifier Offset, see SDREventOnly.IDStringInstanceModifierOffset
+	IDStringInstanceModifierOffset uint8
+
 	// Positive hysteresis is defined as the unsigned number of counts that are
 	// subtracted from the raw threshold values to create the "re-arm" point for all
 	// positive-going thresholds on the sensor. 0 indicates that there is no hysteresis on
@@ -77,6 +90,9 @@ type SDRCompact struct {
 	// compact SDR can have pos/neg hysteresis, but they cannot be analog!
 	NegativeHysteresisRaw uint8
 
+	// Reserved for OEM use.
+	OEM uint8
+
 	IDStringTypeLength TypeLength // Sensor ID String Type/Length Code
 	IDStringBytes      []byte     // Sensor ID String bytes.
 }
@@ -213,16 +229,28 @@ func parseSDRCompactSensor(data []byte, sdr *SDR) error {
 	b22, _, _ := unpackUint8(data, 22)
 	s.SensorUnit = SensorUnit{
 		AnalogDataFormat: SensorAnalogUnitFormat((b20 & 0xc0) >> 6),
-		RateUnit:         SensorRateUnit((b20 & 0x38) >> 4),
-		ModifierRelation: SensorModifierRelation((b20 & 0x06) >> 2),
+		RateUnit:         SensorRateUnit((b20 & 0x38) >> 3),
+		ModifierRelation: SensorModifierRelation((b20 & 0x06) >> 1),
 		Percentage:       isBit0Set(b20),
 		BaseUnit:         SensorUnitType(b21),
 		ModifierUnit:     SensorUnitType(b22),
 	}
 
+	b23, _, _ := unpackUint8(data, 23)
+	s.SensorDirection = b23 >> 6
+	s.IDStringInstanceModifierType = (b23 & 0x30) >> 4
+	s.ShareCount = b23 & 0x0f
+
+	b24, _, _ := unpackUint8(data, 24)
+	s.EntityInstanceSharing = b24 >> 7
+	s.IDStringInstanceModifierOffset = b24 & 0x7f
+
 	s.PositiveHysteresisRaw, _, _ = unpackUint8(data, 25)
 	s.NegativeHysteresisRaw, _, _ = unpackUint8(data, 26)
 
+	// index 27, 28, 29 reserved
+	s.OEM, _, _ = unpackUint8(data, 30)
+
 	typeLength, _, _ := unpackUint8(data, 31)
 	s.IDStringTypeLength = TypeLength(typeLength)
 
@@ -233,3 +261,41 @@ func parseSDRCompactSensor(data []byte, sdr *SDR) error {
 	s.IDStringBytes, _, _ = unpackBytes(data, minSize, idStrLen)
 	return nil
 }
+
+// Pack encodes the Compact Sensor Record to the raw record data, see SDR.Pack.
+// The masks are packed as threshold masks or discrete masks according to SensorEventReadingType.
+func (compact *SDRCompact) Pack() []byte {
+	typeLength, idBytes := packSDRIDString(compact.IDStringTypeLength, compact.IDStringBytes)
+
+	data := make([]byte, 32+len(idBytes))
+	packSDRRecordHeader(data, SDRRecordTypeCompactSensor)
+
+	packUint16L(uint16(compact.GeneratorID), data, 5)
+	packUint8(uint8(compact.SensorNumber), data, 7)
+	packUint8(uint8(compact.SensorEntityID), data, 8)
+	packUint8(setOrClearBit7(uint8(compact.SensorEntityInstance)&0x7f, compact.SensorEntityIsLogical), data, 9)
+	packUint8(compact.SensorInitialization.pack(), data, 10)
+	packUint8(compact.SensorCapabilities.pack(), data, 11)
+	packUint8(uint8(compact.SensorType), data, 12)
+	packUint8(uint8(compact.SensorEventReadingType), data, 13)
+
+	threshold := compact.SensorEventReadingType.IsThreshold()
+	packUint16(compact.Mask.PackAssertLower(threshold), data, 14)
+	packUint16(compact.Mask.PackDeassertUpper(threshold), data, 16)
+	packUint16(compact.Mask.PackReading(threshold), data, 18)
+
+	packBytes(compact.SensorUnit.pack(), data, 20)
+
+	b23 := (compact.SensorDirection&0x03)<<6 | (compact.IDStringInstanceModifierType&0x03)<<4 | compact.ShareCount&0x0f
+	packUint8(b23, data, 23)
+	b24 := (compact.EntityInstanceSharing&0x01)<<7 | compact.IDStringInstanceModifierOffset&0x7f
+	packUint8(b24, data, 24)
+
+	packUint8(compact.PositiveHysteresisRaw, data, 25)
+	packUint8(compact.NegativeHysteresisRaw, data, 26)
+
+	packUint8(compact.OEM, data, 30)
+	packUint8(typeLength, data, 31)
+	packBytes(idBytes, data, 32)
+	return data
+}
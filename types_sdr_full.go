@@ -143,6 +143,9 @@ type SDRFull struct {
 	// 负向迟滞量
 	NegativeHysteresisRaw uint8
 
+	// Reserved for OEM use.
+	OEM uint8
+
 	IDStringTypeLength TypeLength
 	IDStringBytes      []byte
 }
@@ -163,6 +166,30 @@ func (full *SDRFull) ConvertSensorHysteresis(raw uint8) float64 {
 	return float64(raw)
 }
 
+// ConvertReadingToRaw converts the value in the units of the sensor to the raw sensor reading or raw threshold value,
+// it is the inverse of ConvertReading.
+func (full *SDRFull) ConvertReadingToRaw(value float64, rounding ReadingRounding) (uint8, error) {
+	if full.HasAnalogReading() {
+		return ConvertReadingToRaw(value, full.SensorUnit.AnalogDataFormat, full.ReadingFactors, full.LinearizationFunc, rounding)
+	}
+	return convertToRaw(value, rounding, func(raw uint8) float64 { return float64(raw) })
+}
+
+// ConvertThresholdToRaw converts the threshold value in the units of the sensor to the raw threshold value,
+// see Sensor.ConvertThresholdToRaw.
+func (full *SDRFull) ConvertThresholdToRaw(thresholdType SensorThresholdType, value float64) (uint8, error) {
+	return full.ConvertReadingToRaw(value, thresholdRounding(thresholdType))
+}
+
+// ConvertSensorHysteresisToRaw converts the hysteresis value in the units of the sensor to the raw hysteresis value,
+// it is the inverse of ConvertSensorHysteresis.
+func (full *SDRFull) ConvertSensorHysteresisToRaw(value float64, rounding ReadingRounding) (uint8, error) {
+	if full.HasAnalogReading() {
+		return ConvertSensorHysteresisToRaw(value, full.SensorUnit.AnalogDataFormat, full.ReadingFactors, full.LinearizationFunc, rounding)
+	}
+	return convertToRaw(value, rounding, func(raw uint8) float64 { return float64(raw) })
+}
+
 // ConvertSensorTolerance converts raw sensor tolerance value to real value in the desired units for the sensor.
 func (full *SDRFull) ConvertSensorTolerance(raw uint8) float64 {
 	if full.HasAnalogReading() {
@@ -395,8 +422,8 @@ func parseSDRFullSensor(data []byte, sdr *SDR) error {
 	b22, _, _ := unpackUint8(data, 22)
 	s.SensorUnit = SensorUnit{
 		AnalogDataFormat: SensorAnalogUnitFormat((b20 & 0xc0) >> 6),
-		RateUnit:         SensorRateUnit((b20 & 0x38) >> 4),
-		ModifierRelation: SensorModifierRelation((b20 & 0x06) >> 2),
+		RateUnit:         SensorRateUnit((b20 & 0x38) >> 3),
+		ModifierRelation: SensorModifierRelation((b20 & 0x06) >> 1),
 		Percentage:       isBit0Set(b20),
 		BaseUnit:         SensorUnitType(b21),
 		ModifierUnit:     SensorUnitType(b22),
@@ -454,6 +481,9 @@ func parseSDRFullSensor(data []byte, sdr *SDR) error {
 	s.PositiveHysteresisRaw, _, _ = unpackUint8(data, 42)
 	s.NegativeHysteresisRaw, _, _ = unpackUint8(data, 43)
 
+	// index 44, 45 reserved
+	s.OEM, _, _ = unpackUint8(data, 46)
+
 	typeLength, _, _ := unpackUint8(data, 47)
 	s.IDStringTypeLength = TypeLength(typeLength)
 
@@ -467,6 +497,60 @@ func parseSDRFullSensor(data []byte, sdr *SDR) error {
 	return nil
 }
 
+// Pack encodes the Full Sensor Record to the raw record data, see SDR.Pack.
+// The masks are packed as threshold masks or discrete masks according to SensorEventReadingType.
+func (full *SDRFull) Pack() []byte {
+	typeLength, idBytes := packSDRIDString(full.IDStringTypeLength, full.IDStringBytes)
+
+	data := make([]byte, 48+len(idBytes))
+	packSDRRecordHeader(data, SDRRecordTypeFullSensor)
+
+	packUint16L(uint16(full.GeneratorID), data, 5)
+	packUint8(uint8(full.SensorNumber), data, 7)
+	packUint8(uint8(full.SensorEntityID), data, 8)
+	packUint8(setOrClearBit7(uint8(full.SensorEntityInstance)&0x7f, full.SensorEntityIsLogical), data, 9)
+	packUint8(full.SensorInitialization.pack(), data, 10)
+	packUint8(full.SensorCapabilities.pack(), data, 11)
+	packUint8(uint8(full.SensorType), data, 12)
+	packUint8(uint8(full.SensorEventReadingType), data, 13)
+
+	threshold := full.SensorEventReadingType.IsThreshold()
+	packUint16(full.Mask.PackAssertLower(threshold), data, 14)
+	packUint16(full.Mask.PackDeassertUpper(threshold), data, 16)
+	packUint16(full.Mask.PackReading(threshold), data, 18)
+
+	packBytes(full.SensorUnit.pack(), data, 20)
+	packUint8(uint8(full.LinearizationFunc), data, 23)
+	packBytes(full.ReadingFactors.pack(full.SensorDirection), data, 24)
+
+	var b30 uint8
+	b30 = setOrClearBit2(b30, full.NormalMinSpecified)
+	b30 = setOrClearBit1(b30, full.NormalMaxSpecified)
+	b30 = setOrClearBit0(b30, full.NominalReadingSpecified)
+	packUint8(b30, data, 30)
+
+	packUint8(full.NominalReadingRaw, data, 31)
+	packUint8(full.NormalMaxRaw, data, 32)
+	packUint8(full.NormalMinRaw, data, 33)
+	packUint8(full.SensorMaxReadingRaw, data, 34)
+	packUint8(full.SensorMinReadingRaw, data, 35)
+
+	packUint8(full.UNR_Raw, data, 36)
+	packUint8(full.UCR_Raw, data, 37)
+	packUint8(full.UNC_Raw, data, 38)
+	packUint8(full.LNR_Raw, data, 39)
+	packUint8(full.LCR_Raw, data, 40)
+	packUint8(full.LNC_Raw, data, 41)
+
+	packUint8(full.PositiveHysteresisRaw, data, 42)
+	packUint8(full.NegativeHysteresisRaw, data, 43)
+
+	packUint8(full.OEM, data, 46)
+	packUint8(typeLength, data, 47)
+	packBytes(idBytes, data, 48)
+	return data
+}
+
 func (full *SDRFull) HasAnalogReading() bool {
 	// Todo, logic is not clear.
 	/*
@@ -50,6 +50,9 @@ type SDREventOnly struct {
 	// (alpha characters are considered to be base 26 for ASCII)
 	IDStringInstanceModifierOffset uint8
 
+	// Reserved for OEM use.
+	OEM uint8
+
 	IDStringTypeLength TypeLength
 	IDStringBytes      []byte
 }
@@ -95,6 +98,18 @@ func parseSDREventOnly(data []byte, sdr *SDR) error {
 	eventReadingType, _, _ := unpackUint8(data, 11)
 	s.SensorEventReadingType = EventReadingType(eventReadingType)
 
+	b12, _, _ := unpackUint8(data, 12)
+	s.SensorDirection = b12 >> 6
+	s.IDStringInstanceModifierType = (b12 & 0x30) >> 4
+	s.ShareCount = b12 & 0x0f
+
+	b13, _, _ := unpackUint8(data, 13)
+	s.EntityInstanceSharing = isBit7Set(b13)
+	s.IDStringInstanceModifierOffset = b13 & 0x7f
+
+	// index 14 reserved
+	s.OEM, _, _ = unpackUint8(data, 15)
+
 	typeLength, _, _ := unpackUint8(data, 16)
 	s.IDStringTypeLength = TypeLength(typeLength)
 
@@ -106,6 +121,31 @@ func parseSDREventOnly(data []byte, sdr *SDR) error {
 	return nil
 }
 
+// Pack encodes the Event-Only Record to the raw record data, see SDR.Pack.
+func (eventOnly *SDREventOnly) Pack() []byte {
+	typeLength, idBytes := packSDRIDString(eventOnly.IDStringTypeLength, eventOnly.IDStringBytes)
+
+	data := make([]byte, 17+len(idBytes))
+	packSDRRecordHeader(data, SDRRecordTypeEventOnly)
+
+	packUint16L(uint16(eventOnly.GeneratorID), data, 5)
+	packUint8(uint8(eventOnly.SensorNumber), data, 7)
+	packUint8(uint8(eventOnly.SensorEntityID), data, 8)
+	packUint8(setOrClearBit7(uint8(eventOnly.SensorEntityInstance)&0x7f, eventOnly.SensorEntityIsLogical), data, 9)
+	packUint8(uint8(eventOnly.SensorType), data, 10)
+	packUint8(uint8(eventOnly.SensorEventReadingType), data, 11)
+
+	b12 := (eventOnly.SensorDirection&0x03)<<6 | (eventOnly.IDStringInstanceModifierType&0x03)<<4 | eventOnly.ShareCount&0x0f
+	packUint8(b12, data, 12)
+	b13 := setOrClearBit7(eventOnly.IDStringInstanceModifierOffset&0x7f, eventOnly.EntityInstanceSharing)
+	packUint8(b13, data, 13)
+
+	packUint8(eventOnly.OEM, data, 15)
+	packUint8(typeLength, data, 16)
+	packBytes(idBytes, data, 17)
+	return data
+}
+
 // 43.4 SDR Type 08h - Entity Association Record
 type SDREntityAssociation struct {
 	//
@@ -175,6 +215,31 @@ func parseSDREntityAssociation(data []byte, sdr *SDR) error {
 	return nil
 }
 
+// Pack encodes the Entity Association Record to the raw record data, see SDR.Pack.
+func (s *SDREntityAssociation) Pack() []byte {
+	data := make([]byte, 16)
+	packSDRRecordHeader(data, SDRRecordTypeEntityAssociation)
+
+	packUint8(s.ContainerEntityID, data, 5)
+	packUint8(s.ContainerEntityInstance, data, 6)
+
+	var flag uint8
+	flag = setOrClearBit7(flag, s.ContainedEntitiesAsRange)
+	flag = setOrClearBit6(flag, s.LinkedEntityAssociationExist)
+	flag = setOrClearBit5(flag, s.PresenceSensorAlwaysAccessible)
+	packUint8(flag, data, 7)
+
+	packUint8(s.ContainedEntity1ID, data, 8)
+	packUint8(s.ContainedEntity1Instance, data, 9)
+	packUint8(s.ContainedEntity2ID, data, 10)
+	packUint8(s.ContainedEntity2Instance, data, 11)
+	packUint8(s.ContainedEntity3ID, data, 12)
+	packUint8(s.ContainedEntity3Instance, data, 13)
+	packUint8(s.ContainedEntity4ID, data, 14)
+	packUint8(s.ContainedEntity4Instance, data, 15)
+	return data
+}
+
 // 43.5 SDR Type 09h - Device-relative Entity Association Record
 type SDRDeviceRelative struct {
 	//
@@ -270,6 +335,44 @@ func parseSDRDeviceRelativeEntityAssociation(data []byte, sdr *SDR) error {
 	return nil
 }
 
+// Pack encodes the Device-relative Entity Association Record to the raw record data, see SDR.Pack.
+func (s *SDRDeviceRelative) Pack() []byte {
+	data := make([]byte, 32)
+	packSDRRecordHeader(data, SDRRecordTypeDeviceRelativeEntityAssociation)
+
+	packUint8(s.ContainerEntityID, data, 5)
+	packUint8(s.ContainerEntityInstance, data, 6)
+	packUint8(s.ContainerEntityDeviceAddress, data, 7)
+	packUint8(s.ContainerEntityDeviceChannel, data, 8)
+
+	var flag uint8
+	flag = setOrClearBit7(flag, s.ContainedEntitiesAsRange)
+	flag = setOrClearBit6(flag, s.LinkedEntityAssociationExist)
+	flag = setOrClearBit5(flag, s.PresenceSensorAlwaysAccessible)
+	packUint8(flag, data, 9)
+
+	packUint8(s.ContainedEntity1DeviceAddress, data, 10)
+	packUint8(s.ContainedEntity1DeviceChannel, data, 11)
+	packUint8(s.ContainedEntity1ID, data, 12)
+	packUint8(s.ContainedEntity1Instance, data, 13)
+
+	packUint8(s.ContainedEntity2DeviceAddress, data, 14)
+	packUint8(s.ContainedEntity2DeviceChannel, data, 15)
+	packUint8(s.ContainedEntity2ID, data, 16)
+	packUint8(s.ContainedEntity2Instance, data, 17)
+
+	packUint8(s.ContainedEntity3DeviceAddress, data, 18)
+	packUint8(s.ContainedEntity3DeviceChannel, data, 19)
+	packUint8(s.ContainedEntity3ID, data, 20)
+	packUint8(s.ContainedEntity3Instance, data, 21)
+
+	packUint8(s.ContainedEntity4DeviceAddress, data, 22)
+	packUint8(s.ContainedEntity4DeviceChannel, data, 23)
+	packUint8(s.ContainedEntity4ID, data, 24)
+	packUint8(s.ContainedEntity4Instance, data, 25)
+	return data
+}
+
 // 43.7 SDR Type 10h - Generic Device Locator Record
 // This record is used to store the location and type information for devices
 // on the IPMB or management controller private busses that are neither
@@ -300,6 +403,8 @@ type SDRGenericDeviceLocator struct {
 	EntityID           uint8
 	EntityInstance     uint8
 
+	OEM uint8 // Reserved for OEM use.
+
 	DeviceIDTypeLength TypeLength
 	DeviceIDString     []byte // Short ID string for the device
 }
@@ -317,11 +422,12 @@ func parseSDRGenericLocator(data []byte, sdr *SDR) error {
 
 	s.DeviceAccessAddress, _, _ = unpackUint8(data, 5)
 
+	// bit 0 is the most significant bit of the 4-bit channel number
 	b, _, _ := unpackUint8(data, 6)
-	s.DeviceSlaveAddress = b
+	s.DeviceSlaveAddress = b & 0xfe
 
 	c, _, _ := unpackUint8(data, 7)
-	s.ChannelNumber = ((b & 0x01) << 4) | (c >> 5)
+	s.ChannelNumber = ((b & 0x01) << 3) | (c >> 5)
 	s.AccessLUN = (c & 0x1f) >> 3
 	s.PrivateBusID = (c & 0x07)
 
@@ -331,6 +437,7 @@ func parseSDRGenericLocator(data []byte, sdr *SDR) error {
 
 	s.EntityID, _, _ = unpackUint8(data, 12)
 	s.EntityInstance, _, _ = unpackUint8(data, 13)
+	s.OEM, _, _ = unpackUint8(data, 14)
 
 	typeLength, _, _ := unpackUint8(data, 15)
 	s.DeviceIDTypeLength = TypeLength(typeLength)
@@ -343,6 +450,31 @@ func parseSDRGenericLocator(data []byte, sdr *SDR) error {
 	return nil
 }
 
+// Pack encodes the Generic Device Locator Record to the raw record data, see SDR.Pack.
+func (s *SDRGenericDeviceLocator) Pack() []byte {
+	typeLength, idBytes := packSDRIDString(s.DeviceIDTypeLength, s.DeviceIDString)
+
+	data := make([]byte, 16+len(idBytes))
+	packSDRRecordHeader(data, SDRRecordTypeGenericLocator)
+
+	packUint8(s.DeviceAccessAddress, data, 5)
+
+	// the most significant bit of the channel number is kept in bit 0 of the device slave address
+	packUint8(s.DeviceSlaveAddress&0xfe|(s.ChannelNumber>>3)&0x01, data, 6)
+	packUint8((s.ChannelNumber&0x07)<<5|(s.AccessLUN&0x03)<<3|s.PrivateBusID&0x07, data, 7)
+
+	packUint8(s.AddressSpan, data, 8)
+	packUint8(s.DeviceType, data, 10)
+	packUint8(s.DeviceTypeModifier, data, 11)
+	packUint8(s.EntityID, data, 12)
+	packUint8(s.EntityInstance, data, 13)
+	packUint8(s.OEM, data, 14)
+
+	packUint8(typeLength, data, 15)
+	packBytes(idBytes, data, 16)
+	return data
+}
+
 // 43.8 SDR Type 11h - FRU Device Locator Record
 // 38. Accessing FRU Devices
 type SDRFRUDeviceLocator struct {
@@ -403,6 +535,8 @@ type SDRFRUDeviceLocator struct {
 	FRUEntityID       uint8
 	FRUEntityInstance uint8
 
+	OEM uint8 // Reserved for OEM use.
+
 	DeviceIDTypeLength TypeLength
 	DeviceIDBytes      []byte // Short ID string for the FRU Device
 }
@@ -452,7 +586,7 @@ func parseSDRFRUDeviceLocator(data []byte, sdr *SDR) error {
 	s.FRUEntityID, _, _ = unpackUint8(data, 12)
 	s.FRUEntityInstance, _, _ = unpackUint8(data, 13)
 
-	// index 14 Reserved for OEM use.
+	s.OEM, _, _ = unpackUint8(data, 14)
 
 	typeLength, _, _ := unpackUint8(data, 15)
 	s.DeviceIDTypeLength = TypeLength(typeLength)
@@ -472,6 +606,29 @@ func parseSDRFRUDeviceLocator(data []byte, sdr *SDR) error {
 	return nil
 }
 
+// Pack encodes the FRU Device Locator Record to the raw record data, see SDR.Pack.
+func (s *SDRFRUDeviceLocator) Pack() []byte {
+	typeLength, idBytes := packSDRIDString(s.DeviceIDTypeLength, s.DeviceIDBytes)
+
+	data := make([]byte, 16+len(idBytes))
+	packSDRRecordHeader(data, SDRRecordTypeFRUDeviceLocator)
+
+	packUint8(s.DeviceAccessAddress, data, 5)
+	packUint8(s.FRUDeviceID_SlaveAddress, data, 6)
+	packUint8(setOrClearBit7((s.AccessLUN&0x03)<<3|s.PrivateBusID&0x07, s.IsLogicalFRUDevice), data, 7)
+	packUint8(s.ChannelNumber<<4, data, 8)
+
+	packUint8(uint8(s.DeviceType), data, 10)
+	packUint8(s.DeviceTypeModifier, data, 11)
+	packUint8(s.FRUEntityID, data, 12)
+	packUint8(s.FRUEntityInstance, data, 13)
+	packUint8(s.OEM, data, 14)
+
+	packUint8(typeLength, data, 15)
+	packBytes(idBytes, data, 16)
+	return data
+}
+
 // 43.9 SDR Type 12h - Management Controller Device Locator Record
 type SDRMgmtControllerDeviceLocator struct {
 	//
@@ -502,6 +659,8 @@ type SDRMgmtControllerDeviceLocator struct {
 	EntityID       uint8
 	EntityInstance uint8
 
+	OEM uint8 // Reserved for OEM use.
+
 	DeviceIDTypeLength TypeLength
 	DeviceIDBytes      []byte
 }
@@ -541,6 +700,7 @@ func parseSDRManagementControllerDeviceLocator(data []byte, sdr *SDR) error {
 
 	s.EntityID, _, _ = unpackUint8(data, 12)
 	s.EntityInstance, _, _ = unpackUint8(data, 13)
+	s.OEM, _, _ = unpackUint8(data, 14)
 
 	typeLength, _, _ := unpackUint8(data, 15)
 	s.DeviceIDTypeLength = TypeLength(typeLength)
@@ -553,6 +713,43 @@ func parseSDRManagementControllerDeviceLocator(data []byte, sdr *SDR) error {
 	return nil
 }
 
+// Pack encodes the Management Controller Device Locator Record to the raw record data, see SDR.Pack.
+func (s *SDRMgmtControllerDeviceLocator) Pack() []byte {
+	typeLength, idBytes := packSDRIDString(s.DeviceIDTypeLength, s.DeviceIDBytes)
+
+	data := make([]byte, 16+len(idBytes))
+	packSDRRecordHeader(data, SDRRecordTypeManagementControllerDeviceLocator)
+
+	packUint8(s.DeviceSlaveAddress, data, 5)
+	packUint8(s.ChannelNumber, data, 6)
+
+	var b7 uint8
+	b7 = setOrClearBit7(b7, s.ACPISystemPowerStateNotificationRequired)
+	b7 = setOrClearBit6(b7, s.ACPIDevicePowerStateNotificationRequired)
+	b7 = setOrClearBit3(b7, s.ControllerLogsInitializationAgentErrors)
+	b7 = setOrClearBit2(b7, s.LogInitializationAgentErrors)
+	packUint8(b7, data, 7)
+
+	var b8 uint8
+	b8 = setOrClearBit7(b8, s.DeviceCap_ChassisDevice)
+	b8 = setOrClearBit6(b8, s.DeviceCap_Bridge)
+	b8 = setOrClearBit5(b8, s.DeviceCap_IPMBEventGenerator)
+	b8 = setOrClearBit4(b8, s.DeviceCap_IPMBEventReceiver)
+	b8 = setOrClearBit3(b8, s.DeviceCap_FRUInventoryDevice)
+	b8 = setOrClearBit2(b8, s.DeviceCap_SELDevice)
+	b8 = setOrClearBit1(b8, s.DeviceCap_SDRRepoDevice)
+	b8 = setOrClearBit0(b8, s.DeviceCap_SensorDevice)
+	packUint8(b8, data, 8)
+
+	packUint8(s.EntityID, data, 12)
+	packUint8(s.EntityInstance, data, 13)
+	packUint8(s.OEM, data, 14)
+
+	packUint8(typeLength, data, 15)
+	packBytes(idBytes, data, 16)
+	return data
+}
+
 // 43.10 SDR Type 13h - Management Controller Confirmation Record
 type SDRMgmtControllerConfirmation struct {
 	//
@@ -617,6 +814,28 @@ func parseSDRManagementControllerConfirmation(data []byte, sdr *SDR) error {
 	return nil
 }
 
+// Pack encodes the Management Controller Confirmation Record to the raw record data, see SDR.Pack.
+func (s *SDRMgmtControllerConfirmation) Pack() []byte {
+	data := make([]byte, 32)
+	packSDRRecordHeader(data, SDRRecordTypeManagementControllerConfirmation)
+
+	packUint8(s.DeviceSlaveAddress, data, 5)
+	packUint8(s.DeviceID, data, 6)
+	packUint8(s.ChannelNumber<<4|s.DeviceRevision&0x0f, data, 7)
+	packUint8(s.FirmwareMajorRevision&0x7f, data, 8)
+	packUint8(s.FirmwareMinorRevision, data, 9)
+	packUint8(s.MinorIPMIVersion<<4|s.MajorIPMIVersion&0x0f, data, 10)
+	packUint24L(s.ManufacturerID, data, 11)
+	packUint16L(s.ProductID, data, 14)
+
+	guid := s.DeviceGUID
+	if len(guid) > 16 {
+		guid = guid[:16]
+	}
+	packBytes(guid, data, 16)
+	return data
+}
+
 // 43.11 SDR Type 14h - BMC Message Channel Info Record
 type SDRBMCChannelInfo struct {
 	//
@@ -655,6 +874,10 @@ func parseChannelInfo(b uint8) ChannelInfo {
 	}
 }
 
+func (info ChannelInfo) pack() uint8 {
+	return setOrClearBit7((info.MessageReceiveLUN&0x07)<<4|info.ChannelProtocol&0x0f, info.TransmitSupported)
+}
+
 func parseSDRBMCMessageChannelInfo(data []byte, sdr *SDR) error {
 	const SDRBMCMessageChannelInfoSize = 16
 	minSize := SDRBMCMessageChannelInfoSize
@@ -679,6 +902,25 @@ func parseSDRBMCMessageChannelInfo(data []byte, sdr *SDR) error {
 	return nil
 }
 
+// Pack encodes the BMC Message Channel Info Record to the raw record data, see SDR.Pack.
+func (s *SDRBMCChannelInfo) Pack() []byte {
+	data := make([]byte, 16)
+	packSDRRecordHeader(data, SDRRecordTypeBMCMessageChannelInfo)
+
+	packUint8(s.Channel0.pack(), data, 5)
+	packUint8(s.Channel1.pack(), data, 6)
+	packUint8(s.Channel2.pack(), data, 7)
+	packUint8(s.Channel3.pack(), data, 8)
+	packUint8(s.Channel4.pack(), data, 9)
+	packUint8(s.Channel5.pack(), data, 10)
+	packUint8(s.Channel6.pack(), data, 11)
+	packUint8(s.Channel7.pack(), data, 12)
+
+	packUint8(s.MessagingInterruptType, data, 13)
+	packUint8(s.EventMessageBufferInterruptType, data, 14)
+	return data
+}
+
 // 43.12 SDR Type C0h - OEM Record
 type SDROEM struct {
 	//
@@ -709,6 +951,24 @@ func parseSDROEM(data []byte, sdr *SDR) error {
 	return nil
 }
 
+// Pack encodes the OEM Record to the raw record data, see SDR.Pack.
+// OEMData is truncated to the maximum 64 bytes of the record.
+func (s *SDROEM) Pack() []byte {
+	const SDROEMMaxSize = 64
+
+	oemData := s.OEMData
+	if len(oemData) > SDROEMMaxSize-8 {
+		oemData = oemData[:SDROEMMaxSize-8]
+	}
+
+	data := make([]byte, 8+len(oemData))
+	packSDRRecordHeader(data, SDRRecordTypeOEM)
+
+	packUint24L(s.ManufacturerID, data, 5)
+	packBytes(oemData, data, 8)
+	return data
+}
+
 // 43.6 SDR Type 0Ah:0Fh - Reserved Records
 type SDRReserved struct {
 }
@@ -0,0 +1,250 @@
+package ipmi
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+// sdrRecord returns the raw record data of the record body with the record header.
+func sdrRecord(recordID uint16, recordType SDRRecordType, body ...byte) []byte {
+	data := []byte{uint8(recordID), uint8(recordID >> 8), 0x51, uint8(recordType), uint8(len(body))}
+	return append(data, body...)
+}
+
+func TestSDR_Pack(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{
+			name: "full threshold sensor",
+			data: sdrRecord(0x0001, SDRRecordTypeFullSensor,
+				0x20, 0x00, 0x30, 0x07, 0x81, 0x7f, 0x68, 0x02, 0x01,
+				0x95, 0x7a, 0x95, 0x0a, 0x3f, 0x1b, // masks
+				0x9a, 0x04, 0x01, // sensor units
+				0x00,                               // linearization
+				0xfe, 0xc5, 0x10, 0x4a, 0x5e, 0xf2, // reading factors
+				0x07, 0x80, 0xa0, 0x60, 0xff, 0x00,
+				0xc0, 0xb8, 0xb0, 0x40, 0x48, 0x50, // thresholds
+				0x02, 0x03, 0x00, 0x00, 0x5a,
+				0xc7, '1', '2', 'V', ' ', 'B', 'u', 's',
+			),
+		},
+		{
+			name: "compact discrete sensor",
+			data: sdrRecord(0x0002, SDRRecordTypeCompactSensor,
+				0x20, 0x00, 0x51, 0x20, 0x02, 0x63, 0xc1, 0x07, 0x6f,
+				0x83, 0x01, 0x80, 0x00, 0xff, 0x7f, // masks
+				0xc0, 0x00, 0x00, // sensor units
+				0x52, 0x81, // record sharing
+				0x00, 0x00, 0x00, 0x00, 0x00, 0x11,
+				0xc4, 'C', 'P', 'U', ' ',
+			),
+		},
+		{
+			name: "event-only",
+			data: sdrRecord(0x0003, SDRRecordTypeEventOnly,
+				0x20, 0x00, 0x60, 0x22, 0x01, 0x12, 0x6f, 0x83, 0x05, 0x00, 0x3c,
+				0xc6, 'S', 'y', 's', 'E', 'v', 't',
+			),
+		},
+		{
+			name: "entity association",
+			data: sdrRecord(0x0004, SDRRecordTypeEntityAssociation,
+				0x17, 0x01, 0xa0, 0x0a, 0x01, 0x0a, 0x02, 0x1d, 0x01, 0x1d, 0x02,
+			),
+		},
+		{
+			name: "device-relative entity association",
+			data: sdrRecord(0x0005, SDRRecordTypeDeviceRelativeEntityAssociation,
+				0x17, 0x01, 0x20, 0x00, 0x40,
+				0x82, 0x00, 0x0a, 0x01,
+				0x82, 0x00, 0x0a, 0x02,
+				0x84, 0x10, 0x1d, 0x01,
+				0x84, 0x10, 0x1d, 0x02,
+				0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+			),
+		},
+		{
+			name: "generic device locator",
+			data: sdrRecord(0x0006, SDRRecordTypeGenericLocator,
+				0x20, 0xa1, 0x4b, 0x00, 0x00, 0x10, 0x01, 0x07, 0x01, 0x22,
+				0xc3, 'E', 'E', 'P',
+			),
+		},
+		{
+			name: "fru device locator",
+			data: sdrRecord(0x0007, SDRRecordTypeFRUDeviceLocator,
+				0x20, 0x01, 0x80, 0x10, 0x00, 0x10, 0x00, 0x0a, 0x01, 0x00,
+				0xc5, 'P', 'S', 'U', ' ', '1',
+			),
+		},
+		{
+			name: "mc device locator",
+			data: sdrRecord(0x0008, SDRRecordTypeManagementControllerDeviceLocator,
+				0x20, 0x00, 0xc4, 0xbf, 0x00, 0x00, 0x00, 0x2e, 0x01, 0x00,
+				0xc3, 'B', 'M', 'C',
+			),
+		},
+		{
+			name: "mc confirmation",
+			data: sdrRecord(0x0009, SDRRecordTypeManagementControllerConfirmation,
+				0x20, 0x01, 0x13, 0x02, 0x15, 0x02, 0x57, 0x01, 0x00, 0x34, 0x12,
+				0x00, 0x11, 0x22, 0x33, 0x44, 0x55, 0x66, 0x77, 0x88, 0x99, 0xaa, 0xbb, 0xcc, 0xdd, 0xee, 0xff,
+			),
+		},
+		{
+			name: "bmc message channel info",
+			data: sdrRecord(0x000a, SDRRecordTypeBMCMessageChannelInfo,
+				0x81, 0x94, 0x00, 0x00, 0x00, 0x00, 0x8c, 0x05, 0x0f, 0x01, 0x00,
+			),
+		},
+		{
+			name: "oem",
+			data: sdrRecord(0x000b, SDRRecordTypeOEM,
+				0x57, 0x01, 0x00, 0x01, 0x02, 0x03,
+			),
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			sdr, err := ParseSDR(tt.data, 0xffff)
+			if err != nil {
+				t.Fatalf("ParseSDR failed, err: %s", err)
+			}
+
+			if got := sdr.Pack(); !bytes.Equal(got, tt.data) {
+				t.Errorf("Pack() = % x, want % x", got, tt.data)
+			}
+		})
+	}
+}
+
+func TestSDRFull_Pack(t *testing.T) {
+	t.Parallel()
+
+	full := &SDRFull{
+		GeneratorID:            0x0020,
+		SensorNumber:           0x10,
+		SensorEntityID:         EntityID(0x03),
+		SensorEntityInstance:   0x02,
+		SensorInitialization:   SensorInitialization{InitScanning: true, InitEvents: true, SensorScanningEnabled: true},
+		SensorCapabilities:     SensorCapabilities{AutoRearm: true, ThresholdAccess: 2},
+		SensorType:             SensorTypeTemperature,
+		SensorEventReadingType: EventReadingTypeThreshold,
+		SensorUnit: SensorUnit{
+			AnalogDataFormat: SensorAnalogUnitFormat_2sComplement,
+			RateUnit:         SensorRateUnit_PerSec,
+			ModifierRelation: SensorModifierRelation_Div,
+			BaseUnit:         SensorUnitType_DegreesC,
+			ModifierUnit:     SensorUnitType_DegreesC,
+		},
+		ReadingFactors: ReadingFactors{
+			M:            -2,
+			Tolerance:    3,
+			B:            -100,
+			Accuracy:     0x2aa,
+			Accuracy_Exp: 1,
+			R_Exp:        -3,
+			B_Exp:        4,
+		},
+		SensorDirection:         1,
+		NominalReadingSpecified: true,
+		NominalReadingRaw:       0x40,
+		SensorMaxReadingRaw:     0x7f,
+		SensorMinReadingRaw:     0x80,
+		UCR_Raw:                 0x60,
+		LCR_Raw:                 0x05,
+		PositiveHysteresisRaw:   0x02,
+		OEM:                     0xa5,
+		IDStringBytes:           []byte("Inlet Temp"),
+	}
+	full.Mask.Threshold.UCR.Readable = true
+	full.Mask.Threshold.UCR.Settable = true
+	full.Mask.Threshold.UCR.StatusReturned = true
+	full.Mask.Threshold.UCR.High_Assert = true
+	full.Mask.Threshold.LCR.Readable = true
+	full.Mask.Threshold.LCR.Low_Deassert = true
+
+	data := full.Pack()
+	if data[3] != uint8(SDRRecordTypeFullSensor) || int(data[4]) != len(data)-SDRRecordHeaderSize {
+		t.Fatalf("unexpected record header % x", data[:SDRRecordHeaderSize])
+	}
+
+	sdr, err := ParseSDR(data, 0xffff)
+	if err != nil {
+		t.Fatalf("ParseSDR failed, err: %s", err)
+	}
+
+	// the ID string is packed as 8-bit ASCII if the Type/Length is not set
+	want := *full
+	want.IDStringTypeLength = TypeLength(0xc0 | len(full.IDStringBytes))
+	want.Mask.ParseAssertLower(want.Mask.PackAssertLower(true))
+	want.Mask.ParseDeassertUpper(want.Mask.PackDeassertUpper(true))
+	want.Mask.ParseReading(want.Mask.PackReading(true))
+	if !reflect.DeepEqual(sdr.Full, &want) {
+		t.Errorf("ParseSDR(Pack()) = %+v, want %+v", sdr.Full, &want)
+	}
+}
+
+func TestSDREventOnly_Pack_IDStringTruncated(t *testing.T) {
+	t.Parallel()
+
+	eventOnly := &SDREventOnly{
+		SensorNumber:       0x01,
+		IDStringTypeLength: 0xc0,
+		IDStringBytes:      []byte("A Very Long Sensor Name"),
+	}
+
+	sdr, err := ParseSDR(eventOnly.Pack(), 0xffff)
+	if err != nil {
+		t.Fatalf("ParseSDR failed, err: %s", err)
+	}
+	if got := string(sdr.EventOnly.IDStringBytes); got != "A Very Long Sens" {
+		t.Errorf("IDStringBytes = %q, want %q", got, "A Very Long Sens")
+	}
+}
+
+func TestSDRGenericDeviceLocator_Pack(t *testing.T) {
+	t.Parallel()
+
+	for channel := uint8(0); channel <= 0x0f; channel++ {
+		locator := &SDRGenericDeviceLocator{
+			DeviceAccessAddress: 0x20,
+			DeviceSlaveAddress:  0xa0,
+			ChannelNumber:       channel,
+			AccessLUN:           0x01,
+			PrivateBusID:        0x03,
+			DeviceType:          0x10,
+			DeviceTypeModifier:  0x01,
+			EntityID:            0x07,
+			EntityInstance:      0x01,
+			DeviceIDTypeLength:  0xc3,
+			DeviceIDString:      []byte("EEP"),
+		}
+
+		data := locator.Pack()
+		// the channel number MSB is bit 0 of the device slave address, the LS 3 bits are bits 7:5 of the next byte
+		if got, want := data[6], 0xa0|channel>>3; got != want {
+			t.Errorf("channel %d: device slave address byte = %#02x, want %#02x", channel, got, want)
+		}
+		if got, want := data[7], (channel&0x07)<<5|0x0b; got != want {
+			t.Errorf("channel %d: channel number byte = %#02x, want %#02x", channel, got, want)
+		}
+
+		sdr, err := ParseSDR(data, 0xffff)
+		if err != nil {
+			t.Fatalf("ParseSDR failed, err: %s", err)
+		}
+		if !reflect.DeepEqual(sdr.GenericDeviceLocator, locator) {
+			t.Errorf("ParseSDR(Pack()) = %+v, want %+v", sdr.GenericDeviceLocator, locator)
+		}
+	}
+}
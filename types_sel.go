@@ -16,6 +16,9 @@ type SEL struct {
 	Standard          *SELStandard
 	OEMTimestamped    *SELOEMTimestamped
 	OEMNonTimestamped *SELOEMNonTimestamped
+
+	// decoder decodes the vendor specific parts of the record, see SetDecoder
+	decoder SELDecoder
 }
 
 func (sel *SEL) Pack() []byte {
@@ -96,6 +99,52 @@ func (oemNonTimestamped *SELOEMNonTimestamped) Pack() []byte {
 	return oemNonTimestamped.OEM[:]
 }
 
+// NewSELOEMTimestamped builds a timestamped OEM SEL record to be added by AddSELEntry or AddSELRecord,
+// the timestamp is filled by the BMC when the record is added.
+// recordType must be in C0h-DFh, manufacturerID is the 3 bytes IANA Enterprise Number,
+// and data holds up to 6 bytes OEM defined data, which is padded with 00h.
+//
+// Example usage, stamping a maintenance marker with a ticket number:
+//
+//	ticket := make([]byte, 6)
+//	ticket[0] = 0x01 // disk replaced
+//	binary.BigEndian.PutUint32(ticket[1:], 12345)
+//	sel, err := ipmi.NewSELOEMTimestamped(0xc0, myEnterpriseNumber, ticket)
+func NewSELOEMTimestamped(recordType SELRecordType, manufacturerID uint32, data []byte) (*SEL, error) {
+	if recordType.Range() != SELRecordTypeRangeTimestampedOEM {
+		return nil, fmt.Errorf("record type %#02x is not timestamped OEM (C0h-DFh)", uint8(recordType))
+	}
+	if manufacturerID > 0xffffff {
+		return nil, fmt.Errorf("manufacturer ID %#x exceeds 3 bytes", manufacturerID)
+	}
+	if len(data) > 6 {
+		return nil, fmt.Errorf("OEM defined data is %d bytes, the max is 6 bytes", len(data))
+	}
+
+	oem := &SELOEMTimestamped{
+		Timestamp:      time.Now(),
+		ManufacturerID: manufacturerID,
+	}
+	copy(oem.OEMDefined[:], data)
+	return &SEL{RecordType: recordType, OEMTimestamped: oem}, nil
+}
+
+// NewSELOEMNonTimestamped builds a non-timestamped OEM SEL record to be added by AddSELEntry or AddSELRecord.
+// recordType must be in E0h-FFh, and data holds up to 13 bytes OEM data, which is padded with 00h.
+// The BMC stores the data as is, no manufacturer ID is defined for these records.
+func NewSELOEMNonTimestamped(recordType SELRecordType, data []byte) (*SEL, error) {
+	if recordType.Range() != SELRecordTypeRangeNonTimestampedOEM {
+		return nil, fmt.Errorf("record type %#02x is not non-timestamped OEM (E0h-FFh)", uint8(recordType))
+	}
+	if len(data) > 13 {
+		return nil, fmt.Errorf("OEM data is %d bytes, the max is 13 bytes", len(data))
+	}
+
+	oem := &SELOEMNonTimestamped{}
+	copy(oem.OEM[:], data)
+	return &SEL{RecordType: recordType, OEMNonTimestamped: oem}, nil
+}
+
 // 32.1 SEL Standard Event Records
 type SELStandard struct {
 	Timestamp    time.Time    // Time when event was logged. uint32 LS byte first.
@@ -112,6 +161,9 @@ type SELStandard struct {
 	// The sensor class determines the corresponding Event Data format.
 	// The sensor class can be extracted from EventReadingType.
 	EventData EventData
+
+	// decoder decodes the vendor specific event data, see SEL.SetDecoder
+	decoder SELDecoder
 }
 
 func (standard *SELStandard) Pack() []byte {
@@ -139,7 +191,13 @@ func (standard *SELStandard) Pack() []byte {
 }
 
 // EventString return string description of the event.
+// The vendor specific event data is decoded if a SELDecoder is attached to the record, see SEL.SetDecoder.
 func (sel *SELStandard) EventString() string {
+	if sel.decoder != nil {
+		if s := sel.decoder.DecodeEvent(sel); s != "" {
+			return s
+		}
+	}
 	return sel.EventReadingType.EventString(sel.SensorType, sel.EventData)
 }
 
@@ -213,9 +271,7 @@ func parseSELOEMNonTimestamped(msg []byte, sel *SEL) error {
 
 	s.OEM = [13]byte{}
 	b, _, _ := unpackBytes(msg, 3, 13)
-	for i := 0; i < 6; i++ {
-		s.OEM[i] = b[i]
-	}
+	copy(s.OEM[:], b)
 
 	return nil
 }
@@ -256,20 +312,32 @@ func FormatSELs(records []*SEL, sdrMap SDRMapBySensorNumber) string {
 			}
 
 			if elistMode {
-				var sensorName string
-				sdr, ok := sdrMap[s.GeneratorID][s.SensorNumber]
-				if !ok {
-					sensorName = fmt.Sprintf("N/A %#04x, %#02x", uint16(s.GeneratorID), s.SensorNumber)
-				} else {
-					sensorName = sdr.SensorName()
-				}
-				row["SensorName"] = sensorName
+				row["SensorName"] = selSensorName(s, sdrMap)
 			}
 
 			rows = append(rows, row)
 
 		case SELRecordTypeRangeTimestampedOEM:
+			s := sel.OEMTimestamped
+			description := sel.OEMString()
+			if description == "" {
+				description = fmt.Sprintf("%s (%#06x)", OEM(s.ManufacturerID), s.ManufacturerID)
+			}
+			rows = append(rows, map[string]string{
+				"ID":               fmt.Sprintf("%#04x", sel.RecordID),
+				"RecordType":       sel.RecordType.String(),
+				"Timestamp":        fmt.Sprintf("%v", s.Timestamp),
+				"EventDescription": description,
+				"EventData":        fmt.Sprintf("%x", s.OEMDefined),
+			})
+
 		case SELRecordTypeRangeNonTimestampedOEM:
+			rows = append(rows, map[string]string{
+				"ID":               fmt.Sprintf("%#04x", sel.RecordID),
+				"RecordType":       sel.RecordType.String(),
+				"EventDescription": sel.OEMString(),
+				"EventData":        fmt.Sprintf("%x", sel.OEMNonTimestamped.OEM),
+			})
 		}
 	}
 
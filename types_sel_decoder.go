@@ -0,0 +1,138 @@
+package ipmi
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// SELDecoder decodes the vendor specific parts of the SEL records, which are printed as raw hex otherwise:
+// the OEM records (record types C0h-FFh) and the OEM codes in the event data of the standard records.
+//
+// The decoders are registered by the manufacturer ID and the product ID of the BMC, see RegisterSELDecoder,
+// and attached to the records by SEL.SetDecoder, then they are consulted by SELStandard.EventString,
+// FormatSELs, FormatSELLine and the exporters.
+type SELDecoder interface {
+	// DecodeOEMRecord returns the description of the timestamped or non-timestamped OEM record,
+	// "" if the record is not known.
+	DecodeOEMRecord(sel *SEL) string
+
+	// DecodeEvent returns the description of the standard record with vendor specific event data,
+	// "" to use the generic description.
+	// It must not call SELStandard.EventString, the generic description is EventReadingType.EventString.
+	DecodeEvent(s *SELStandard) string
+}
+
+// SELDecoderFuncs is a SELDecoder built from functions, the nil functions decode nothing.
+type SELDecoderFuncs struct {
+	OEMRecord func(sel *SEL) string
+	Event     func(s *SELStandard) string
+}
+
+func (d SELDecoderFuncs) DecodeOEMRecord(sel *SEL) string {
+	if d.OEMRecord == nil {
+		return ""
+	}
+	return d.OEMRecord(sel)
+}
+
+func (d SELDecoderFuncs) DecodeEvent(s *SELStandard) string {
+	if d.Event == nil {
+		return ""
+	}
+	return d.Event(s)
+}
+
+// selDecoderKey identifies the products a decoder is registered for,
+// allProducts means all the products of the manufacturer.
+type selDecoderKey struct {
+	manufacturerID OEM
+	productID      uint16
+	allProducts    bool
+}
+
+var (
+	selDecodersMu sync.RWMutex
+
+	// selDecoders holds the registered decoders, starting with the builtin vendor decoders
+	selDecoders = map[selDecoderKey]SELDecoder{
+		{manufacturerID: OEM_SUPERMICRO, allProducts: true}:       supermicroSELDecoder,
+		{manufacturerID: OEM_SUPERMICRO_47488, allProducts: true}: supermicroSELDecoder,
+		{manufacturerID: OEM_DELL, allProducts: true}:             dellSELDecoder,
+	}
+)
+
+// RegisterSELDecoder registers the decoder for the given products of the manufacturer,
+// or for all the products of the manufacturer if no productIDs is given.
+// It replaces the decoder registered before for the same products, including the builtin decoders.
+func RegisterSELDecoder(manufacturerID OEM, decoder SELDecoder, productIDs ...uint16) {
+	selDecodersMu.Lock()
+	defer selDecodersMu.Unlock()
+
+	if len(productIDs) == 0 {
+		selDecoders[selDecoderKey{manufacturerID: manufacturerID, allProducts: true}] = decoder
+		return
+	}
+	for _, productID := range productIDs {
+		selDecoders[selDecoderKey{manufacturerID: manufacturerID, productID: productID}] = decoder
+	}
+}
+
+// FindSELDecoder returns the decoder registered for the product, or for all the products of the manufacturer,
+// nil if none is registered.
+func FindSELDecoder(manufacturerID OEM, productID uint16) SELDecoder {
+	selDecodersMu.RLock()
+	defer selDecodersMu.RUnlock()
+
+	if decoder, ok := selDecoders[selDecoderKey{manufacturerID: manufacturerID, productID: productID}]; ok {
+		return decoder
+	}
+	return selDecoders[selDecoderKey{manufacturerID: manufacturerID, allProducts: true}]
+}
+
+// SELDecoder returns the decoder registered for the manufacturer and the product of the BMC,
+// which are got by GetDeviceID. It returns nil if no decoder is registered.
+func (c *Client) SELDecoder(ctx context.Context) (SELDecoder, error) {
+	res, err := c.GetDeviceID(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("GetDeviceID failed, err: %w", err)
+	}
+	return FindSELDecoder(OEM(res.ManufacturerID), res.ProductID), nil
+}
+
+// SetDecoder attaches the decoder to the record, nil detaches the decoder.
+func (sel *SEL) SetDecoder(decoder SELDecoder) {
+	sel.decoder = decoder
+	if sel.Standard != nil {
+		sel.Standard.decoder = decoder
+	}
+}
+
+// SetSELDecoder attaches the decoder to all the records, see SEL.SetDecoder.
+func SetSELDecoder(records []*SEL, decoder SELDecoder) {
+	for _, sel := range records {
+		sel.SetDecoder(decoder)
+	}
+}
+
+// OEMString returns the description of the OEM record by the decoder attached to the record,
+// or else by the decoder registered for the manufacturer of the timestamped OEM record.
+// It returns "" for the standard records and the OEM records not decoded.
+func (sel *SEL) OEMString() string {
+	if sel.RecordType.Range() == SELRecordTypeRangeStandard {
+		return ""
+	}
+
+	if sel.decoder != nil {
+		if s := sel.decoder.DecodeOEMRecord(sel); s != "" {
+			return s
+		}
+	}
+
+	if sel.OEMTimestamped != nil {
+		if decoder := FindSELDecoder(OEM(sel.OEMTimestamped.ManufacturerID), 0x0000); decoder != nil {
+			return decoder.DecodeOEMRecord(sel)
+		}
+	}
+	return ""
+}
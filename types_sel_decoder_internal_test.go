@@ -0,0 +1,151 @@
+package ipmi
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSELDecoderVendors(t *testing.T) {
+	t.Parallel()
+
+	// the records are laid out as decoded by ipmitool (get_supermicro_evt_desc and get_dell_evt_desc of lib/ipmi_sel.c),
+	// they are the SEL records returned by Get SEL Entry
+	tests := []struct {
+		name           string
+		manufacturerID OEM
+		record         []byte
+		want           string
+	}{
+		{
+			name:           "supermicro",
+			manufacturerID: OEM_SUPERMICRO,
+			// Correctable ECC, Event Data 2 1Bh: channel A DIMM 2, Event Data 3 00h: CPU1
+			record: []byte{0x12, 0x00, 0x02, 0x6f, 0x8e, 0x91, 0x5f, 0x20, 0x00, 0x04, 0x0c, 0x05, 0x6f, 0xa0, 0x1b, 0x00},
+			want:   "Correctable ECC / other correctable memory error, DIMMA2(CPU1)",
+		},
+		{
+			name:           "supermicro second cpu",
+			manufacturerID: OEM_SUPERMICRO_47488,
+			// Uncorrectable ECC, Event Data 2 2Ah: channel B DIMM 1, Event Data 3 01h: CPU2, whose channels start with E
+			record: []byte{0x13, 0x00, 0x02, 0x6f, 0x8e, 0x91, 0x5f, 0x20, 0x00, 0x04, 0x0c, 0x05, 0x6f, 0xa1, 0x2a, 0x01},
+			want:   "Uncorrectable ECC / other uncorrectable memory error, DIMMF1(CPU2)",
+		},
+		{
+			name:           "supermicro bmc reset",
+			manufacturerID: OEM_SUPERMICRO,
+			record:         []byte{0x14, 0x00, 0x02, 0x6f, 0x8e, 0x91, 0x5f, 0x20, 0x00, 0x04, 0xc0, 0xca, 0x6f, 0x80, 0x01, 0xff},
+			want:           "BMC cold reset",
+		},
+		{
+			name:           "dell",
+			manufacturerID: OEM_DELL,
+			// Correctable ECC, Event Data 2 0Fh: card A, no group, Event Data 3 04h: the third DIMM
+			record: []byte{0x21, 0x00, 0x02, 0x6f, 0x8e, 0x91, 0x5f, 0x20, 0x00, 0x04, 0x0c, 0x01, 0x6f, 0xa0, 0x0f, 0x04},
+			want:   "Correctable ECC / other correctable memory error, Card A DIMM3",
+		},
+		{
+			name:           "dell dimms per node",
+			manufacturerID: OEM_DELL,
+			// Uncorrectable ECC, Event Data 2 C1h: 12 DIMMs per node, the second group of 8 DIMMs,
+			// Event Data 3 11h: the first and the fifth DIMMs of the group (the 9th and the 13th DIMMs)
+			record: []byte{0x22, 0x00, 0x02, 0x6f, 0x8e, 0x91, 0x5f, 0x20, 0x00, 0x04, 0x0c, 0x01, 0x6f, 0xa1, 0xc1, 0x11},
+			want:   "Uncorrectable ECC / other uncorrectable memory error, DIMMA9, DIMMB1",
+		},
+		{
+			name:           "dell logging disabled",
+			manufacturerID: OEM_DELL,
+			// Correctable memory error logging disabled, Event Data 2 FFh: no card and no group, Event Data 3 82h
+			record: []byte{0x23, 0x00, 0x02, 0x6f, 0x8e, 0x91, 0x5f, 0x20, 0x00, 0x04, 0x10, 0x72, 0x6f, 0xa0, 0xff, 0x82},
+			want:   "Correctable Memory Error Logging Disabled, DIMM2, DIMM8",
+		},
+		{
+			name:           "no oem codes",
+			manufacturerID: OEM_DELL,
+			record:         []byte{0x24, 0x00, 0x02, 0x6f, 0x8e, 0x91, 0x5f, 0x20, 0x00, 0x04, 0x0c, 0x01, 0x6f, 0x00, 0xff, 0xff},
+			want:           "Correctable ECC / other correctable memory error",
+		},
+	}
+
+	for _, tt := range tests {
+		decoder := FindSELDecoder(tt.manufacturerID, 0x1234)
+		if decoder == nil {
+			t.Fatalf("%s: FindSELDecoder returned nil", tt.name)
+		}
+
+		sel, err := ParseSEL(tt.record)
+		if err != nil {
+			t.Fatalf("%s: ParseSEL failed, err: %s", tt.name, err)
+		}
+		sel.SetDecoder(decoder)
+		if got := sel.Standard.EventString(); got != tt.want {
+			t.Errorf("%s: EventString() = %q, want %q", tt.name, got, tt.want)
+		}
+
+		sel.SetDecoder(nil)
+		if got, want := sel.Standard.EventString(), sel.Standard.EventReadingType.EventString(sel.Standard.SensorType, sel.Standard.EventData); got != want {
+			t.Errorf("%s: EventString() without decoder = %q, want %q", tt.name, got, want)
+		}
+	}
+
+	if FindSELDecoder(OEM_HP, 0x0000) != nil {
+		t.Errorf("FindSELDecoder returned a decoder for a manufacturer without decoder")
+	}
+}
+
+func TestRegisterSELDecoder(t *testing.T) {
+	t.Parallel()
+
+	// the manufacturer ID is not used by the other tests
+	const manufacturerID = OEM_DEBUG
+
+	marker := SELDecoderFuncs{
+		OEMRecord: func(sel *SEL) string {
+			if sel.OEMTimestamped == nil || sel.OEMTimestamped.OEMDefined[0] != 0x01 {
+				return ""
+			}
+			return "disk replaced"
+		},
+	}
+	other := SELDecoderFuncs{}
+	RegisterSELDecoder(manufacturerID, marker)
+	RegisterSELDecoder(manufacturerID, other, 0x0002)
+
+	if _, ok := FindSELDecoder(manufacturerID, 0x0001).(SELDecoderFuncs); !ok {
+		t.Fatalf("FindSELDecoder did not return the decoder registered for all the products")
+	}
+	if got := FindSELDecoder(manufacturerID, 0x0002).DecodeOEMRecord(&SEL{}); got != "" {
+		t.Errorf("the decoder registered for the product decoded %q", got)
+	}
+
+	// the timestamped OEM records are decoded by the decoder of their manufacturer without SetDecoder
+	sel, err := NewSELOEMTimestamped(0xc0, uint32(manufacturerID), []byte{0x01})
+	if err != nil {
+		t.Fatalf("NewSELOEMTimestamped failed, err: %s", err)
+	}
+	sel.RecordID = 0x0005
+	if got := sel.OEMString(); got != "disk replaced" {
+		t.Errorf("OEMString() = %q, want %q", got, "disk replaced")
+	}
+	if got := FormatSELLine(sel, nil); !strings.HasSuffix(got, "| OEM record 0xc0 | disk replaced") {
+		t.Errorf("FormatSELLine() = %q", got)
+	}
+	if got := FormatSELs([]*SEL{sel}, nil); !strings.Contains(got, "disk replaced") || !strings.Contains(got, "0x0005") {
+		t.Errorf("FormatSELs() does not contain the OEM record:\n%s", got)
+	}
+	if got := NewSELExportRecord(sel, nil).EventDescription; got != "disk replaced" {
+		t.Errorf("NewSELExportRecord().EventDescription = %q", got)
+	}
+
+	// the decoder attached to the record decodes the non-timestamped OEM records
+	sel, err = NewSELOEMNonTimestamped(0xe0, []byte{0x01})
+	if err != nil {
+		t.Fatalf("NewSELOEMNonTimestamped failed, err: %s", err)
+	}
+	if got := sel.OEMString(); got != "" {
+		t.Errorf("OEMString() without decoder = %q, want empty", got)
+	}
+	sel.SetDecoder(SELDecoderFuncs{OEMRecord: func(sel *SEL) string { return "marker" }})
+	if got := sel.OEMString(); got != "marker" {
+		t.Errorf("OEMString() = %q, want %q", got, "marker")
+	}
+}
@@ -0,0 +1,44 @@
+package ipmi_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/bougou/go-ipmi"
+	"github.com/bougou/go-ipmi/ipmitest"
+)
+
+func TestClient_SELDecoder(t *testing.T) {
+	ctx := context.Background()
+	s := newTestServer(t, func(s *ipmitest.Server) {
+		s.Device.ManufacturerID = ipmi.OEM_SUPERMICRO
+		// Correctable ECC of DIMMC2 of CPU1
+		s.AddSEL([]byte{0, 0, 0x02, 0x6f, 0x8e, 0x91, 0x5f, 0x20, 0x00, 0x04, 0x0c, 0x05, 0x6f, 0xa0, 0x3b, 0x00})
+	})
+	c := newTestClient(t, s, ipmi.InterfaceLanplus, testPassword)
+	if err := c.Connect(ctx); err != nil {
+		t.Fatalf("Connect failed, err: %s", err)
+	}
+	defer c.Close(ctx)
+
+	decoder, err := c.SELDecoder(ctx)
+	if err != nil {
+		t.Fatalf("SELDecoder failed, err: %s", err)
+	}
+	if decoder == nil {
+		t.Fatalf("SELDecoder returned no decoder for Supermicro")
+	}
+
+	sels, err := c.GetSELEntries(ctx, 0)
+	if err != nil {
+		t.Fatalf("GetSELEntries failed, err: %s", err)
+	}
+	ipmi.SetSELDecoder(sels, decoder)
+	if got, want := sels[2].Standard.EventString(), "Correctable ECC / other correctable memory error, DIMMC2(CPU1)"; got != want {
+		t.Errorf("EventString() = %q, want %q", got, want)
+	}
+	if got := ipmi.FormatSELs(sels, nil); !strings.Contains(got, "DIMMC2(CPU1)") {
+		t.Errorf("FormatSELs() does not contain the DIMM:\n%s", got)
+	}
+}
@@ -0,0 +1,147 @@
+package ipmi
+
+import (
+	"fmt"
+	"strings"
+)
+
+// The builtin SEL decoders of the vendors, see RegisterSELDecoder.
+//
+// The memory events (sensor type Memory) carry the DIMM of the error in the event data, whose layout differs by vendor.
+// The layouts are the ones decoded by ipmitool (lib/ipmi_sel.c).
+//
+// The builtin decoders only decode the OEM codes in the event data of the standard records,
+// the OEM records (record types C0h-FFh) are left to the decoders registered with an OEMRecord func.
+//
+// There is no builtin decoder for the Lenovo and IBM BMCs (OEM_LENOVO, OEM_IBM_*) yet, ipmitool does not decode
+// the OEM event data of their memory and PCIe events. Their events are formatted generically,
+// unless a decoder is registered for them by RegisterSELDecoder.
+var (
+	supermicroSELDecoder = SELDecoderFuncs{
+		Event: decodeSupermicroEvent,
+	}
+	dellSELDecoder = SELDecoderFuncs{
+		Event: decodeDellEvent,
+	}
+)
+
+// supermicroSensorTypeBMC is the OEM sensor type of the BMC reset events of the Supermicro BMCs.
+const supermicroSensorTypeBMC SensorType = 0xc0
+
+// decodeSupermicroEvent decodes the events like get_supermicro_evt_desc of ipmitool.
+func decodeSupermicroEvent(s *SELStandard) string {
+	if s.EventReadingType != EventReadingTypeSensorSpecific {
+		return ""
+	}
+
+	switch s.SensorType {
+	case SensorTypeMemory:
+		return withLocation(s, supermicroDIMM(s))
+
+	case supermicroSensorTypeBMC:
+		if s.EventData.EventData1 != 0x80 || s.EventData.EventData3 != 0xff {
+			return ""
+		}
+		switch s.EventData.EventData2 {
+		case 0x00:
+			return "BMC unexpected reset"
+		case 0x01:
+			return "BMC cold reset"
+		case 0x02:
+			return "BMC warm reset"
+		}
+	}
+	return ""
+}
+
+// supermicroDIMM returns the DIMM like "DIMMA1(CPU1)" of the memory events of the Supermicro boards,
+// in the layout ipmitool uses for the boards not listed in its chipset tables (chipset type 1):
+// Event Data 2 [7:4] is the channel (1 for A) and [3:0] the DIMM in the channel plus 9 (Ah for the first),
+// Event Data 3 [1:0] is the CPU (0 for CPU1), the channels of each following CPU are 4 letters further.
+func supermicroDIMM(s *SELStandard) string {
+	channel, dimm := s.EventData.EventData2>>4, s.EventData.EventData2&0x0f
+	if channel == 0 || channel == 0x0f || dimm < 0x0a {
+		return ""
+	}
+	cpu := s.EventData.EventData3 & 0x03
+	return fmt.Sprintf("DIMM%c%d(CPU%d)", '@'+rune(channel)+rune(cpu)*4, dimm-9, cpu+1)
+}
+
+// decodeDellEvent decodes the memory events like get_dell_evt_desc of ipmitool.
+// The DIMMs are also reported by the events of the correctable memory error logging being disabled.
+func decodeDellEvent(s *SELStandard) string {
+	if s.EventReadingType != EventReadingTypeSensorSpecific {
+		return ""
+	}
+
+	switch s.SensorType {
+	case SensorTypeMemory, SensorTypeEventLoggingDisabled:
+		return withLocation(s, dellDIMMs(s))
+	}
+	return ""
+}
+
+// dellDIMMsPerNode is the count of DIMMs per node indicated by Event Data 2 [7:4] of the Dell memory events.
+var dellDIMMsPerNode = map[uint8]int{
+	0x08: 4,
+	0x09: 6,
+	0x0a: 8,
+	0x0b: 9,
+	0x0c: 12,
+	0x0d: 24,
+	0x0e: 3,
+}
+
+// dellDIMMs returns the DIMMs of the memory events of the Dell servers, in the IPMI 2.0 layout decoded by ipmitool:
+// Event Data 3 is a bitmap of 8 DIMMs, and Event Data 2 [3:0] is the group of 8 DIMMs
+// the bitmap is for (0 for the first, Fh if unspecified).
+// Event Data 2 [7:4] is the memory card (0 for card A) if less than 8, or else the count of DIMMs per node
+// (8: 4, 9: 6, Ah: 8, Bh: 9, Ch: 12, Dh: 24, Eh: 3), then the DIMMs are named by the node letter and the number
+// in the node like "DIMMA1", otherwise they are numbered from 1 like "DIMM5".
+func dellDIMMs(s *SELStandard) string {
+	bitmap, ok := s.EventData.EventData3Code()
+	if !ok || bitmap == 0 {
+		return ""
+	}
+
+	var card string
+	var first, perNode int
+	if data2, ok := s.EventData.EventData2Code(); ok {
+		switch high := data2 >> 4; {
+		case high < 0x08:
+			card = fmt.Sprintf("Card %c", 'A'+rune(high))
+		case high != 0x0f:
+			perNode = dellDIMMsPerNode[high]
+		}
+		if group := data2 & 0x0f; group != 0x0f {
+			first = int(group) * 8
+		}
+	}
+
+	var dimms []string
+	for i := 0; i < 8; i++ {
+		if bitmap&(1<<i) == 0 {
+			continue
+		}
+		n := first + i
+		if perNode > 0 {
+			dimms = append(dimms, fmt.Sprintf("DIMM%c%d", 'A'+rune(n/perNode), n%perNode+1))
+		} else {
+			dimms = append(dimms, fmt.Sprintf("DIMM%d", n+1))
+		}
+	}
+
+	location := strings.Join(dimms, ", ")
+	if card != "" {
+		location = card + " " + location
+	}
+	return location
+}
+
+// withLocation appends the location to the generic description of the event, it returns "" if location is empty.
+func withLocation(s *SELStandard, location string) string {
+	if location == "" {
+		return ""
+	}
+	return fmt.Sprintf("%s, %s", s.EventReadingType.EventString(s.SensorType, s.EventData), location)
+}
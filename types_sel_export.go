@@ -0,0 +1,245 @@
+package ipmi
+
+import (
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+)
+
+// WriteSELsRaw writes the 16 bytes SEL records back to back to w,
+// the same format as "ipmitool sel writeraw". The records can be read back by ReadSELsRaw.
+func WriteSELsRaw(w io.Writer, records []*SEL) error {
+	for _, sel := range records {
+		if _, err := w.Write(sel.Pack()); err != nil {
+			return fmt.Errorf("write SEL record %#04x failed, err: %w", sel.RecordID, err)
+		}
+	}
+	return nil
+}
+
+// ReadSELsRaw parses the SEL records written by WriteSELsRaw or "ipmitool sel writeraw",
+// the returned records can be formatted offline, e.g. by FormatSELs.
+func ReadSELsRaw(r io.Reader) ([]*SEL, error) {
+	out := make([]*SEL, 0)
+	for {
+		data := make([]byte, 16)
+		if _, err := io.ReadFull(r, data); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return nil, fmt.Errorf("read SEL record (record %d) failed, err: %w", len(out), err)
+		}
+
+		sel, err := ParseSEL(data)
+		if err != nil {
+			return nil, fmt.Errorf("ParseSEL (record %d) failed, err: %w", len(out), err)
+		}
+		out = append(out, sel)
+	}
+	return out, nil
+}
+
+// selSensorName returns the name of the sensor the standard SEL record is generated by,
+// or the generator ID and sensor number if the sensor is not found in sdrMap.
+func selSensorName(s *SELStandard, sdrMap SDRMapBySensorNumber) string {
+	sdr, ok := sdrMap[s.GeneratorID][s.SensorNumber]
+	if !ok {
+		return fmt.Sprintf("N/A %#04x, %#02x", uint16(s.GeneratorID), s.SensorNumber)
+	}
+	return sdr.SensorName()
+}
+
+// FormatSELLine formats the SEL record in one line like "ipmitool sel elist",
+// the sensor names are resolved through sdrMap, which can be nil.
+func FormatSELLine(sel *SEL, sdrMap SDRMapBySensorNumber) string {
+	switch sel.RecordType.Range() {
+	case SELRecordTypeRangeStandard:
+		s := sel.Standard
+		sensor := fmt.Sprintf("%s %#02x", s.SensorType, s.SensorNumber)
+		if sdrMap != nil {
+			sensor = fmt.Sprintf("%s %s", s.SensorType, selSensorName(s, sdrMap))
+		}
+		return fmt.Sprintf("%#04x | %s | %s | %s | %s",
+			sel.RecordID, s.Timestamp.Format(timeFormat), sensor, s.EventString(), s.EventDir)
+
+	case SELRecordTypeRangeTimestampedOEM:
+		s := sel.OEMTimestamped
+		if description := sel.OEMString(); description != "" {
+			return fmt.Sprintf("%#04x | %s | OEM record %#02x | %s",
+				sel.RecordID, s.Timestamp.Format(timeFormat), uint8(sel.RecordType), description)
+		}
+		return fmt.Sprintf("%#04x | %s | OEM record %#02x | %#06x | % x",
+			sel.RecordID, s.Timestamp.Format(timeFormat), uint8(sel.RecordType), s.ManufacturerID, s.OEMDefined)
+
+	case SELRecordTypeRangeNonTimestampedOEM:
+		if description := sel.OEMString(); description != "" {
+			return fmt.Sprintf("%#04x | OEM record %#02x | %s", sel.RecordID, uint8(sel.RecordType), description)
+		}
+		return fmt.Sprintf("%#04x | OEM record %#02x | % x", sel.RecordID, uint8(sel.RecordType), sel.OEMNonTimestamped.OEM)
+	}
+
+	return fmt.Sprintf("%#04x | %s", sel.RecordID, sel.RecordType)
+}
+
+// WriteSELsText writes the SEL records to w one line each, see FormatSELLine.
+func WriteSELsText(w io.Writer, records []*SEL, sdrMap SDRMapBySensorNumber) error {
+	for _, sel := range records {
+		if _, err := fmt.Fprintln(w, FormatSELLine(sel, sdrMap)); err != nil {
+			return fmt.Errorf("write SEL record %#04x failed, err: %w", sel.RecordID, err)
+		}
+	}
+	return nil
+}
+
+// SELExportRecord is the decoded SEL record written by WriteSELsJSON and WriteSELsCSV.
+// The fields not applicable to the record type are left empty,
+// EventDescription of the OEM records is set if they are decoded, see SEL.OEMString.
+type SELExportRecord struct {
+	RecordID   uint16 `json:"record_id"`
+	RecordType string `json:"record_type"`
+
+	// Timestamp is in UTC, nil for the non-timestamped OEM records.
+	Timestamp *time.Time `json:"timestamp,omitempty"`
+
+	// Standard records
+	GeneratorID      uint16 `json:"generator_id,omitempty"`
+	SensorNumber     uint8  `json:"sensor_number,omitempty"`
+	SensorType       string `json:"sensor_type,omitempty"`
+	SensorName       string `json:"sensor_name,omitempty"`
+	EventReadingType string `json:"event_reading_type,omitempty"`
+	EventDirection   string `json:"event_direction,omitempty"`
+	EventDescription string `json:"event_description,omitempty"`
+	EventSeverity    string `json:"event_severity,omitempty"`
+	EventData        string `json:"event_data,omitempty"`
+
+	// Timestamped OEM records
+	ManufacturerID uint32 `json:"manufacturer_id,omitempty"`
+
+	// Raw is the hex string of the 16 bytes record, so the record can be decoded again.
+	Raw string `json:"raw"`
+}
+
+// NewSELExportRecord decodes the SEL record, the sensor names are resolved through sdrMap, which can be nil.
+func NewSELExportRecord(sel *SEL, sdrMap SDRMapBySensorNumber) *SELExportRecord {
+	out := &SELExportRecord{
+		RecordID:   sel.RecordID,
+		RecordType: sel.RecordType.String(),
+		Raw:        hex.EncodeToString(sel.Pack()),
+	}
+
+	switch sel.RecordType.Range() {
+	case SELRecordTypeRangeStandard:
+		s := sel.Standard
+		timestamp := s.Timestamp.UTC()
+		out.Timestamp = &timestamp
+		out.GeneratorID = uint16(s.GeneratorID)
+		out.SensorNumber = uint8(s.SensorNumber)
+		out.SensorType = s.SensorType.String()
+		if sdrMap != nil {
+			out.SensorName = selSensorName(s, sdrMap)
+		}
+		out.EventReadingType = s.EventReadingType.String()
+		out.EventDirection = s.EventDir.String()
+		out.EventDescription = s.EventString()
+		out.EventSeverity = string(s.EventSeverity())
+		out.EventData = s.EventData.String()
+
+	case SELRecordTypeRangeTimestampedOEM:
+		timestamp := sel.OEMTimestamped.Timestamp.UTC()
+		out.Timestamp = &timestamp
+		out.ManufacturerID = sel.OEMTimestamped.ManufacturerID
+		out.EventDescription = sel.OEMString()
+
+	case SELRecordTypeRangeNonTimestampedOEM:
+		out.EventDescription = sel.OEMString()
+	}
+
+	return out
+}
+
+// WriteSELsJSON writes the SEL records to w as a JSON array of SELExportRecord.
+func WriteSELsJSON(w io.Writer, records []*SEL, sdrMap SDRMapBySensorNumber) error {
+	out := make([]*SELExportRecord, 0, len(records))
+	for _, sel := range records {
+		out = append(out, NewSELExportRecord(sel, sdrMap))
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(out); err != nil {
+		return fmt.Errorf("write SEL records failed, err: %w", err)
+	}
+	return nil
+}
+
+// selCSVHeaders are the columns written by WriteSELsCSV, in the order of the fields of SELExportRecord.
+var selCSVHeaders = []string{
+	"ID",
+	"RecordType",
+	"Timestamp",
+	"GID",
+	"SensorNumber",
+	"SensorType",
+	"SensorName",
+	"EventReadingType",
+	"EventDirection",
+	"EventDescription",
+	"EventSeverity",
+	"EventData",
+	"ManufacturerID",
+	"Raw",
+}
+
+// WriteSELsCSV writes the SEL records to w as CSV with a header line, see SELExportRecord for the columns.
+func WriteSELsCSV(w io.Writer, records []*SEL, sdrMap SDRMapBySensorNumber) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(selCSVHeaders); err != nil {
+		return fmt.Errorf("write CSV header failed, err: %w", err)
+	}
+
+	for _, sel := range records {
+		r := NewSELExportRecord(sel, sdrMap)
+
+		var timestamp, generatorID, sensorNumber, manufacturerID string
+		if r.Timestamp != nil {
+			timestamp = r.Timestamp.Format(time.RFC3339)
+		}
+		if sel.RecordType.Range() == SELRecordTypeRangeStandard {
+			generatorID = fmt.Sprintf("%#04x", r.GeneratorID)
+			sensorNumber = fmt.Sprintf("%#02x", r.SensorNumber)
+		}
+		if sel.RecordType.Range() == SELRecordTypeRangeTimestampedOEM {
+			manufacturerID = fmt.Sprintf("%#06x", r.ManufacturerID)
+		}
+
+		row := []string{
+			fmt.Sprintf("%#04x", r.RecordID),
+			r.RecordType,
+			timestamp,
+			generatorID,
+			sensorNumber,
+			r.SensorType,
+			r.SensorName,
+			r.EventReadingType,
+			r.EventDirection,
+			r.EventDescription,
+			r.EventSeverity,
+			r.EventData,
+			manufacturerID,
+			r.Raw,
+		}
+		if err := writer.Write(row); err != nil {
+			return fmt.Errorf("write SEL record %#04x failed, err: %w", sel.RecordID, err)
+		}
+	}
+
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return fmt.Errorf("write SEL records failed, err: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,145 @@
+package ipmi
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func testSELRecords(t *testing.T) []*SEL {
+	t.Helper()
+
+	out := make([]*SEL, 0)
+	for _, s := range []string{
+		// standard record of the power unit sensor 01h generated by 41h
+		"4d150290b3c66741000409010b03ffff",
+		// timestamped OEM record of the manufacturer 002a7ch
+		"4e15c090b3c6677c2a00010203040506",
+	} {
+		data, _ := hex.DecodeString(s)
+		sel, err := ParseSEL(data)
+		if err != nil {
+			t.Fatalf("ParseSEL failed, err: %s", err)
+		}
+		out = append(out, sel)
+	}
+	return out
+}
+
+func TestSELsRaw(t *testing.T) {
+	t.Parallel()
+
+	records := testSELRecords(t)
+
+	var buf bytes.Buffer
+	if err := WriteSELsRaw(&buf, records); err != nil {
+		t.Fatalf("WriteSELsRaw failed, err: %s", err)
+	}
+	if buf.Len() != 32 {
+		t.Fatalf("WriteSELsRaw wrote %d bytes, want 32", buf.Len())
+	}
+	raw := buf.Bytes()
+
+	got, err := ReadSELsRaw(bytes.NewReader(raw))
+	if err != nil {
+		t.Fatalf("ReadSELsRaw failed, err: %s", err)
+	}
+	if len(got) != len(records) {
+		t.Fatalf("ReadSELsRaw returned %d records, want %d", len(got), len(records))
+	}
+	for i := range records {
+		if !bytes.Equal(got[i].Pack(), records[i].Pack()) {
+			t.Errorf("record %d = % x, want % x", i, got[i].Pack(), records[i].Pack())
+		}
+	}
+
+	if _, err := ReadSELsRaw(bytes.NewReader(raw[:20])); err == nil {
+		t.Errorf("ReadSELsRaw on truncated data should fail")
+	}
+
+	empty, err := ReadSELsRaw(bytes.NewReader(nil))
+	if err != nil || len(empty) != 0 {
+		t.Errorf("ReadSELsRaw on empty data = %v, %v, want no records", empty, err)
+	}
+}
+
+func TestSELsExport(t *testing.T) {
+	t.Parallel()
+
+	records := testSELRecords(t)
+
+	sdr, err := ParseSDR((&SDRFull{
+		GeneratorID: 0x0041, SensorNumber: 0x01, SensorType: SensorTypePowerUnit,
+		IDStringBytes: []byte("PS Redundancy"),
+	}).Pack(), 0x0001)
+	if err != nil {
+		t.Fatalf("ParseSDR failed, err: %s", err)
+	}
+	sdrMap := NewSDRMapBySensorNumber([]*SDR{sdr})
+
+	t.Run("line", func(t *testing.T) {
+		if got := FormatSELLine(records[0], sdrMap); !strings.Contains(got, "PS Redundancy") {
+			t.Errorf("FormatSELLine() = %q, should contain the sensor name", got)
+		}
+		if got := FormatSELLine(records[0], nil); strings.Contains(got, "PS Redundancy") || !strings.HasPrefix(got, "0x154d | ") {
+			t.Errorf("FormatSELLine() without sdrMap = %q", got)
+		}
+		if got := FormatSELLine(records[1], nil); !strings.Contains(got, "0x002a7c") {
+			t.Errorf("FormatSELLine() = %q, should contain the manufacturer ID", got)
+		}
+	})
+
+	t.Run("json", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := WriteSELsJSON(&buf, records, sdrMap); err != nil {
+			t.Fatalf("WriteSELsJSON failed, err: %s", err)
+		}
+
+		var got []SELExportRecord
+		if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+			t.Fatalf("unmarshal failed, err: %s", err)
+		}
+		if len(got) != 2 {
+			t.Fatalf("WriteSELsJSON wrote %d records, want 2", len(got))
+		}
+
+		standard := got[0]
+		if standard.RecordID != 0x154d || standard.GeneratorID != 0x0041 || standard.SensorNumber != 0x01 ||
+			standard.SensorName != "PS Redundancy" || standard.EventSeverity != string(EventSeverityCritical) ||
+			standard.Raw != "4d150290b3c66741000409010b03ffff" || standard.Timestamp == nil {
+			t.Errorf("standard record = %+v", standard)
+		}
+
+		oem := got[1]
+		if oem.ManufacturerID != 0x002a7c || oem.SensorType != "" || oem.Raw != "4e15c090b3c6677c2a00010203040506" {
+			t.Errorf("OEM record = %+v", oem)
+		}
+	})
+
+	t.Run("csv", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := WriteSELsCSV(&buf, records, nil); err != nil {
+			t.Fatalf("WriteSELsCSV failed, err: %s", err)
+		}
+
+		rows, err := csv.NewReader(&buf).ReadAll()
+		if err != nil {
+			t.Fatalf("read CSV failed, err: %s", err)
+		}
+		if len(rows) != 3 {
+			t.Fatalf("WriteSELsCSV wrote %d rows, want 3", len(rows))
+		}
+		if strings.Join(rows[0], ",") != strings.Join(selCSVHeaders, ",") {
+			t.Errorf("header = %v", rows[0])
+		}
+		if rows[1][0] != "0x154d" || rows[1][3] != "0x0041" || rows[1][4] != "0x01" || rows[1][12] != "" {
+			t.Errorf("standard row = %v", rows[1])
+		}
+		if rows[2][3] != "" || rows[2][12] != "0x002a7c" || rows[2][13] != "4e15c090b3c6677c2a00010203040506" {
+			t.Errorf("OEM row = %v", rows[2])
+		}
+	})
+}
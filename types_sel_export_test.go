@@ -0,0 +1,51 @@
+package ipmi_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/bougou/go-ipmi"
+)
+
+func TestClient_SELExport(t *testing.T) {
+	s := newTestServer(t)
+	c := newTestClient(t, s, ipmi.InterfaceLanplus, testPassword)
+	ctx := context.Background()
+	if err := c.Connect(ctx); err != nil {
+		t.Fatalf("Connect failed, err: %s", err)
+	}
+	defer c.Close(ctx)
+
+	sels, err := c.GetSELEntries(ctx, 0)
+	if err != nil {
+		t.Fatalf("GetSELEntries failed, err: %s", err)
+	}
+
+	var buf bytes.Buffer
+	if err := ipmi.WriteSELsRaw(&buf, sels); err != nil {
+		t.Fatalf("WriteSELsRaw failed, err: %s", err)
+	}
+	got, err := ipmi.ReadSELsRaw(&buf)
+	if err != nil {
+		t.Fatalf("ReadSELsRaw failed, err: %s", err)
+	}
+	if len(got) != len(sels) {
+		t.Fatalf("ReadSELsRaw returned %d records, want %d", len(got), len(sels))
+	}
+	for i := range sels {
+		if ipmi.FormatSELLine(got[i], nil) != ipmi.FormatSELLine(sels[i], nil) {
+			t.Errorf("record %d = %q, want %q", i, ipmi.FormatSELLine(got[i], nil), ipmi.FormatSELLine(sels[i], nil))
+		}
+	}
+
+	// an empty SEL has no records instead of an error
+	s.ClearSEL()
+	sels, err = c.GetSELEntries(ctx, 0)
+	if err != nil {
+		t.Fatalf("GetSELEntries on empty SEL failed, err: %s", err)
+	}
+	if len(sels) != 0 {
+		t.Errorf("GetSELEntries on empty SEL returned %d records, want 0", len(sels))
+	}
+}
@@ -37,3 +37,49 @@ func TestParseSEL(t *testing.T) {
 		})
 	}
 }
+
+func TestNewSELOEM(t *testing.T) {
+	t.Parallel()
+
+	sel, err := NewSELOEMTimestamped(0xc1, 0x002a7c, []byte{0x01, 0x00, 0x00, 0x30, 0x39})
+	if err != nil {
+		t.Fatalf("NewSELOEMTimestamped failed, err: %s", err)
+	}
+	parsed, err := ParseSEL(sel.Pack())
+	if err != nil {
+		t.Fatalf("ParseSEL failed, err: %s", err)
+	}
+	if parsed.RecordType != 0xc1 || parsed.OEMTimestamped.ManufacturerID != 0x002a7c ||
+		parsed.OEMTimestamped.OEMDefined != [6]byte{0x01, 0x00, 0x00, 0x30, 0x39, 0x00} {
+		t.Errorf("timestamped OEM record = %+v", parsed.OEMTimestamped)
+	}
+
+	oem := []byte("disk replaced")
+	sel, err = NewSELOEMNonTimestamped(0xe0, oem)
+	if err != nil {
+		t.Fatalf("NewSELOEMNonTimestamped failed, err: %s", err)
+	}
+	parsed, err = ParseSEL(sel.Pack())
+	if err != nil {
+		t.Fatalf("ParseSEL failed, err: %s", err)
+	}
+	if string(parsed.OEMNonTimestamped.OEM[:]) != string(oem) {
+		t.Errorf("non-timestamped OEM record = %q, want %q", parsed.OEMNonTimestamped.OEM[:], oem)
+	}
+
+	invalid := []struct {
+		name string
+		fn   func() (*SEL, error)
+	}{
+		{"standard record type", func() (*SEL, error) { return NewSELOEMTimestamped(0x02, 0x002a7c, nil) }},
+		{"manufacturer ID", func() (*SEL, error) { return NewSELOEMTimestamped(0xc0, 0x01000000, nil) }},
+		{"OEM defined data", func() (*SEL, error) { return NewSELOEMTimestamped(0xc0, 0x002a7c, make([]byte, 7)) }},
+		{"timestamped record type", func() (*SEL, error) { return NewSELOEMNonTimestamped(0xc0, nil) }},
+		{"OEM data", func() (*SEL, error) { return NewSELOEMNonTimestamped(0xe0, make([]byte, 14)) }},
+	}
+	for _, tt := range invalid {
+		if _, err := tt.fn(); err == nil {
+			t.Errorf("invalid %s should fail", tt.name)
+		}
+	}
+}
@@ -589,6 +589,16 @@ func (unit SensorUnit) IsAnalog() bool {
 	return unit.AnalogDataFormat != SensorAnalogUnitFormat_NotAnalog
 }
 
+// pack returns the Sensor Units 1, Base Unit and Modifier Unit bytes of the Full and Compact SDR.
+func (unit SensorUnit) pack() []byte {
+	var b uint8
+	b |= (uint8(unit.AnalogDataFormat) & 0x03) << 6
+	b |= (uint8(unit.RateUnit) & 0x07) << 3
+	b |= (uint8(unit.ModifierRelation) & 0x03) << 1
+	b = setOrClearBit0(b, unit.Percentage)
+	return []byte{b, uint8(unit.BaseUnit), uint8(unit.ModifierUnit)}
+}
+
 type SensorAnalogUnitFormat uint8
 
 const (
@@ -779,6 +789,24 @@ type ReadingFactors struct {
 	B_Exp int8 // 4 bits, signed, also called K1
 }
 
+// pack returns the 6 bytes of M, Tolerance, B, Accuracy, Accuracy exp, R exp and B exp as laid out in the Full SDR,
+// the Sensor Direction shares the byte of the Accuracy exp.
+func (f ReadingFactors) pack(sensorDirection uint8) []byte {
+	m := twosComplementEncode(int32(f.M), 10)
+	b := twosComplementEncode(int32(f.B), 10)
+	rExp := twosComplementEncode(int32(f.R_Exp), 4)
+	bExp := twosComplementEncode(int32(f.B_Exp), 4)
+
+	return []byte{
+		uint8(m),
+		uint8(m>>2)&0xc0 | f.Tolerance&0x3f,
+		uint8(b),
+		uint8(b>>2)&0xc0 | uint8(f.Accuracy)&0x3f,
+		uint8(f.Accuracy>>2)&0xf0 | (f.Accuracy_Exp&0x03)<<2 | sensorDirection&0x03,
+		uint8(rExp&0x0f)<<4 | uint8(bExp&0x0f),
+	}
+}
+
 func (f ReadingFactors) String() string {
 	return fmt.Sprintf("M: (%d), T: (%d), B: (%d), A: (%d), A_Exp: (%d), R_Exp: (%d), B_Exp: (%d)",
 		f.M, f.Tolerance, f.B, f.Accuracy, f.Accuracy_Exp, f.R_Exp, f.B_Exp)
@@ -869,6 +897,110 @@ func ConvertSensorTolerance(raw uint8, analogDataFormat SensorAnalogUnitFormat,
 	return linearizationFunc.Apply(y)
 }
 
+// ReadingRounding specifies which raw value is picked when a value is converted back to raw value,
+// as the raw values of a sensor only represent discrete readings.
+type ReadingRounding uint8
+
+const (
+	// ReadingRoundingNearest picks the raw value whose reading is nearest to the value.
+	ReadingRoundingNearest ReadingRounding = iota
+
+	// ReadingRoundingDown picks the raw value whose reading is the greatest one not above the value.
+	ReadingRoundingDown
+
+	// ReadingRoundingUp picks the raw value whose reading is the least one not below the value.
+	ReadingRoundingUp
+)
+
+func (r ReadingRounding) String() string {
+	switch r {
+	case ReadingRoundingNearest:
+		return "nearest"
+	case ReadingRoundingDown:
+		return "down"
+	case ReadingRoundingUp:
+		return "up"
+	}
+	return fmt.Sprintf("(%d)", uint8(r))
+}
+
+// thresholdRounding returns the rounding of the threshold value, which makes the threshold
+// event assert no later than the value, that is upper thresholds are rounded down and
+// lower thresholds are rounded up.
+func thresholdRounding(thresholdType SensorThresholdType) ReadingRounding {
+	switch thresholdType {
+	case SensorThresholdType_UNC, SensorThresholdType_UCR, SensorThresholdType_UNR:
+		return ReadingRoundingDown
+	case SensorThresholdType_LNC, SensorThresholdType_LCR, SensorThresholdType_LNR:
+		return ReadingRoundingUp
+	}
+	return ReadingRoundingNearest
+}
+
+// ConvertReadingToRaw is the inverse of ConvertReading, it converts the value in the units of the sensor
+// to the raw sensor reading or raw sensor threshold value, rounded by rounding.
+//
+// The conversion formula is not inverted algebraically, instead the readings of all the raw values are compared
+// with the value, so it works for the non-linear linearization functions, negative M and the signed analog data formats.
+// ErrReadingOutOfRange is returned if the value is outside the range of the readings of the sensor.
+func ConvertReadingToRaw(value float64, analogDataFormat SensorAnalogUnitFormat, factors ReadingFactors, linearizationFunc LinearizationFunc, rounding ReadingRounding) (uint8, error) {
+	return convertToRaw(value, rounding, func(raw uint8) float64 {
+		return ConvertReading(raw, analogDataFormat, factors, linearizationFunc)
+	})
+}
+
+// ConvertSensorHysteresisToRaw is the inverse of ConvertSensorHysteresis, see ConvertReadingToRaw.
+func ConvertSensorHysteresisToRaw(value float64, analogDataFormat SensorAnalogUnitFormat, factors ReadingFactors, linearizationFunc LinearizationFunc, rounding ReadingRounding) (uint8, error) {
+	return convertToRaw(value, rounding, func(raw uint8) float64 {
+		return ConvertSensorHysteresis(raw, analogDataFormat, factors, linearizationFunc)
+	})
+}
+
+// convertToRaw returns the raw value whose converted reading matches the value according to rounding.
+func convertToRaw(value float64, rounding ReadingRounding, convert func(raw uint8) float64) (uint8, error) {
+	if math.IsNaN(value) || math.IsInf(value, 0) {
+		return 0, fmt.Errorf("%w: %v", ErrReadingOutOfRange, value)
+	}
+
+	// tolerate the float error of the conversion, so a value which is exactly a reading is not rounded away
+	epsilon := 1e-9 * math.Max(1, math.Abs(value))
+
+	var found bool
+	var best uint8
+	var bestReading float64
+	lowest, highest := math.Inf(1), math.Inf(-1)
+
+	for i := 0; i <= 0xff; i++ {
+		raw := uint8(i)
+		reading := convert(raw)
+		if math.IsNaN(reading) || math.IsInf(reading, 0) {
+			continue
+		}
+		lowest = math.Min(lowest, reading)
+		highest = math.Max(highest, reading)
+
+		var better bool
+		switch rounding {
+		case ReadingRoundingDown:
+			better = reading <= value+epsilon && (!found || reading > bestReading)
+		case ReadingRoundingUp:
+			better = reading >= value-epsilon && (!found || reading < bestReading)
+		default:
+			better = !found || math.Abs(reading-value) < math.Abs(bestReading-value)
+		}
+		if better {
+			found = true
+			best = raw
+			bestReading = reading
+		}
+	}
+
+	if !found || value < lowest-epsilon || value > highest+epsilon {
+		return 0, fmt.Errorf("%w: %v is not in [%v, %v]", ErrReadingOutOfRange, value, lowest, highest)
+	}
+	return best, nil
+}
+
 // Sensor holds all attribute of a sensor.
 type Sensor struct {
 	GeneratorID GeneratorID
@@ -1087,6 +1219,31 @@ func (sensor *Sensor) ConvertSensorTolerance(raw uint8) float64 {
 	return float64(raw)
 }
 
+// ConvertReadingToRaw converts the value in the units of the sensor to the raw sensor reading or raw threshold value,
+// it is the inverse of ConvertReading.
+func (sensor *Sensor) ConvertReadingToRaw(value float64, rounding ReadingRounding) (uint8, error) {
+	if sensor.HasAnalogReading {
+		return ConvertReadingToRaw(value, sensor.SensorUnit.AnalogDataFormat, sensor.Threshold.ReadingFactors, sensor.Threshold.LinearizationFunc, rounding)
+	}
+	return convertToRaw(value, rounding, func(raw uint8) float64 { return float64(raw) })
+}
+
+// ConvertThresholdToRaw converts the threshold value in the units of the sensor to the raw threshold value.
+// Upper thresholds are rounded down and lower thresholds are rounded up,
+// so the threshold event is asserted no later than the reading reaches the value.
+func (sensor *Sensor) ConvertThresholdToRaw(thresholdType SensorThresholdType, value float64) (uint8, error) {
+	return sensor.ConvertReadingToRaw(value, thresholdRounding(thresholdType))
+}
+
+// ConvertSensorHysteresisToRaw converts the hysteresis value in the units of the sensor to the raw hysteresis value,
+// it is the inverse of ConvertSensorHysteresis.
+func (sensor *Sensor) ConvertSensorHysteresisToRaw(value float64, rounding ReadingRounding) (uint8, error) {
+	if sensor.HasAnalogReading {
+		return ConvertSensorHysteresisToRaw(value, sensor.SensorUnit.AnalogDataFormat, sensor.Threshold.ReadingFactors, sensor.Threshold.LinearizationFunc, rounding)
+	}
+	return convertToRaw(value, rounding, func(raw uint8) float64 { return float64(raw) })
+}
+
 // SensorThreshold return SensorThreshold for a specified threshold type.
 func (sensor *Sensor) SensorThreshold(thresholdType SensorThresholdType) SensorThreshold {
 	switch thresholdType {
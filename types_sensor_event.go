@@ -1,6 +1,10 @@
 package ipmi
 
-import "fmt"
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
 
 type SensorEvent struct {
 	SensorClass SensorClass
@@ -74,6 +78,94 @@ func (events SensorEvents) FilterDiscrete() SensorEvents {
 	return out
 }
 
+// thresholdEventOffsets are the event offsets of the going low events of the thresholds,
+// the going high event is the next offset.
+//
+// see: Table 42-2, Generic Event/Reading Type Codes
+var thresholdEventOffsets = map[SensorThresholdType]uint8{
+	SensorThresholdType_LNC: 0x00,
+	SensorThresholdType_LCR: 0x02,
+	SensorThresholdType_LNR: 0x04,
+	SensorThresholdType_UNC: 0x06,
+	SensorThresholdType_UCR: 0x08,
+	SensorThresholdType_UNR: 0x0a,
+}
+
+// ParseSensorEventOffsets resolves the names of the events of a sensor to the mask of their event offsets,
+// bit N of the mask is the event offset N. A name is one of:
+//   - the event offset, like "5" or "0x05"
+//   - "stateN", the event offset N of discrete sensors
+//   - the threshold event in the form of SensorEvent.String, like "ucr+" (going high) or "lnc-" (going low),
+//     or the threshold alone, like "ucr", for both the going high and going low events
+//   - the event name of the event offset in the Event/Reading Type Code tables (see EventReadingType.EventForOffset),
+//     like "Lower Critical - going low" or "Thermal Trip", case-insensitive
+func ParseSensorEventOffsets(names []string, eventReadingType EventReadingType, sensorType SensorType) (uint16, error) {
+	var mask uint16
+	for _, name := range names {
+		offsets, err := parseSensorEventOffset(name, eventReadingType, sensorType)
+		if err != nil {
+			return 0, err
+		}
+		mask |= offsets
+	}
+	return mask, nil
+}
+
+func parseSensorEventOffset(name string, eventReadingType EventReadingType, sensorType SensorType) (uint16, error) {
+	name = strings.TrimSpace(name)
+	lower := strings.ToLower(name)
+
+	if offset, err := strconv.ParseUint(strings.TrimPrefix(lower, "state"), 0, 8); err == nil {
+		if offset > 14 {
+			return 0, fmt.Errorf("invalid event offset %q, must be 0-14", name)
+		}
+		return 1 << offset, nil
+	}
+
+	if eventReadingType.IsThreshold() {
+		for thresholdType, offset := range thresholdEventOffsets {
+			switch lower {
+			case thresholdType.Abbr():
+				return 0b11 << offset, nil
+			case thresholdType.Abbr() + "-":
+				return 1 << offset, nil
+			case thresholdType.Abbr() + "+":
+				return 1 << (offset + 1), nil
+			}
+		}
+	}
+
+	for offset := uint8(0); offset <= 14; offset++ {
+		event := eventReadingType.EventForOffset(sensorType, offset)
+		if event != nil && strings.EqualFold(event.EventName, name) {
+			return 1 << offset, nil
+		}
+	}
+
+	return 0, fmt.Errorf("unknown event %q for %s sensor type %s", name, eventReadingType, sensorType)
+}
+
+// SensorEventOffsetName returns the name of the event offset of a sensor,
+// like "ucr+ (Upper Critical - going high)" or "state1 (Thermal Trip)".
+func SensorEventOffsetName(offset uint8, eventReadingType EventReadingType, sensorType SensorType) string {
+	name := fmt.Sprintf("state%d", offset)
+	if eventReadingType.IsThreshold() {
+		for thresholdType, low := range thresholdEventOffsets {
+			switch offset {
+			case low:
+				name = thresholdType.Abbr() + "-"
+			case low + 1:
+				name = thresholdType.Abbr() + "+"
+			}
+		}
+	}
+
+	if event := eventReadingType.EventForOffset(sensorType, offset); event != nil {
+		name += fmt.Sprintf(" (%s)", event.EventName)
+	}
+	return name
+}
+
 // SensorEventFlag holds a struct with fields indicating the specified sensor event is set or not.
 // SensorEventFlag was embedded in Sensor related commands.
 type SensorEventFlag struct {
@@ -136,6 +228,86 @@ type SensorEventFlag struct {
 	SensorEvent_State_8_Deassert  bool
 }
 
+// eventOffsetFields returns the fields of the event offsets 0-14 of the assertion or deassertion events,
+// the threshold field and the discrete field of an offset share the same bit in the sensor event commands.
+func (flag *SensorEventFlag) eventOffsetFields(assert bool) [15][]*bool {
+	if assert {
+		return [15][]*bool{
+			{&flag.SensorEvent_LNC_Low_Assert, &flag.SensorEvent_State_0_Assert},
+			{&flag.SensorEvent_LNC_High_Assert, &flag.SensorEvent_State_1_Assert},
+			{&flag.SensorEvent_LCR_Low_Assert, &flag.SensorEvent_State_2_Assert},
+			{&flag.SensorEvent_LCR_High_Assert, &flag.SensorEvent_State_3_Assert},
+			{&flag.SensorEvent_LNR_Low_Assert, &flag.SensorEvent_State_4_Assert},
+			{&flag.SensorEvent_LNR_High_Assert, &flag.SensorEvent_State_5_Assert},
+			{&flag.SensorEvent_UNC_Low_Assert, &flag.SensorEvent_State_6_Assert},
+			{&flag.SensorEvent_UNC_High_Assert, &flag.SensorEvent_State_7_Assert},
+			{&flag.SensorEvent_UCR_Low_Assert, &flag.SensorEvent_State_8_Assert},
+			{&flag.SensorEvent_UCR_High_Assert, &flag.SensorEvent_State_9_Assert},
+			{&flag.SensorEvent_UNR_Low_Assert, &flag.SensorEvent_State_10_Assert},
+			{&flag.SensorEvent_UNR_High_Assert, &flag.SensorEvent_State_11_Assert},
+			{&flag.SensorEvent_State_12_Assert},
+			{&flag.SensorEvent_State_13_Assert},
+			{&flag.SensorEvent_State_14_Assert},
+		}
+	}
+	return [15][]*bool{
+		{&flag.SensorEvent_LNC_Low_Deassert, &flag.SensorEvent_State_0_Deassert},
+		{&flag.SensorEvent_LNC_High_Deassert, &flag.SensorEvent_State_1_Deassert},
+		{&flag.SensorEvent_LCR_Low_Deassert, &flag.SensorEvent_State_2_Deassert},
+		{&flag.SensorEvent_LCR_High_Deassert, &flag.SensorEvent_State_3_Deassert},
+		{&flag.SensorEvent_LNR_Low_Deassert, &flag.SensorEvent_State_4_Deassert},
+		{&flag.SensorEvent_LNR_High_Deassert, &flag.SensorEvent_State_5_Deassert},
+		{&flag.SensorEvent_UNC_Low_Deassert, &flag.SensorEvent_State_6_Deassert},
+		{&flag.SensorEvent_UNC_High_Deassert, &flag.SensorEvent_State_7_Deassert},
+		{&flag.SensorEvent_UCR_Low_Deassert, &flag.SensorEvent_State_8_Deassert},
+		{&flag.SensorEvent_UCR_High_Deassert, &flag.SensorEvent_State_9_Deassert},
+		{&flag.SensorEvent_UNR_Low_Deassert, &flag.SensorEvent_State_10_Deassert},
+		{&flag.SensorEvent_UNR_High_Deassert, &flag.SensorEvent_State_11_Deassert},
+		{&flag.SensorEvent_State_12_Deassert},
+		{&flag.SensorEvent_State_13_Deassert},
+		{&flag.SensorEvent_State_14_Deassert},
+	}
+}
+
+// SetEventOffsets sets the events of the offsets in the masks, bit N of the masks is the event offset N.
+// It works for both threshold and discrete sensors.
+func (flag *SensorEventFlag) SetEventOffsets(assertMask uint16, deassertMask uint16) {
+	for i, fields := range flag.eventOffsetFields(true) {
+		for _, field := range fields {
+			*field = assertMask&(1<<i) != 0
+		}
+	}
+	for i, fields := range flag.eventOffsetFields(false) {
+		for _, field := range fields {
+			*field = deassertMask&(1<<i) != 0
+		}
+	}
+}
+
+// EventOffsets returns the masks of the assertion and deassertion event offsets which are set,
+// bit N of the masks is the event offset N. threshold reports whether the threshold fields or the discrete fields are read.
+func (flag *SensorEventFlag) EventOffsets(threshold bool) (assertMask uint16, deassertMask uint16) {
+	// the discrete field is the last field of an offset, the offsets 12-14 have no threshold field
+	isSet := func(fields []*bool) bool {
+		if threshold {
+			return len(fields) == 2 && *fields[0]
+		}
+		return *fields[len(fields)-1]
+	}
+
+	for i, fields := range flag.eventOffsetFields(true) {
+		if isSet(fields) {
+			assertMask |= 1 << i
+		}
+	}
+	for i, fields := range flag.eventOffsetFields(false) {
+		if isSet(fields) {
+			deassertMask |= 1 << i
+		}
+	}
+	return
+}
+
 // TrueEvents returns a slice of SensorEvent those are set to true in the SensorEventFlag.
 func (flag *SensorEventFlag) TrueEvents() []SensorEvent {
 	out := make([]SensorEvent, 0)
@@ -0,0 +1,106 @@
+package ipmi
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestParseSensorEventOffsets(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name             string
+		events           []string
+		eventReadingType EventReadingType
+		sensorType       SensorType
+		want             uint16
+		wantErr          bool
+	}{
+		{
+			name:             "threshold events",
+			events:           []string{"ucr+", "lnc-", "unr"},
+			eventReadingType: EventReadingTypeThreshold,
+			sensorType:       SensorTypeTemperature,
+			want:             1<<9 | 1<<0 | 1<<10 | 1<<11,
+		},
+		{
+			name:             "threshold event name",
+			events:           []string{"lower critical - going low"},
+			eventReadingType: EventReadingTypeThreshold,
+			sensorType:       SensorTypeFan,
+			want:             1 << 2,
+		},
+		{
+			name:             "sensor-specific event names and offsets",
+			events:           []string{"Thermal Trip", "0x05", "state7"},
+			eventReadingType: EventReadingTypeSensorSpecific,
+			sensorType:       SensorTypeProcessor,
+			want:             1<<1 | 1<<5 | 1<<7,
+		},
+		{
+			name:             "threshold name of discrete sensor",
+			events:           []string{"ucr"},
+			eventReadingType: EventReadingTypeSensorSpecific,
+			sensorType:       SensorTypeProcessor,
+			wantErr:          true,
+		},
+		{
+			name:             "offset out of range",
+			events:           []string{"15"},
+			eventReadingType: EventReadingTypeSensorSpecific,
+			sensorType:       SensorTypeProcessor,
+			wantErr:          true,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := ParseSensorEventOffsets(tt.events, tt.eventReadingType, tt.sensorType)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("ParseSensorEventOffsets() = %#04x, want error", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseSensorEventOffsets() failed, err: %s", err)
+			}
+			if got != tt.want {
+				t.Errorf("ParseSensorEventOffsets() = %#04x, want %#04x", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSensorEventFlag_SetEventOffsets(t *testing.T) {
+	t.Parallel()
+
+	var flag SensorEventFlag
+	flag.SetEventOffsets(1<<9|1<<2, 1<<14)
+
+	if !flag.SensorEvent_UCR_High_Assert || !flag.SensorEvent_LCR_Low_Assert || !flag.SensorEvent_State_14_Deassert {
+		t.Errorf("SetEventOffsets did not set the events, flag: %+v", flag)
+	}
+
+	assertMask, deassertMask := flag.EventOffsets(true)
+	if assertMask != 1<<9|1<<2 || deassertMask != 0 {
+		t.Errorf("EventOffsets(true) = %#04x, %#04x", assertMask, deassertMask)
+	}
+	assertMask, deassertMask = flag.EventOffsets(false)
+	if assertMask != 1<<9|1<<2 || deassertMask != 1<<14 {
+		t.Errorf("EventOffsets(false) = %#04x, %#04x", assertMask, deassertMask)
+	}
+
+	request := &SetSensorEventEnableRequest{
+		SensorNumber:   0x10,
+		Mode:           SetSensorEventEnableModeEnable,
+		DiscreteEvents: true,
+	}
+	request.SensorEventFlag.SetEventOffsets(1<<2, 1<<2)
+	if got, want := request.Pack(), []byte{0x10, 0xd0, 0x04, 0x00, 0x04, 0x00}; !bytes.Equal(got, want) {
+		t.Errorf("Pack() = % x, want % x", got, want)
+	}
+}
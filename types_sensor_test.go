@@ -1,6 +1,7 @@
 package ipmi
 
 import (
+	"errors"
 	"fmt"
 	"testing"
 )
@@ -52,3 +53,139 @@ func Test_ConvertReading(t *testing.T) {
 		// Todo
 	}
 }
+
+func Test_ConvertReadingToRaw(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name              string
+		value             float64
+		analogDataFormat  SensorAnalogUnitFormat
+		factors           ReadingFactors
+		linearizationFunc LinearizationFunc
+		rounding          ReadingRounding
+		want              uint8
+		wantErr           bool
+	}{
+		{
+			name:             "exact reading",
+			value:            12.096,
+			analogDataFormat: SensorAnalogUnitFormat_Unsigned,
+			factors:          ReadingFactors{M: 63, R_Exp: -3},
+			want:             0xc0,
+		},
+		{
+			name:             "nearest",
+			value:            12.1,
+			analogDataFormat: SensorAnalogUnitFormat_Unsigned,
+			factors:          ReadingFactors{M: 63, R_Exp: -3},
+			want:             0xc0,
+		},
+		{
+			name:             "rounded up",
+			value:            12.1,
+			analogDataFormat: SensorAnalogUnitFormat_Unsigned,
+			factors:          ReadingFactors{M: 63, R_Exp: -3},
+			rounding:         ReadingRoundingUp,
+			want:             0xc1,
+		},
+		{
+			name:             "2's complement with offset",
+			value:            -20,
+			analogDataFormat: SensorAnalogUnitFormat_2sComplement,
+			factors:          ReadingFactors{M: 1, B: 5},
+			want:             0xe7,
+		},
+		{
+			name:             "1's complement rounded down",
+			value:            -2.5,
+			analogDataFormat: SensorAnalogUnitFormat_1sComplement,
+			factors:          ReadingFactors{M: 1},
+			rounding:         ReadingRoundingDown,
+			want:             0xfc,
+		},
+		{
+			name:             "negative M rounded down",
+			value:            95,
+			analogDataFormat: SensorAnalogUnitFormat_Unsigned,
+			factors:          ReadingFactors{M: -2, B: 100},
+			rounding:         ReadingRoundingDown,
+			want:             0x03,
+		},
+		{
+			name:              "non-linear",
+			value:             0.01,
+			analogDataFormat:  SensorAnalogUnitFormat_Unsigned,
+			factors:           ReadingFactors{M: 1},
+			linearizationFunc: LinearizationFunc_1X,
+			want:              0x64,
+		},
+		{
+			name:             "above the range",
+			value:            256,
+			analogDataFormat: SensorAnalogUnitFormat_Unsigned,
+			factors:          ReadingFactors{M: 1},
+			wantErr:          true,
+		},
+		{
+			name:             "below the range",
+			value:            -129,
+			analogDataFormat: SensorAnalogUnitFormat_2sComplement,
+			factors:          ReadingFactors{M: 1},
+			rounding:         ReadingRoundingUp,
+			wantErr:          true,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := ConvertReadingToRaw(tt.value, tt.analogDataFormat, tt.factors, tt.linearizationFunc, tt.rounding)
+			if tt.wantErr {
+				if !errors.Is(err, ErrReadingOutOfRange) {
+					t.Errorf("ConvertReadingToRaw() err = %v, want ErrReadingOutOfRange", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ConvertReadingToRaw() failed, err: %s", err)
+			}
+			if got != tt.want {
+				t.Errorf("ConvertReadingToRaw() = %#02x, want %#02x", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSensor_ConvertThresholdToRaw(t *testing.T) {
+	t.Parallel()
+
+	sensor := &Sensor{
+		HasAnalogReading: true,
+		SensorUnit:       SensorUnit{AnalogDataFormat: SensorAnalogUnitFormat_Unsigned},
+	}
+	sensor.Threshold.ReadingFactors = ReadingFactors{M: 50}
+
+	// readings are in steps of 50 RPM, 1225 RPM is between 0x18 (1200 RPM) and 0x19 (1250 RPM)
+	tests := []struct {
+		thresholdType SensorThresholdType
+		want          uint8
+	}{
+		{SensorThresholdType_UCR, 0x18},
+		{SensorThresholdType_LCR, 0x19},
+	}
+	for _, tt := range tests {
+		got, err := sensor.ConvertThresholdToRaw(tt.thresholdType, 1225)
+		if err != nil {
+			t.Fatalf("ConvertThresholdToRaw(%s) failed, err: %s", tt.thresholdType, err)
+		}
+		if got != tt.want {
+			t.Errorf("ConvertThresholdToRaw(%s) = %#02x, want %#02x", tt.thresholdType, got, tt.want)
+		}
+		if reading := sensor.ConvertReading(got); reading != float64(tt.want)*50 {
+			t.Errorf("ConvertReading(%#02x) = %v", got, reading)
+		}
+	}
+}
@@ -0,0 +1,113 @@
+package ipmi
+
+import "fmt"
+
+const (
+	// SOLPacketHeaderSize is the size of the SOL payload header which precedes the character data.
+	SOLPacketHeaderSize int = 4
+)
+
+// SOL Operation bits, set in the Operation/Status field of the packets sent by the remote console.
+// see 15.9 SOL Payload Data Format, Table 15-2
+const (
+	SOLOperationNACK uint8 = 1 << 6
+
+	// Ring/WOR, assert RI (may be used to trigger Wake-On-Ring)
+	SOLOperationRingWOR uint8 = 1 << 5
+
+	// Generate BREAK (300 ms, nominal)
+	SOLOperationGenerateBreak uint8 = 1 << 4
+
+	// De-assert CTS (clear to send) to the baseboard serial controller, "pause".
+	SOLOperationDeassertCTS uint8 = 1 << 3
+
+	// De-assert DCD/DSR to the baseboard serial controller.
+	SOLOperationDeassertDCDDSR uint8 = 1 << 2
+
+	// Flush Inbound, the characters from the remote console to the baseboard serial controller.
+	SOLOperationFlushInbound uint8 = 1 << 1
+
+	// Flush Outbound, the characters from the baseboard serial controller to the remote console.
+	SOLOperationFlushOutbound uint8 = 1 << 0
+)
+
+// SOL Status bits, set in the Operation/Status field of the packets sent by the BMC.
+// see 15.9 SOL Payload Data Format, Table 15-2
+const (
+	SOLStatusNACK uint8 = 1 << 6
+
+	// Character transfer is unavailable, e.g. the system is powered down.
+	SOLStatusCharacterTransferUnavailable uint8 = 1 << 5
+
+	// SOL is being deactivated, the remote console should not send more SOL packets.
+	SOLStatusDeactivating uint8 = 1 << 4
+
+	// Characters from the baseboard serial controller were dropped.
+	SOLStatusTransmitOverrun uint8 = 1 << 3
+
+	// A BREAK condition was detected on the baseboard serial controller.
+	SOLStatusBreakDetected uint8 = 1 << 2
+)
+
+// SOLPacket is the payload of the SOL (Serial Over LAN) Payload Type.
+// see 15.9 SOL Payload Data Format
+type SOLPacket struct {
+	// Packet Sequence Number, [3:0], 1 to 15.
+	// 0 means this is an ACK-only packet, it carries no character data.
+	Sequence uint8
+
+	// Packet ACK/NACK Sequence Number, [3:0], the sequence number of the packet being ACK'd or NACK'd.
+	// 0 means this packet does not ACK or NACK any packet.
+	AckSequence uint8
+
+	// The number of characters accepted from the ACK'd packet.
+	AcceptedCharacterCount uint8
+
+	// OperationStatus is the Operation field for packets sent by the remote console,
+	// see SOLOperation bits, or the Status field for packets sent by the BMC, see SOLStatus bits.
+	OperationStatus uint8
+
+	// Character data
+	Data []byte
+}
+
+func (p *SOLPacket) Pack() []byte {
+	out := make([]byte, SOLPacketHeaderSize+len(p.Data))
+	packUint8(p.Sequence&0x0f, out, 0)
+	packUint8(p.AckSequence&0x0f, out, 1)
+	packUint8(p.AcceptedCharacterCount, out, 2)
+	packUint8(p.OperationStatus, out, 3)
+	packBytes(p.Data, out, SOLPacketHeaderSize)
+	return out
+}
+
+func (p *SOLPacket) Unpack(msg []byte) error {
+	if len(msg) < SOLPacketHeaderSize {
+		return ErrUnpackedDataTooShortWith(len(msg), SOLPacketHeaderSize)
+	}
+	p.Sequence = msg[0] & 0x0f
+	p.AckSequence = msg[1] & 0x0f
+	p.AcceptedCharacterCount = msg[2]
+	p.OperationStatus = msg[3]
+	p.Data, _, _ = unpackBytes(msg, SOLPacketHeaderSize, len(msg)-SOLPacketHeaderSize)
+	return nil
+}
+
+// IsNACK reports whether the packet refuses the packet of AckSequence.
+func (p *SOLPacket) IsNACK() bool {
+	return p.AckSequence != 0 && isBit6Set(p.OperationStatus)
+}
+
+func (p *SOLPacket) String() string {
+	return fmt.Sprintf("seq: %d, ack seq: %d, accepted: %d, op/status: %#02x, data: %d bytes",
+		p.Sequence, p.AckSequence, p.AcceptedCharacterCount, p.OperationStatus, len(p.Data))
+}
+
+// nextSOLSequence returns the packet sequence number following seq, it wraps from 15 to 1.
+func nextSOLSequence(seq uint8) uint8 {
+	seq = (seq + 1) & 0x0f
+	if seq == 0 {
+		seq = 1
+	}
+	return seq
+}
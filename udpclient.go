@@ -2,9 +2,11 @@ package ipmi
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"net"
+	"os"
 	"strconv"
 	"sync"
 	"time"
@@ -28,6 +30,22 @@ type UDPClient struct {
 	// lock is used to protect udp Exchange method to prevent another
 	// send/receive operation from occurring while one is in progress.
 	lock sync.Mutex
+
+	// recvCh is not nil when a background goroutine owns the reads of conn,
+	// it holds the received packets which are not consumed by the handle funcs
+	// passed to StartReceiving.
+	recvCh   chan []byte
+	recvStop chan struct{}
+	recvDone chan struct{}
+
+	// handlersMu is held while a received packet is passed to the handlers,
+	// so a handler is not called anymore once its stop func returns.
+	handlersMu sync.Mutex
+	handlers   []*receiveHandler
+}
+
+type receiveHandler struct {
+	handle func(msg []byte) bool
 }
 
 func NewUDPClient(host string, port int) *UDPClient {
@@ -127,6 +145,10 @@ func (c *UDPClient) Exchange(ctx context.Context, reader io.Reader) ([]byte, err
 	c.lock.Lock()
 	defer c.lock.Unlock()
 
+	if c.receiving() {
+		return c.exchangeReceiving(ctx, reader)
+	}
+
 	recvBuffer := make([]byte, c.bufferSize)
 
 	doneChan := make(chan error, 1)
@@ -174,3 +196,278 @@ func (c *UDPClient) Exchange(ctx context.Context, reader io.Reader) ([]byte, err
 		return recvBuffer[:recvCount], nil
 	}
 }
+
+// exchangeReceiving sends the request and waits for the reply delivered by the receiving goroutine.
+func (c *UDPClient) exchangeReceiving(ctx context.Context, reader io.Reader) ([]byte, error) {
+	// discard the stale replies of the previous timed out requests
+	for len(c.recvCh) > 0 {
+		<-c.recvCh
+	}
+
+	if _, err := io.Copy(c.conn, reader); err != nil {
+		return nil, fmt.Errorf("write to conn failed, err: %w", err)
+	}
+
+	return c.waitReceived(ctx)
+}
+
+// waitReceived waits for a packet delivered by the receiving goroutine.
+func (c *UDPClient) waitReceived(ctx context.Context) ([]byte, error) {
+	timer := time.NewTimer(c.timeout)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return nil, fmt.Errorf("canceled from caller")
+	case <-timer.C:
+		// same as the error returned by the conn read when the deadline is exceeded
+		return nil, &net.OpError{Op: "read", Net: "udp", Err: os.ErrDeadlineExceeded}
+	case msg, ok := <-c.recvCh:
+		if !ok {
+			return nil, fmt.Errorf("read from conn failed, err: %w", net.ErrClosed)
+		}
+		return msg, nil
+	}
+}
+
+// Receive waits for one more packet from the target without sending anything,
+// it is used when the target sends more than one reply for a request.
+func (c *UDPClient) Receive(ctx context.Context) ([]byte, error) {
+	if err := c.initConn(); err != nil {
+		return nil, fmt.Errorf("init udp connection failed, err: %w", err)
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if c.receiving() {
+		return c.waitReceived(ctx)
+	}
+
+	recvBuffer := make([]byte, c.bufferSize)
+
+	doneChan := make(chan error, 1)
+	recvChan := make(chan int, 1)
+	go func() {
+		err := c.conn.SetReadDeadline(time.Now().Add(c.timeout))
+		if err != nil {
+			doneChan <- fmt.Errorf("set conn read deadline failed, err: %w", err)
+			return
+		}
+
+		nRead, err := c.conn.Read(recvBuffer)
+		if err != nil {
+			doneChan <- fmt.Errorf("read from conn failed, err: %w", err)
+			return
+		}
+
+		doneChan <- nil
+		recvChan <- nRead
+	}()
+
+	select {
+	case <-ctx.Done():
+		return nil, fmt.Errorf("canceled from caller")
+	case err := <-doneChan:
+		if err != nil {
+			return nil, err
+		}
+		recvCount := <-recvChan
+		return recvBuffer[:recvCount], nil
+	}
+}
+
+// Send writes the msg to the target without waiting for a reply.
+func (c *UDPClient) Send(msg []byte) error {
+	if err := c.initConn(); err != nil {
+		return fmt.Errorf("init udp connection failed, err: %w", err)
+	}
+
+	if _, err := c.conn.Write(msg); err != nil {
+		return fmt.Errorf("write to conn failed, err: %w", err)
+	}
+	return nil
+}
+
+// StartReceiving starts a goroutine which keeps reading packets from the target,
+// it is used when the target sends packets which are not replies of a request,
+// like the SOL payload packets, or the replies of several requests in flight.
+//
+// Each received packet is passed to the handle funcs in the order they were added,
+// until one of them consumes it (returns true). The packets not consumed by any handle
+// are treated as the replies of Exchange. The handle funcs are called on the receiving goroutine.
+//
+// The returned stop func removes handle, the goroutine is stopped once no handle is left.
+// The read errors are ignored, like the ECONNREFUSED reported while the target is resetting.
+// If the conn is closed, the goroutine exits and all the handle funcs are removed.
+func (c *UDPClient) StartReceiving(handle func(msg []byte) bool) (stop func(), err error) {
+	stop, _, err = c.startReceiving(handle)
+	return stop, err
+}
+
+// startReceiving is like StartReceiving, it also returns a channel which is closed
+// once the receiving goroutine calling handle exits.
+func (c *UDPClient) startReceiving(handle func(msg []byte) bool) (stop func(), done <-chan struct{}, err error) {
+	if err := c.initConn(); err != nil {
+		return nil, nil, fmt.Errorf("init udp connection failed, err: %w", err)
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if !c.receiving() {
+		// clear the deadline possibly left by Exchange
+		if err := c.conn.SetReadDeadline(time.Time{}); err != nil {
+			return nil, nil, fmt.Errorf("set conn read deadline failed, err: %w", err)
+		}
+
+		recvCh := make(chan []byte, 16)
+		recvStop := make(chan struct{})
+		recvDone := make(chan struct{})
+		c.recvCh = recvCh
+		c.recvStop = recvStop
+		c.recvDone = recvDone
+
+		go c.receive(c.conn, recvCh, recvStop, recvDone)
+	}
+
+	h := &receiveHandler{handle: handle}
+	c.handlersMu.Lock()
+	c.handlers = append(c.handlers, h)
+	c.handlersMu.Unlock()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			c.removeHandler(h)
+		})
+	}, c.recvDone, nil
+}
+
+// receiveErrorBackoff is the wait before reading again after a read error,
+// so the receiving goroutine does not spin on a persistent error.
+const receiveErrorBackoff = 10 * time.Millisecond
+
+// receive reads the packets until it is stopped or the conn is closed.
+func (c *UDPClient) receive(conn net.Conn, recvCh chan []byte, recvStop chan struct{}, recvDone chan struct{}) {
+	defer close(recvDone)
+	defer close(recvCh)
+
+	for {
+		buf := make([]byte, c.bufferSize)
+		n, err := conn.Read(buf)
+		if errors.Is(err, net.ErrClosed) {
+			return
+		}
+		if err != nil {
+			select {
+			case <-recvStop:
+				return
+			case <-time.After(receiveErrorBackoff):
+				continue
+			}
+		}
+		if c.handle(buf[:n]) {
+			continue
+		}
+
+		select {
+		case recvCh <- buf[:n]:
+		default:
+			// nobody is waiting for so many replies, drop it
+		}
+	}
+}
+
+// receiving reports whether the receiving goroutine owns the reads of conn, c.lock must be held.
+// The state of the goroutine which exited with the conn closed is discarded,
+// so the replies are read by Exchange again.
+func (c *UDPClient) receiving() bool {
+	if c.recvCh == nil {
+		return false
+	}
+
+	select {
+	case <-c.recvDone:
+	default:
+		return true
+	}
+
+	c.handlersMu.Lock()
+	c.handlers = nil
+	c.handlersMu.Unlock()
+
+	c.recvCh = nil
+	c.recvStop = nil
+	c.recvDone = nil
+	return false
+}
+
+// handle passes the packet to the handlers, it reports whether one of them consumed it.
+func (c *UDPClient) handle(msg []byte) bool {
+	c.handlersMu.Lock()
+	defer c.handlersMu.Unlock()
+
+	for _, h := range c.handlers {
+		if h.handle(msg) {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *UDPClient) removeHandler(h *receiveHandler) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if !c.receiving() {
+		// the handle is removed with the exited goroutine
+		return
+	}
+
+	c.handlersMu.Lock()
+	removed := false
+	for i := range c.handlers {
+		if c.handlers[i] == h {
+			c.handlers = append(c.handlers[:i], c.handlers[i+1:]...)
+			removed = true
+			break
+		}
+	}
+	left := len(c.handlers)
+	c.handlersMu.Unlock()
+
+	if removed && left == 0 {
+		c.stopReceiving()
+	}
+}
+
+// StopReceiving removes all the handle funcs passed to StartReceiving,
+// stops the receiving goroutine and waits for it to exit.
+func (c *UDPClient) StopReceiving() {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.handlersMu.Lock()
+	c.handlers = nil
+	c.handlersMu.Unlock()
+
+	c.stopReceiving()
+}
+
+func (c *UDPClient) stopReceiving() {
+	if !c.receiving() {
+		return
+	}
+
+	close(c.recvStop)
+	if c.conn != nil {
+		// unblock the pending read
+		_ = c.conn.SetReadDeadline(time.Now())
+	}
+	<-c.recvDone
+
+	c.recvCh = nil
+	c.recvStop = nil
+	c.recvDone = nil
+}
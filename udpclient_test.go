@@ -0,0 +1,92 @@
+package ipmi
+
+import (
+	"bytes"
+	"context"
+	"net"
+	"testing"
+	"time"
+)
+
+// newEchoServer starts a UDP server sending back the received packets.
+func newEchoServer(t *testing.T) *net.UDPConn {
+	t.Helper()
+
+	conn, err := net.ListenUDP("udp", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	if err != nil {
+		t.Fatalf("ListenUDP failed, err: %s", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	go func() {
+		buf := make([]byte, 64)
+		for {
+			n, addr, err := conn.ReadFromUDP(buf)
+			if err != nil {
+				return
+			}
+			_, _ = conn.WriteToUDP(buf[:n], addr)
+		}
+	}()
+	return conn
+}
+
+func TestUDPClient_Receiving(t *testing.T) {
+	server := newEchoServer(t)
+	addr := server.LocalAddr().(*net.UDPAddr)
+
+	c := NewUDPClient(addr.IP.String(), addr.Port).SetTimeout(time.Second).SetBufferSize(64)
+	defer c.Close()
+
+	exchange := func(msg string) {
+		t.Helper()
+		recv, err := c.Exchange(context.Background(), bytes.NewReader([]byte(msg)))
+		if err != nil {
+			t.Fatalf("Exchange failed, err: %s", err)
+		}
+		if string(recv) != msg {
+			t.Errorf("Exchange returned %q, want %q", recv, msg)
+		}
+	}
+
+	_, done, err := c.startReceiving(func(msg []byte) bool {
+		return false
+	})
+	if err != nil {
+		t.Fatalf("startReceiving failed, err: %s", err)
+	}
+	exchange("received by the goroutine")
+
+	// the refused packets do not stop the receiving goroutine
+	if err := server.Close(); err != nil {
+		t.Fatalf("Close failed, err: %s", err)
+	}
+	if err := c.Send([]byte("refused")); err != nil {
+		t.Fatalf("Send failed, err: %s", err)
+	}
+	select {
+	case <-done:
+		t.Fatalf("receiving goroutine exited after a refused packet")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	// the replies are read by Exchange again once the conn is closed
+	server = newEchoServer(t)
+	addr = server.LocalAddr().(*net.UDPAddr)
+	if err := c.Close(); err != nil {
+		t.Fatalf("Close failed, err: %s", err)
+	}
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatalf("receiving goroutine not exited after the conn is closed")
+	}
+	c.Port = addr.Port
+	exchange("read by Exchange")
+	c.lock.Lock()
+	receiving := c.receiving()
+	c.lock.Unlock()
+	if receiving {
+		t.Errorf("receiving goroutine not discarded after it exited")
+	}
+}